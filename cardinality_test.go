@@ -0,0 +1,97 @@
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+func TestObjectCardinality(t *testing.T) {
+	mapping := IndexMapping{
+		IndexName: "orders",
+		Properties: map[string]*Field{
+			"customer": {Name: "customer", Type: FieldTypeObject, Properties: map[string]*Field{
+				"name": {Name: "name", Type: FieldTypeKeyword},
+			}},
+			"lines": {Name: "lines", Type: FieldTypeNested, Properties: map[string]*Field{
+				"sku": {Name: "sku", Type: FieldTypeKeyword},
+			}},
+			"tags": {Name: "tags", Type: FieldTypeObject, Properties: map[string]*Field{
+				"label": {Name: "label", Type: FieldTypeKeyword},
+			}},
+		},
+	}
+	tagsMany := map[string]Cardinality{"tags": CardinalityMany}
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("orders", &QueryConfig{Mapping: mapping, ObjectCardinality: tagsMany})))
+		assertContains(t, sdl, "customer: CustomerObject\n", "lines: [LinesObject]", "tags: [TagsObject]")
+	})
+
+	t.Run("does not modify mapping", func(t *testing.T) {
+		queryConfig := &QueryConfig{Mapping: mapping, ObjectCardinality: tagsMany}
+
+		queryMapping := queryConfig.GetMapping()
+		if !queryMapping.GetField("tags").isList() {
+			t.Error("Expected override to apply to the query mapping")
+		}
+		if queryConfig.Mapping.GetField("tags").Cardinality != "" {
+			t.Error("Expected configured mapping to be left unchanged")
+		}
+	})
+
+	t.Run("normalize", func(t *testing.T) {
+		overridden := mapping.withCardinality(tagsMany)
+
+		doc := map[string]any{
+			"customer": []any{map[string]any{"name": "Alice"}},
+			"lines":    map[string]any{"sku": "A"},
+			"tags":     map[string]any{"label": "vip"},
+			"other":    []any{map[string]any{"name": "Bob"}},
+		}
+		normalizeObjectCardinality(doc, &overridden)
+
+		expected := map[string]any{
+			"customer": map[string]any{"name": "Alice"},
+			"lines":    []any{map[string]any{"sku": "A"}},
+			"tags":     []any{map[string]any{"label": "vip"}},
+			"other":    []any{map[string]any{"name": "Bob"}},
+		}
+		if !reflect.DeepEqual(doc, expected) {
+			t.Errorf("Expected %v, got %v", expected, doc)
+		}
+	})
+
+	t.Run("several objects", func(t *testing.T) {
+		backend := &recordingBackend{hits: []map[string]any{
+			{"customer": []any{map[string]any{"name": "Alice"}, map[string]any{"name": "Bob"}}},
+		}}
+		api, err := New(backend, NewConfig(WithQuery("orders", &QueryConfig{Mapping: mapping, Features: []reveald.Feature{&mockWrapperFeature{}}})))
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+
+		// A single object field holding several objects is an error, not its first object
+		result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ orders { hits { customer { name } } } }`})
+		if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, "holds 2 objects") {
+			t.Errorf("Expected an error for several objects, got %v", result)
+		}
+	})
+
+	invalid := map[string]map[string]Cardinality{
+		"unknown field":       {"missing": CardinalityMany},
+		"not an object":       {"customer.name": CardinalityMany},
+		"unknown cardinality": {"tags": "several"},
+	}
+	for name, overrides := range invalid {
+		t.Run("invalid "+name, func(t *testing.T) {
+			config := NewConfig(WithQuery("orders", &QueryConfig{Mapping: mapping, ObjectCardinality: overrides}))
+			if _, err := GenerateSchemaSDL(config); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
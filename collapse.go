@@ -0,0 +1,306 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// collapsedHitsName is the name of the inner hits section holding the documents of a collapse group
+	collapsedHitsName = "_collapsed"
+
+	// collapseGroupsAggregation is the name of the cardinality aggregation counting the collapse groups
+	collapseGroupsAggregation = "_collapseGroups"
+)
+
+// collapseEnumName returns the name of the enum of a query's collapse fields
+func collapseEnumName(queryName string, queryConfig *QueryConfig) string {
+	return strings.TrimSuffix(resultTypeName(queryName, queryConfig), "Result") + "CollapseField"
+}
+
+// isCollapsibleField reports whether hits can be collapsed on a field
+// Text fields are collapsed on their keyword multi-field
+func isCollapsibleField(field *Field) bool {
+	switch field.Type {
+	case FieldTypeKeyword, FieldTypeConstantKeyword:
+		return true
+	}
+	if isTextFieldType(field.Type) {
+		_, ok := keywordMultiField(field)
+		return ok
+	}
+	return false
+}
+
+// validateCollapseFields checks that the query's collapse fields are keyword fields
+func validateCollapseFields(paths []string, mapping *IndexMapping) error {
+	for _, path := range paths {
+		field := mapping.GetField(path)
+		if field == nil {
+			return fmt.Errorf("collapse field %s not found", path)
+		}
+		if !isCollapsibleField(field) {
+			return fmt.Errorf("collapse field %s must be a keyword field, got %s", path, field.Type)
+		}
+	}
+	return nil
+}
+
+// addCollapseArgument adds the collapse argument and its enum of allowed fields to a query
+func (sg *SchemaGenerator) addCollapseArgument(args graphql.FieldConfigArgument, queryName string, queryConfig *QueryConfig) error {
+	if len(queryConfig.CollapseFields) == 0 {
+		return nil
+	}
+
+	collapseEnum, err := sg.enumType(&Field{
+		EnumTypeName: collapseEnumName(queryName, queryConfig),
+		EnumValues:   queryConfig.CollapseFields,
+	})
+	if err != nil {
+		return err
+	}
+
+	sg.names.reserve(argumentScope(queryName), "collapse")
+	args["collapse"] = &graphql.ArgumentConfig{
+		Type:        collapseEnum,
+		Description: "Return only the top hit of each group of documents with the same value of this field",
+	}
+	sg.names.reserve(argumentScope(queryName), "after")
+	args["after"] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Return the groups after this cursor of a collapsed search (from the cursor result field)",
+	}
+	return nil
+}
+
+// addCollapseFields adds the collapsedHits field, returning the documents of a hit's collapse
+// group, to the document types of the queries with collapse fields
+func (sg *SchemaGenerator) addCollapseFields() {
+	added := make(map[string]bool)
+	for _, queryName := range sortedKeys(sg.config.Queries) {
+		queryConfig := sg.config.Queries[queryName]
+		if len(queryConfig.CollapseFields) == 0 || queryConfig.HitsType != nil || queryConfig.isMultiIndex() {
+			continue
+		}
+
+		mapping := queryConfig.GetMapping()
+		docType, ok := sg.typeCache[queryDocumentTypeName(queryConfig, &mapping)]
+		if !ok || added[docType.Name()] {
+			continue
+		}
+		added[docType.Name()] = true
+
+		sg.names.reserve(docType.Name(), "collapsedHits")
+		docType.AddFieldConfig("collapsedHits", &graphql.Field{
+			Type:        graphql.NewList(docType),
+			Description: "Documents of the hit's collapse group (only set when the query is collapsed)",
+			Args: graphql.FieldConfigArgument{
+				"size": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: defaultInnerHitsSize,
+					Description:  "Maximum number of documents",
+				},
+				"sort": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.String),
+					Description: "Sort of the documents, as \"<field>-asc\" or \"<field>-desc\" (e.g., \"createdAt-desc\")",
+				},
+			},
+			Resolve: collapsedHitsResolver(&mapping),
+		})
+	}
+}
+
+// collapsedHitsResolver returns the documents of a hit's collapse group
+func collapsedHitsResolver(mapping *IndexMapping) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		innerHits, ok := source[innerHitsKey].(map[string][]map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		docs, ok := innerHits[collapsedHitsName]
+		if !ok {
+			return nil, nil
+		}
+
+		if size, ok := p.Args["size"].(int); ok && size >= 0 && len(docs) > size {
+			docs = docs[:size]
+		}
+		for _, doc := range docs {
+			normalizeObjectCardinality(doc, mapping)
+		}
+		return docs, nil
+	}
+}
+
+// collapseOptions describe how the hits of a request are collapsed
+type collapseOptions struct {
+	field       string             // ES field the hits are collapsed on
+	innerHits   *innerHitsOptions  // Documents of each group, when collapsedHits is selected
+	countGroups bool               // Whether totalGroups is selected
+	paged       bool               // Whether the groups are paged with a cursor (after is set or cursor is selected)
+	after       []types.FieldValue // Sort values of the last group of the previous page
+}
+
+// collapseRequest returns the collapse options of a request, or nil when it is not collapsed
+func (rb *ResolverBuilder) collapseRequest(params graphql.ResolveParams, mapping *IndexMapping) (*collapseOptions, error) {
+	after, _ := params.Args["after"].(string)
+	field, ok := params.Args["collapse"].(string)
+	if !ok || field == "" {
+		if after != "" {
+			return nil, fmt.Errorf("after needs a collapse field")
+		}
+		return nil, nil
+	}
+
+	options := &collapseOptions{
+		field:       exactFieldPath(field, mapping),
+		countGroups: selectsField(params.Info, "totalGroups"),
+		paged:       after != "" || selectsField(params.Info, "cursor"),
+	}
+	if after != "" {
+		values, err := decodeCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		options.after = values
+	}
+	var err error
+	forSelectedFields(params.Info, "hits", func(_ []string, selection *ast.Field) {
+		if selection.Name.Value != "collapsedHits" {
+			return
+		}
+		selected, selectionErr := readInnerHitsOptions(selection, params.Info.VariableValues, "", mapping)
+		if selectionErr != nil {
+			if err == nil {
+				err = selectionErr
+			}
+			return
+		}
+		// The largest size when selected several times (e.g., in fragments)
+		if options.innerHits == nil || options.innerHits.size < selected.size {
+			options.innerHits = &selected
+		}
+	})
+	return options, err
+}
+
+// apply collapses the hits of a search request and adds the group count aggregation
+// Paged groups are sorted on the collapse field, the only sort ES supports for search_after on
+// collapsed hits, so the request must not be sorted otherwise
+func (co *collapseOptions) apply(req *search.Request) error {
+	if co.paged {
+		switch {
+		case len(req.Sort) == 0:
+			order := sortorder.Asc
+			req.Sort = []types.SortCombinations{types.SortOptions{
+				SortOptions: map[string]types.FieldSort{co.field: {Order: &order}},
+			}}
+		case !sortedOn(req.Sort, co.field):
+			return fmt.Errorf("cursor pagination of collapsed hits needs the hits sorted on the collapse field %s only", co.field)
+		}
+		if co.after != nil {
+			if req.From != nil && *req.From > 0 {
+				return fmt.Errorf("after cannot be combined with offset")
+			}
+			req.SearchAfter = co.after
+		}
+	}
+
+	collapse := &types.FieldCollapse{Field: co.field}
+	if co.innerHits != nil {
+		name := collapsedHitsName
+		size := co.innerHits.size
+		innerHits := types.InnerHits{Name: &name, Size: &size}
+		for _, option := range co.innerHits.sort {
+			field, order, _ := parseSortValue(option)
+			innerHits.Sort = append(innerHits.Sort, types.SortOptions{
+				SortOptions: map[string]types.FieldSort{field: {Order: &order}},
+			})
+		}
+		collapse.InnerHits = []types.InnerHits{innerHits}
+	}
+	req.Collapse = collapse
+
+	if co.countGroups {
+		if req.Aggregations == nil {
+			req.Aggregations = make(map[string]types.Aggregations)
+		}
+		field := co.field
+		req.Aggregations[collapseGroupsAggregation] = types.Aggregations{
+			Cardinality: &types.CardinalityAggregation{Field: &field},
+		}
+	}
+	return nil
+}
+
+// sortedOn reports whether a request is sorted on a field only
+func sortedOn(sort []types.SortCombinations, field string) bool {
+	if len(sort) != 1 {
+		return false
+	}
+	switch s := sort[0].(type) {
+	case string:
+		return s == field
+	case types.SortOptions:
+		_, ok := s.SortOptions[field]
+		return ok && len(s.SortOptions) == 1
+	case *types.SortOptions:
+		_, ok := s.SortOptions[field]
+		return ok && len(s.SortOptions) == 1
+	}
+	return false
+}
+
+// cursor returns the cursor after the last group of a response, when the groups are paged
+func (co *collapseOptions) cursor(resp *search.Response) *string {
+	if !co.paged || len(resp.Hits.Hits) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(resp.Hits.Hits[len(resp.Hits.Hits)-1].Sort)
+	if err != nil {
+		return nil
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+	return &cursor
+}
+
+// decodeCursor returns the sort values of a cursor
+func decodeCursor(cursor string) ([]types.FieldValue, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid after cursor %q", cursor)
+	}
+	var values []types.FieldValue
+	if err := json.Unmarshal(data, &values); err != nil || len(values) == 0 {
+		return nil, fmt.Errorf("invalid after cursor %q", cursor)
+	}
+	return values, nil
+}
+
+// takeGroupCount removes the group count aggregation from a response and returns its value
+// The count is approximate for large numbers of groups (ES cardinality)
+func takeGroupCount(resp *search.Response) (int64, bool) {
+	agg, ok := resp.Aggregations[collapseGroupsAggregation]
+	if !ok {
+		return 0, false
+	}
+	delete(resp.Aggregations, collapseGroupsAggregation)
+
+	cardinality, ok := agg.(*types.CardinalityAggregate)
+	if !ok {
+		return 0, false
+	}
+	return cardinality.Value, true
+}
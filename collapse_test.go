@@ -0,0 +1,197 @@
+package graphql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+// leadAggregationFeature aggregates the hits by lead like a filter feature
+type leadAggregationFeature struct{}
+
+func (f *leadAggregationFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	field := "leadId"
+	builder.Aggregation("leads", types.Aggregations{Terms: &types.TermsAggregation{Field: &field}})
+	return next(builder)
+}
+
+// createdAtSortFeature sorts the hits by creation time like a sorting feature
+type createdAtSortFeature struct{}
+
+func (f *createdAtSortFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	builder.Selection().Update(reveald.WithSort("createdAt", sortorder.Desc))
+	return next(builder)
+}
+
+func TestCollapse(t *testing.T) {
+	queryConfig := &QueryConfig{
+		Mapping: IndexMapping{
+			IndexName: "iterations",
+			Properties: map[string]*Field{
+				"id":        {Name: "id", Type: FieldTypeKeyword},
+				"leadId":    {Name: "leadId", Type: FieldTypeKeyword},
+				"createdAt": {Name: "createdAt", Type: FieldTypeDate},
+				"title": {Name: "title", Type: FieldTypeText, Fields: map[string]*Field{
+					"keyword": {Name: "keyword", Type: FieldTypeKeyword},
+				}},
+			},
+		},
+		Features:              []reveald.Feature{&mockWrapperFeature{}},
+		CollapseFields:        []string{"leadId", "title"},
+		EnableElasticQuerying: true,
+	}
+
+	// Two collapsed hits and the group count
+	response := `"hits": {"total": {"value": 5, "relation": "eq"}, "hits": [
+		{"_index": "iterations", "_id": "i3", "_source": {"leadId": "l1"},
+		 "inner_hits": {"_collapsed": {"hits": {"hits": [
+			{"_index": "iterations", "_id": "i3", "_source": {"leadId": "l1"}},
+			{"_index": "iterations", "_id": "i2", "_source": {"leadId": "l1"}}
+		 ]}}}},
+		{"_index": "iterations", "_id": "i5", "_source": {"leadId": "l2"}}
+	]},
+	"aggregations": {"cardinality#_collapseGroups": {"value": 2}}`
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("iterations", queryConfig)))
+		assertContains(t, sdl, "collapse: IterationsCollapseField", "enum IterationsCollapseField", "LEAD_ID", "totalGroups: Long", "collapsedHits(", "): [IterationsDocument]")
+
+		notKeyword := *queryConfig
+		notKeyword.CollapseFields = []string{"createdAt"}
+		if _, err := GenerateSchemaSDL(NewConfig(WithQuery("iterations", &notKeyword))); err == nil {
+			t.Error("Expected an error for a field that is not a keyword")
+		}
+	})
+
+	t.Run("feature query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(response))
+		backend := &recordingBackend{hits: make([]map[string]any, 5)}
+		api := es.newAPI(t, backend, WithQuery("iterations", queryConfig))
+
+		data := runQuery(t, api, `{ iterations(collapse: TITLE) { totalCount totalGroups hits { id collapsedHits(size: 2, sort: ["createdAt-desc"]) { id } } } }`)
+
+		// The features' request is sent once, collapsed, instead of through the backend
+		if backend.search != nil {
+			t.Errorf("Expected no backend search, got %v", backend.search)
+		}
+		if len(es.requests()) != 1 {
+			t.Fatalf("Expected a single search, got %v", es.requests())
+		}
+		assertContains(t, es.requests()[0],
+			`"collapse":{"field":"title.keyword","inner_hits":[{"name":"_collapsed","size":2,"sort":[{"createdAt":{"order":"desc"}}]}]}`,
+			`"_collapseGroups":{"cardinality":{"field":"title.keyword"}}`,
+		)
+
+		iterations := data["iterations"].(map[string]any)
+		if fmt.Sprint(iterations["totalCount"], iterations["totalGroups"]) != "5 2" {
+			t.Errorf("Expected 5 documents in 2 groups, got %v and %v", iterations["totalCount"], iterations["totalGroups"])
+		}
+		hits := iterations["hits"].([]any)
+		if len(hits) != 2 {
+			t.Fatalf("Expected one hit per group, got %v", hits)
+		}
+		if collapsed := hits[0].(map[string]any)["collapsedHits"].([]any); len(collapsed) != 2 || collapsed[1].(map[string]any)["id"] != "i2" {
+			t.Errorf("Expected the documents of the group, got %v", collapsed)
+		}
+
+		// Without an ES client the backend cannot collapse
+		api, err := New(backend, NewConfig(WithQuery("iterations", queryConfig)))
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+		result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ iterations(collapse: LEAD_ID) { totalCount } }`})
+		if len(result.Errors) == 0 {
+			t.Error("Expected an error without an ES client")
+		}
+	})
+
+	t.Run("typed query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(response))
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("iterations", queryConfig))
+
+		data := runQuery(t, api, `{ iterations(collapse: LEAD_ID, query: {term: {field: "leadId", value: "l1"}}) { totalCount hits { id } } }`)
+		assertContains(t, es.requests()[0], `"collapse":{"field":"leadId"}`)
+
+		// Groups are only counted when totalGroups is selected
+		assertNotContains(t, es.requests()[0], "_collapseGroups")
+
+		iterations := data["iterations"].(map[string]any)
+		if fmt.Sprint(iterations["totalCount"]) != "5" || len(iterations["hits"].([]any)) != 2 {
+			t.Errorf("Expected 2 collapsed hits of 5 documents, got %v", iterations)
+		}
+	})
+
+	t.Run("feature aggregations", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(response))
+		backend := &recordingBackend{hits: make([]map[string]any, 5)}
+		withAggregation := *queryConfig
+		withAggregation.Features = []reveald.Feature{&mockWrapperFeature{}, &leadAggregationFeature{}}
+		api := es.newAPI(t, backend, WithQuery("iterations", &withAggregation))
+
+		data := runQuery(t, api, `{ iterations(collapse: LEAD_ID) { totalCount hits { id } } }`)
+
+		// The features' aggregations are computed by the backend, the collapsed hits by the typed client
+		if backend.search == nil || *backend.search.Size != 0 || backend.search.Aggregations["leads"].Terms == nil {
+			t.Fatalf("Expected the aggregations from the backend without hits, got %v", backend.search)
+		}
+		assertContains(t, es.requests()[0], `"collapse":{"field":"leadId"}`)
+		assertNotContains(t, es.requests()[0], `"leads"`)
+
+		iterations := data["iterations"].(map[string]any)
+		if fmt.Sprint(iterations["totalCount"]) != "5" || len(iterations["hits"].([]any)) != 2 {
+			t.Errorf("Expected 2 collapsed hits of 5 documents, got %v", iterations)
+		}
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 5, "relation": "eq"}, "hits": [
+			{"_index": "iterations", "_id": "i3", "_source": {"leadId": "l1"}, "sort": ["l1"]},
+			{"_index": "iterations", "_id": "i5", "_source": {"leadId": "l2"}, "sort": ["l2"]}
+		]}`))
+		paged := *queryConfig
+		paged.EnablePagination = true
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("iterations", &paged))
+
+		// Paged groups are sorted on the collapse field
+		data := runQuery(t, api, `{ iterations(collapse: LEAD_ID, limit: 2) { cursor hits { id } } }`)
+		assertContains(t, es.requests()[0], `"sort":[{"leadId":{"order":"asc"}}]`)
+		cursor, ok := data["iterations"].(map[string]any)["cursor"].(string)
+		if !ok {
+			t.Fatalf("Expected a cursor, got %v", data)
+		}
+
+		// The next page starts after the cursor, on feature-based and typed queries
+		for _, query := range []string{
+			`{ iterations(collapse: LEAD_ID, after: "` + cursor + `") { hits { id } } }`,
+			`{ iterations(collapse: LEAD_ID, after: "` + cursor + `", query: {term: {field: "title", value: "x"}}) { hits { id } } }`,
+		} {
+			runQuery(t, api, query)
+			assertContains(t, es.requests()[len(es.requests())-1], `"search_after":["l2"]`)
+		}
+
+		tests := map[string]string{
+			"after without collapse": `{ iterations(after: "` + cursor + `") { totalCount } }`,
+			"after with offset":      `{ iterations(collapse: LEAD_ID, after: "` + cursor + `", offset: 10, query: {term: {field: "title", value: "x"}}) { totalCount } }`,
+			"invalid cursor":         `{ iterations(collapse: LEAD_ID, after: "???") { totalCount } }`,
+		}
+		for name, query := range tests {
+			t.Run(name, func(t *testing.T) {
+				if result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: query}); len(result.Errors) == 0 {
+					t.Error("Expected an error")
+				}
+			})
+		}
+
+		// ES only pages collapsed hits sorted on the collapse field
+		sorted := *queryConfig
+		sorted.Features = []reveald.Feature{&mockWrapperFeature{}, &createdAtSortFeature{}}
+		api = es.newAPI(t, &recordingBackend{}, WithQuery("iterations", &sorted))
+		if result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ iterations(collapse: LEAD_ID) { cursor } }`}); len(result.Errors) == 0 {
+			t.Error("Expected an error for paged groups sorted on another field")
+		}
+	})
+}
@@ -1,7 +1,10 @@
 package graphql
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/graphql-go/graphql"
@@ -86,6 +89,9 @@ type Config struct {
 	// RawQueries maps GraphQL query names to raw (backend-agnostic) query configurations
 	RawQueries map[string]*RawQueryConfig
 
+	// SuggestQueries maps GraphQL query names to suggestion (autocomplete) query configurations
+	SuggestQueries map[string]*SuggestQueryConfig
+
 	// EnableFederation enables Apollo Federation v2 support
 	// When enabled:
 	// - Adds @shareable, @link, and @key directives to schema
@@ -122,6 +128,18 @@ type Config struct {
 
 	// CustomTypesWithKeys defines custom types with entity keys for Federation
 	CustomTypesWithKeys []CustomTypeWithKeys
+
+	// UseIntForLong exposes long/unsigned_long fields and counts (totalCount, doc_count)
+	// as Int instead of the Long scalar
+	// Only for backwards compatibility: Int is 32-bit and overflows for large values
+	// Default: false
+	UseIntForLong bool
+
+	// NamingStrategy converts ES field and aggregation names to GraphQL names
+	// Applies to document fields, nested objects, filter arguments, aggregation fields and
+	// sort enum values. Use CamelCaseNaming for snake_case indices or a custom function.
+	// Default: nil (AsIsNaming)
+	NamingStrategy NamingStrategy
 }
 
 // RootQueryBuilder is a function that builds a root query based on the HTTP request
@@ -134,11 +152,23 @@ type RootQueryBuilder func(r *http.Request) (*types.Query, error)
 // Used for feature-based reveald queries
 type RequestInterceptor func(httpReq *http.Request, revealdReq *reveald.Request) error
 
+// IndexResolver is a function that picks the indices to search based on the HTTP request
+// and the GraphQL arguments (for _entities, the representation fields)
+// This allows routing to per-tenant or time-based indices at request time
+// Used by feature-based, typed, precompiled and entity queries
+type IndexResolver func(r *http.Request, args map[string]any) ([]string, error)
+
 // QueryConfig defines configuration for a single GraphQL query
 type QueryConfig struct {
 	// Mapping is the Elasticsearch index mapping for this query
 	Mapping IndexMapping
 
+	// Mappings are the mappings of additional indices searched by this query
+	// When the query spans several indices, the hits field returns an interface
+	// with the fields shared by all mappings, implemented by one document type
+	// per index. Filter arguments and aggregations are generated from the merged mappings.
+	Mappings []IndexMapping
+
 	// Features are the reveald features to apply to this query
 	Features []reveald.Feature
 
@@ -165,6 +195,74 @@ type QueryConfig struct {
 	// - Custom scalar types
 	FieldTypeOverrides map[string]graphql.Output
 
+	// ObjectCardinality overrides whether object fields are single objects or lists, keyed by field path
+	// Object fields are single objects and nested fields lists by default. Use CardinalityMany
+	// for object fields that hold arrays of objects, or InferCardinality to detect them from samples.
+	// Example: map[string]Cardinality{"tags": CardinalityMany}
+	ObjectCardinality map[string]Cardinality
+
+	// FieldAliases renames mapping fields in the GraphQL schema, keyed by field path
+	// Aliases take precedence over the naming strategy and apply to document fields,
+	// nested object fields, filter arguments, aggregation fields and sort enum values
+	// Example: map[string]string{"cust_nm": "customerName", "customer.addr_1": "street"}
+	FieldAliases map[string]string
+
+	// MultiFields exposes multi-fields of the mapping under their own name, keyed by path
+	// Each multi-field becomes a document field, a filter argument, a sort option
+	// ("name_keyword_asc") and, with EnableAggregations, an aggregation field
+	// Example: []string{"name.keyword", "title.sortable"} → name_keyword, title_sortable
+	MultiFields []string
+
+	// FieldEnums exposes keyword fields with a known set of values as GraphQL enums, keyed by field path
+	// Example: map[string]FieldEnum{"status": {Values: []string{"open", "won", "lost"}}}
+	FieldEnums map[string]FieldEnum
+
+	// RuntimeFields are fields computed at query time by Painless scripts, keyed by field name
+	// They are added to the document type, filter arguments, sort options ("name-asc") and,
+	// with EnableAggregations, aggregation fields, and sent to ES only when used
+	// Example: map[string]RuntimeField{"priceInclVat": {Type: FieldTypeDouble, Script: "emit(doc['price'].value * 1.25)"}}
+	RuntimeFields map[string]RuntimeField
+
+	// ScriptFields are values computed by Painless scripts for each hit, keyed by field name
+	// They are only added to the document type and sent to ES when selected
+	// Example: map[string]ScriptField{"fullName": {Type: FieldTypeKeyword, Script: "doc['first'].value + ' ' + doc['last'].value"}}
+	ScriptFields map[string]ScriptField
+
+	// InnerHits adds a matched field to the hits for each nested path, returning the nested
+	// objects matching the query's nested filters (ES inner_hits, requested when the field is selected)
+	// Example: []string{"processes"} → matchedProcesses(size: 3, sort: ["startedAt-desc"])
+	InnerHits []string
+
+	// CollapseFields are the keyword fields the hits can be collapsed on with the collapse argument,
+	// returning the top hit of each group (e.g., the latest iteration per lead). Collapsed queries
+	// get a totalGroups result field, a collapsedHits field with the documents of each group and
+	// cursor pagination (the cursor result field and the after argument).
+	// Feature-based queries need an ES client (WithESClient) to be collapsed
+	// Example: []string{"leadId"} → leads(collapse: LEAD_ID, sort: createdAt_desc)
+	CollapseFields []string
+
+	// SpellCheck adds a suggestions result field with "did you mean" corrections of the query's
+	// search text (its match, multi_match and query_string queries) from the ES term or phrase
+	// suggester over text fields. Feature-based queries need an ES client (WithESClient)
+	// Example: &SpellCheck{Fields: []string{"title"}} → suggestions { text highlighted score }
+	SpellCheck *SpellCheck
+
+	// GeoFields adds geoDistance, geoBoundingBox and geoPolygon filter arguments and a sortByDistance
+	// argument on geo_point fields. Hits sorted by distance get their distance in a distance field;
+	// feature-based queries need an ES client (WithESClient) to sort by distance
+	// Example: []string{"location"} → dealers(geoDistance: {field: "location", origin: {lat: 59.3, lon: 18.1}, distance: "50km"})
+	GeoFields []string
+
+	// KNN adds a knn argument ranking the hits by similarity to a query vector on dense_vector fields
+	// The vector is given in the argument or computed from text by the Embedder. Combined with a
+	// text query, the scores of both are summed (hybrid search); filters apply before the search
+	// Example: &KNN{Fields: []string{"embedding"}, Embedder: embedder} → products(knn: {text: "red shoes"})
+	KNN *KNN
+
+	// Relations add fields resolving documents of other queries to the document type
+	// Example: []Relation{{FieldName: "customer", SourceField: "customerId", TargetQuery: "customers", TargetField: "id"}}
+	Relations []Relation
+
 	// AggregationFields specifies which fields should have aggregations in the schema
 	// If nil or empty, no aggregation fields will be generated
 	AggregationFields []string
@@ -186,6 +284,11 @@ type QueryConfig struct {
 	// Used for feature-based queries to inject dynamic parameters
 	RequestInterceptor RequestInterceptor
 
+	// IndexResolver picks the indices to search per request
+	// When not set, the indices of Mapping and Mappings are searched
+	// The schema is still generated from the configured mappings
+	IndexResolver IndexResolver
+
 	// EntityKeyFields specifies the fields to use as entity keys for Apollo Federation
 	// Overrides the IndexMapping.EntityKeyFields for this specific query
 	// Each element represents one @key directive with space-separated field names
@@ -212,6 +315,90 @@ type QueryConfig struct {
 	ResultTypeName string
 }
 
+// indexMappings returns the mappings of all indices searched by this query
+func (qc *QueryConfig) indexMappings() []IndexMapping {
+	var mappings []IndexMapping
+	seen := make(map[string]bool)
+	for _, mapping := range append([]IndexMapping{qc.Mapping}, qc.Mappings...) {
+		if mapping.IndexName == "" || seen[mapping.IndexName] {
+			continue
+		}
+		seen[mapping.IndexName] = true
+		mappings = append(mappings, qc.applyOverrides(mapping))
+	}
+	return mappings
+}
+
+// GetIndices returns all indices searched by this query
+func (qc *QueryConfig) GetIndices() []string {
+	var indices []string
+	for _, mapping := range qc.indexMappings() {
+		indices = append(indices, mapping.IndexName)
+	}
+	return indices
+}
+
+// GetMapping returns the mapping used for filter arguments and aggregations,
+// merging Mapping with any additional Mappings
+func (qc *QueryConfig) GetMapping() IndexMapping {
+	if len(qc.Mappings) == 0 {
+		return qc.applyOverrides(qc.Mapping)
+	}
+
+	indexName := qc.Mapping.IndexName
+	if indexName == "" {
+		indexName = qc.Mappings[0].IndexName
+	}
+
+	merged := MergeMappings(indexName, append([]IndexMapping{qc.Mapping}, qc.Mappings...)...)
+	return qc.applyOverrides(merged)
+}
+
+// applyOverrides applies the query's cardinality overrides, field aliases and enums to a mapping
+func (qc *QueryConfig) applyOverrides(mapping IndexMapping) IndexMapping {
+	return mapping.withCardinality(qc.ObjectCardinality).withFieldAliases(qc.FieldAliases).withFieldEnums(qc.FieldEnums)
+}
+
+// documentTypeOptions describes the options of the query that shape its document type
+// Queries sharing a document type must agree on them
+func (qc *QueryConfig) documentTypeOptions() string {
+	var options []string
+	for _, path := range sortedKeys(qc.FieldAliases) {
+		options = append(options, "alias "+path+"="+qc.FieldAliases[path])
+	}
+	for _, name := range sortedKeys(qc.RuntimeFields) {
+		rf := qc.RuntimeFields[name]
+		options = append(options, fmt.Sprintf("runtime %s %s %q", name, rf.Type, rf.Description))
+	}
+	for _, name := range sortedKeys(qc.ScriptFields) {
+		sf := qc.ScriptFields[name]
+		options = append(options, fmt.Sprintf("script %s %s %q", name, sf.Type, sf.Description))
+	}
+	for _, path := range slices.Sorted(slices.Values(qc.InnerHits)) {
+		options = append(options, "innerHits "+path)
+	}
+	return strings.Join(options, ";")
+}
+
+// features returns the reveald features of the query, including the feature
+// handling the exposed multi-fields
+func (qc *QueryConfig) features(mapping *IndexMapping) ([]reveald.Feature, error) {
+	if len(qc.MultiFields) == 0 {
+		return qc.Features, nil
+	}
+
+	multiFields, err := newMultiFieldFeature(qc.MultiFields, mapping, qc.EnableAggregations)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]reveald.Feature{}, qc.Features...), multiFields), nil
+}
+
+// isMultiIndex reports whether this query searches indices with different mappings
+func (qc *QueryConfig) isMultiIndex() bool {
+	return len(qc.indexMappings()) > 1
+}
+
 // FieldFilter defines which fields to include or exclude
 type FieldFilter struct {
 	// Include lists fields to include (if empty, all fields are included)
@@ -231,6 +418,20 @@ func WithEnableFederation() ConfigOption {
 	}
 }
 
+// WithIntForLong keeps Int for long fields and counts instead of the Long scalar
+func WithIntForLong() ConfigOption {
+	return func(c *Config) {
+		c.UseIntForLong = true
+	}
+}
+
+// WithNamingStrategy sets the strategy used to derive GraphQL names from ES names
+func WithNamingStrategy(strategy NamingStrategy) ConfigOption {
+	return func(c *Config) {
+		c.NamingStrategy = strategy
+	}
+}
+
 // WithQueryNamespace sets the query namespace and optionally extends it
 // Examples:
 //
@@ -273,6 +474,16 @@ func WithRawQuery(name string, queryConfig *RawQueryConfig) ConfigOption {
 	}
 }
 
+// WithSuggestQuery adds a suggestion (autocomplete) query
+func WithSuggestQuery(name string, queryConfig *SuggestQueryConfig) ConfigOption {
+	return func(c *Config) {
+		if c.SuggestQueries == nil {
+			c.SuggestQueries = make(map[string]*SuggestQueryConfig)
+		}
+		c.SuggestQueries[name] = queryConfig
+	}
+}
+
 // WithTypeExtension adds custom fields to a generated GraphQL type
 // Example: Add "reviews" field to ProductDocument type
 func WithTypeExtension(typeName string, fields []FieldExtension) ConfigOption {
@@ -312,6 +523,7 @@ func NewConfig(opts ...ConfigOption) *Config {
 		Queries:            make(map[string]*QueryConfig),
 		PrecompiledQueries: make(map[string]*PrecompiledQueryConfig),
 		RawQueries:         make(map[string]*RawQueryConfig),
+		SuggestQueries:     make(map[string]*SuggestQueryConfig),
 	}
 
 	// Apply options
@@ -338,6 +550,14 @@ func (c *Config) AddPrecompiledQuery(name string, config *PrecompiledQueryConfig
 	c.PrecompiledQueries[name] = config
 }
 
+// AddSuggestQuery adds a suggestion query configuration to the config
+func (c *Config) AddSuggestQuery(name string, config *SuggestQueryConfig) {
+	if c.SuggestQueries == nil {
+		c.SuggestQueries = make(map[string]*SuggestQueryConfig)
+	}
+	c.SuggestQueries[name] = config
+}
+
 // AddTypeExtension adds custom fields to a generated GraphQL type
 func (c *Config) AddTypeExtension(typeName string, fields []FieldExtension) {
 	c.TypeExtensions = append(c.TypeExtensions, TypeExtension{
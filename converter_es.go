@@ -83,6 +83,21 @@ func convertQueryInput(input *ESQueryInput) (*types.Query, error) {
 		fieldsSet++
 	}
 
+	if input.DateRange != nil {
+		dateRangeQuery := types.DateRangeQuery{
+			Gte:      input.DateRange.Gte,
+			Gt:       input.DateRange.Gt,
+			Lte:      input.DateRange.Lte,
+			Lt:       input.DateRange.Lt,
+			Format:   input.DateRange.Format,
+			TimeZone: input.DateRange.TimeZone,
+		}
+		query.Range = map[string]types.RangeQuery{
+			input.DateRange.Field: dateRangeQuery,
+		}
+		fieldsSet++
+	}
+
 	if input.Bool != nil {
 		boolQuery := &types.BoolQuery{}
 
@@ -191,6 +206,29 @@ func convertQueryInput(input *ESQueryInput) (*types.Query, error) {
 		fieldsSet++
 	}
 
+	if input.GeoDistance != nil {
+		geoQuery, err := geoDistanceQuery(input.GeoDistance)
+		if err != nil {
+			return nil, err
+		}
+		query.GeoDistance = geoQuery.GeoDistance
+		fieldsSet++
+	}
+
+	if input.GeoBoundingBox != nil {
+		query.GeoBoundingBox = geoBoundingBoxQuery(input.GeoBoundingBox).GeoBoundingBox
+		fieldsSet++
+	}
+
+	if input.GeoPolygon != nil {
+		geoQuery, err := geoPolygonQuery(input.GeoPolygon)
+		if err != nil {
+			return nil, err
+		}
+		query.GeoShape = geoQuery.GeoShape
+		fieldsSet++
+	}
+
 	if fieldsSet == 0 {
 		return nil, fmt.Errorf("no query type specified")
 	}
@@ -236,6 +274,9 @@ func convertAggsInput(inputs []*ESAggInput) (map[string]types.Aggregations, erro
 			if input.DateHistogram.MinDocCount != nil {
 				dhAgg.MinDocCount = input.DateHistogram.MinDocCount
 			}
+			if input.DateHistogram.TimeZone != nil {
+				dhAgg.TimeZone = input.DateHistogram.TimeZone
+			}
 			agg.DateHistogram = dhAgg
 			fieldsSet++
 		}
@@ -315,6 +356,21 @@ func convertAggsInput(inputs []*ESAggInput) (map[string]types.Aggregations, erro
 			fieldsSet++
 		}
 
+		if input.GeohashGrid != nil || input.GeotileGrid != nil {
+			agg.GeohashGrid, agg.GeotileGrid = geoGridAggregations(input.GeohashGrid, input.GeotileGrid)
+			if agg.GeohashGrid != nil {
+				fieldsSet++
+			}
+			if agg.GeotileGrid != nil {
+				fieldsSet++
+			}
+		}
+
+		if input.GeoCentroid != nil {
+			agg.GeoCentroid = &types.GeoCentroidAggregation{Field: &input.GeoCentroid.Field}
+			fieldsSet++
+		}
+
 		if fieldsSet == 0 {
 			return nil, fmt.Errorf("no aggregation type specified for %s", input.Name)
 		}
@@ -0,0 +1,399 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// formattedDateTime is a date already formatted by a field's format/timeZone arguments
+// The DateTime scalar passes it through unchanged instead of normalising it to RFC 3339
+type formattedDateTime string
+
+// defaultDateLayouts are tried when a date value does not match the mapping's format
+var defaultDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+// namedDateFormats maps Elasticsearch built-in date formats to Go layouts
+var namedDateFormats = map[string]string{
+	"date":                           time.DateOnly,
+	"strict_date":                    time.DateOnly,
+	"date_time":                      "2006-01-02T15:04:05.000Z07:00",
+	"strict_date_time":               "2006-01-02T15:04:05.000Z07:00",
+	"date_time_no_millis":            "2006-01-02T15:04:05Z07:00",
+	"strict_date_time_no_millis":     "2006-01-02T15:04:05Z07:00",
+	"date_hour_minute_second":        "2006-01-02T15:04:05",
+	"strict_date_hour_minute_second": "2006-01-02T15:04:05",
+	"date_hour_minute":               "2006-01-02T15:04",
+	"strict_date_hour_minute":        "2006-01-02T15:04",
+	"basic_date":                     "20060102",
+	"basic_date_time":                "20060102T150405.000Z0700",
+	"basic_date_time_no_millis":      "20060102T150405Z0700",
+	"year_month_day":                 time.DateOnly,
+	"strict_year_month_day":          time.DateOnly,
+	"year_month":                     "2006-01",
+	"strict_year_month":              "2006-01",
+	"year":                           "2006",
+	"strict_year":                    "2006",
+}
+
+// javaDateTokens maps Java/Joda date pattern letters (as used by ES mapping formats) to Go layouts
+// Keys are the letter repeated as in the pattern, longest runs are matched first
+var javaDateTokens = map[string]string{
+	"yyyy": "2006", "uuuu": "2006", "yy": "06", "uu": "06",
+	"MMMM": "January", "MMM": "Jan", "MM": "01", "M": "1",
+	"dd": "02", "d": "2",
+	"EEEE": "Monday", "EEE": "Mon",
+	"HH": "15", "hh": "03", "h": "3",
+	"mm": "04", "m": "4",
+	"ss": "05", "s": "5",
+	"SSSSSSSSS": "000000000", "SSSSSS": "000000", "SSS": "000", "SS": "00", "S": "0",
+	"a":   "PM",
+	"XXX": "Z07:00", "XX": "Z0700", "X": "Z07",
+	"xxx": "-07:00", "xx": "-0700",
+	"ZZZ": "-0700", "ZZ": "-07:00", "Z": "-0700",
+	"z": "MST",
+}
+
+// layoutProbe is formatted with converted layouts to find literal text Go reads as a layout element
+// None of its values match Go's reference time
+var layoutProbe = time.Date(2017, time.November, 28, 9, 48, 39, 123456789, time.FixedZone("XYZ", 3*3600+1800))
+
+// javaToGoLayout converts a Java date pattern (e.g., "yyyy-MM-dd'T'HH:mm") to a Go time layout
+// Go layouts cannot escape text, so patterns whose literals would be read as layout elements
+// (e.g., "'Jan' yyyy") or that use unsupported letters return an error
+func javaToGoLayout(pattern string) (string, error) {
+	// expected is what the layout must format the probe as when every literal is kept as is
+	var layout, expected strings.Builder
+	literal := func(text string) {
+		layout.WriteString(text)
+		expected.WriteString(text)
+	}
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		// Quoted literal text, '' is a quote both inside and outside of it
+		if r == '\'' {
+			if i+1 < len(runes) && runes[i+1] == '\'' {
+				literal("'")
+				i += 2
+				continue
+			}
+			var text strings.Builder
+			end := i + 1
+			for end < len(runes) {
+				if runes[end] == '\'' {
+					if end+1 < len(runes) && runes[end+1] == '\'' {
+						text.WriteRune('\'')
+						end += 2
+						continue
+					}
+					break
+				}
+				text.WriteRune(runes[end])
+				end++
+			}
+			literal(text.String())
+			i = end + 1
+			continue
+		}
+
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			literal(string(r))
+			i++
+			continue
+		}
+
+		// Take the run of the same letter and map the longest known token
+		end := i
+		for end < len(runes) && runes[end] == r {
+			end++
+		}
+		run := string(runes[i:end])
+		for len(run) > 0 {
+			if _, ok := javaDateTokens[run]; ok {
+				break
+			}
+			run = run[:len(run)-1]
+		}
+		if run == "" {
+			return "", fmt.Errorf("unsupported date pattern letter %q in %q", r, pattern)
+		}
+
+		goLayout := javaDateTokens[run]
+		layout.WriteString(goLayout)
+		if r == 'S' {
+			// Fractional seconds are only a layout element after a '.' or ','
+			expected.WriteString(layoutProbe.Format("." + goLayout)[1:])
+		} else {
+			expected.WriteString(layoutProbe.Format(goLayout))
+		}
+
+		// Remaining letters of a longer run (e.g., "yyyyy") are treated as another token
+		i += len([]rune(run))
+	}
+
+	if layoutProbe.Format(layout.String()) != expected.String() {
+		return "", fmt.Errorf("date pattern %q has literal text that cannot be represented in a Go layout", pattern)
+	}
+
+	return layout.String(), nil
+}
+
+// dateFormatLayout returns the Go layout for a single ES date format
+func dateFormatLayout(format string) (string, error) {
+	if layout, ok := namedDateFormats[format]; ok {
+		return layout, nil
+	}
+	return javaToGoLayout(format)
+}
+
+// parseDateValue parses a date value from a document using the mapping's format
+// The format may list several formats separated by "||" as in ES mappings.
+// Values without a zone are interpreted as UTC.
+func parseDateValue(value any, format string) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case float64:
+		return epochToTime(v, format), true
+	case int64:
+		return epochToTime(float64(v), format), true
+	case int:
+		return epochToTime(float64(v), format), true
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		return epochToTime(f, format), true
+	case string:
+		return parseDateString(v, format)
+	}
+	return time.Time{}, false
+}
+
+// parseDateString parses a date string using the mapping's format, then the default layouts
+func parseDateString(value, format string) (time.Time, bool) {
+	for _, f := range strings.Split(format, "||") {
+		f = strings.TrimSpace(f)
+		switch f {
+		case "":
+			continue
+		case "epoch_millis", "epoch_second":
+			if n, err := strconv.ParseFloat(value, 64); err == nil {
+				return epochToTime(n, f), true
+			}
+			continue
+		case "strict_date_optional_time", "date_optional_time", "strict_date_optional_time_nanos":
+			// Covered by the default layouts
+			continue
+		}
+		layout, err := dateFormatLayout(f)
+		if err != nil {
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, true
+		}
+	}
+
+	for _, layout := range defaultDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// epochToTime converts an epoch number to a time, in seconds when the format says so
+func epochToTime(value float64, format string) time.Time {
+	if strings.Contains(format, "epoch_second") && !strings.Contains(format, "epoch_millis") {
+		sec, frac := math.Modf(value)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	}
+	return time.UnixMilli(int64(value)).UTC()
+}
+
+// isDateMath reports whether a value uses ES date math (e.g., "now-7d/d" or "2024-01-01||+1M")
+func isDateMath(value string) bool {
+	return strings.HasPrefix(value, "now") || strings.Contains(value, "||")
+}
+
+// serializeDateTime normalises a date value to RFC 3339
+// Values that cannot be parsed are returned unchanged so no data is lost
+func serializeDateTime(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case formattedDateTime:
+		return string(v)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+
+	if t, ok := parseDateValue(value, ""); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+// parseDateTimeInput accepts dates, epoch millis and ES date math in inputs
+// The value is passed to Elasticsearch as a string, which resolves date math itself
+func parseDateTimeInput(value any) any {
+	switch v := value.(type) {
+	case string:
+		if isDateMath(v) {
+			return v
+		}
+		if _, ok := parseDateString(v, ""); ok {
+			return v
+		}
+		if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return v
+		}
+		return nil
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		if v == math.Trunc(v) {
+			return strconv.FormatInt(int64(v), 10)
+		}
+	}
+	return nil
+}
+
+// parseDateRangeBound keeps strings as they are, since the bound's format is only known from
+// the range; they are checked as DateTime inputs when the range has no format
+func parseDateRangeBound(value any) any {
+	if v, ok := value.(string); ok {
+		return v
+	}
+	return parseDateTimeInput(value)
+}
+
+// validateDateRangeBounds checks that the bounds of a range without a format are DateTime inputs
+func validateDateRangeBounds(input *ESDateRangeQueryInput) error {
+	if input.Format != nil && *input.Format != "" {
+		return nil
+	}
+	for _, bound := range []*string{input.Gte, input.Gt, input.Lte, input.Lt} {
+		if bound != nil && parseDateTimeInput(*bound) == nil {
+			return fmt.Errorf("dateRange on %s: invalid date %q, set format for dates in other formats", input.Field, *bound)
+		}
+	}
+	return nil
+}
+
+// dateFieldArgs returns the arguments added to DateTime document fields
+func dateFieldArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"format": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Output format as a date pattern (e.g., \"yyyy-MM-dd\"), defaults to RFC 3339",
+		},
+		"timeZone": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Time zone to convert to (e.g., \"Europe/Stockholm\")",
+		},
+	}
+}
+
+// dateFieldResolver resolves a date field from the source document,
+// parsing it with the mapping's format and applying the format/timeZone arguments
+func dateFieldResolver(field *Field) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		value, ok := source[field.Name]
+		if !ok || value == nil {
+			return nil, nil
+		}
+
+		t, ok := parseDateValue(value, field.Format)
+		if !ok {
+			// Unknown format, return the raw value rather than failing the whole hit
+			return value, nil
+		}
+
+		if tz, ok := p.Args["timeZone"].(string); ok && tz != "" {
+			loc, err := time.LoadLocation(tz)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeZone %q: %w", tz, err)
+			}
+			t = t.In(loc)
+		}
+
+		if format, ok := p.Args["format"].(string); ok && format != "" {
+			layout, err := dateFormatLayout(format)
+			if err != nil {
+				return nil, fmt.Errorf("invalid format: %w", err)
+			}
+			return formattedDateTime(t.Format(layout)), nil
+		}
+
+		return t, nil
+	}
+}
+
+// applyQueryTimeZone sets the default time zone on date range queries that do not specify their own
+func applyQueryTimeZone(query *ESQueryInput, timeZone string) {
+	if query == nil {
+		return
+	}
+
+	if query.DateRange != nil && query.DateRange.TimeZone == nil {
+		query.DateRange.TimeZone = &timeZone
+	}
+
+	if query.Bool != nil {
+		for _, clauses := range [][]*ESQueryInput{query.Bool.Must, query.Bool.Should, query.Bool.Filter, query.Bool.MustNot} {
+			for _, clause := range clauses {
+				applyQueryTimeZone(clause, timeZone)
+			}
+		}
+	}
+
+	if query.Nested != nil {
+		applyQueryTimeZone(query.Nested.Query, timeZone)
+	}
+}
+
+// applyAggTimeZone sets the default time zone on date histograms (including sub-aggregations)
+func applyAggTimeZone(agg *ESAggInput, timeZone string) {
+	if agg == nil {
+		return
+	}
+
+	if agg.DateHistogram != nil && agg.DateHistogram.TimeZone == nil {
+		agg.DateHistogram.TimeZone = &timeZone
+	}
+
+	if agg.Filter != nil {
+		applyQueryTimeZone(agg.Filter.Query, timeZone)
+	}
+
+	for _, subAgg := range agg.Aggs {
+		applyAggTimeZone(subAgg, timeZone)
+	}
+}
@@ -0,0 +1,226 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+)
+
+func TestJavaToGoLayout(t *testing.T) {
+	tests := map[string]string{
+		"yyyy-MM-dd":                 "2006-01-02",
+		"yyyy-MM-dd HH:mm:ss":        "2006-01-02 15:04:05",
+		"yyyy-MM-dd'T'HH:mm:ss.SSSZ": "2006-01-02T15:04:05.000-0700",
+		"dd/MM/yy":                   "02/01/06",
+		"EEE, d MMM yyyy":            "Mon, 2 Jan 2006",
+		"dd 'de' MMMM 'at' HH:mm":    "02 de January at 15:04",
+		"hh 'o''clock' a":            "03 o'clock PM",
+		"yyyyMMdd'T'HHmmss.SSS":      "20060102T150405.000",
+	}
+
+	for pattern, expected := range tests {
+		if got, err := javaToGoLayout(pattern); err != nil || got != expected {
+			t.Errorf("javaToGoLayout(%q) = %q, %v, expected %q", pattern, got, err, expected)
+		}
+	}
+
+	// Go layouts cannot escape text, literals that Go would read as date elements are rejected
+	for _, pattern := range []string{"'Jan' yyyy", "dd 'of' MMM '2'", "yyyy'1'MM", "'_'d", "'PM' HH", "G yyyy"} {
+		if got, err := javaToGoLayout(pattern); err == nil {
+			t.Errorf("javaToGoLayout(%q) = %q, expected an error", pattern, got)
+		}
+	}
+}
+
+func TestParseDateValue(t *testing.T) {
+	expected := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  any
+		format string
+	}{
+		{"RFC 3339", "2024-01-15T10:30:00Z", ""},
+		{"mapping format", "2024-01-15 10:30:00", "yyyy-MM-dd HH:mm:ss"},
+		{"second of multiple formats", "15/01/2024 10:30", "yyyy-MM-dd||dd/MM/yyyy HH:mm"},
+		{"epoch millis", float64(expected.UnixMilli()), "epoch_millis"},
+		{"epoch millis string", "1705314600000", "strict_date_optional_time||epoch_millis"},
+		{"epoch seconds", float64(expected.Unix()), "epoch_second"},
+		{"named format", "2024-01-15T10:30:00", "date_hour_minute_second"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseDateValue(tt.value, tt.format)
+			if !ok {
+				t.Fatalf("Failed to parse %v with format %q", tt.value, tt.format)
+			}
+			if !got.Equal(expected) {
+				t.Errorf("Expected %v, got %v", expected, got)
+			}
+		})
+	}
+
+	if _, ok := parseDateValue("not a date", ""); ok {
+		t.Error("Expected invalid date to fail")
+	}
+}
+
+func TestDateFieldResolver(t *testing.T) {
+	resolve := dateFieldResolver(&Field{Name: "createdAt", Type: FieldTypeDate, Format: "yyyy-MM-dd HH:mm:ss"})
+	source := map[string]any{"createdAt": "2024-01-15 23:30:00"}
+
+	tests := []struct {
+		name     string
+		args     map[string]any
+		expected string
+	}{
+		{"RFC 3339 by default", map[string]any{}, "2024-01-15T23:30:00Z"},
+		{"time zone", map[string]any{"timeZone": "Europe/Stockholm"}, "2024-01-16T00:30:00+01:00"},
+		{"format and time zone", map[string]any{"format": "yyyy-MM-dd", "timeZone": "Europe/Stockholm"}, "2024-01-16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := resolve(graphql.ResolveParams{Source: source, Args: tt.args})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := DateTime.Serialize(value); got != tt.expected {
+				t.Errorf("Expected %q, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := resolve(graphql.ResolveParams{Source: source, Args: map[string]any{"timeZone": "Mars/Olympus"}}); err == nil {
+		t.Error("Expected error for invalid time zone")
+	}
+}
+
+func TestDateTimeInput(t *testing.T) {
+	for _, valid := range []any{"now-7d/d", "2024-01-01||+1M", "2024-01-15", "2024-01-15T10:30:00Z", 1705314600000} {
+		if DateTime.ParseValue(valid) == nil {
+			t.Errorf("Expected %v to be accepted", valid)
+		}
+	}
+	if DateTime.ParseValue("yesterday") != nil {
+		t.Error("Expected invalid date input to be rejected")
+	}
+}
+
+func TestDateTimeSchema(t *testing.T) {
+	config := NewConfig(
+		WithQuery("events", &QueryConfig{
+			Mapping: IndexMapping{
+				IndexName: "events",
+				Properties: map[string]*Field{
+					"createdAt": {Name: "createdAt", Type: FieldTypeDate},
+					"loggedAt":  {Name: "loggedAt", Type: FieldTypeDateNanos},
+				},
+			},
+			EnableElasticQuerying: true,
+		}),
+	)
+
+	sdl, err := GenerateSchemaSDL(config)
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	for _, e := range []string{"scalar DateTime", "format: String", "timeZone: String", "input ESDateRangeQueryInput"} {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+	for _, field := range []string{"createdAt(", "loggedAt("} {
+		line := sdl[strings.Index(sdl, field):]
+		line = line[:strings.Index(line, "\n")]
+		if !strings.HasSuffix(line, "): DateTime") {
+			t.Errorf("Expected %s to be a DateTime field, got: %s", field, line)
+		}
+	}
+}
+
+func TestDateRangeQueryTimeZone(t *testing.T) {
+	gte := "now-7d/d"
+	input := &ESQueryInput{
+		Bool: &ESBoolQueryInput{
+			Filter: []*ESQueryInput{
+				{DateRange: &ESDateRangeQueryInput{Field: "createdAt", Gte: &gte}},
+			},
+		},
+	}
+	applyQueryTimeZone(input, "Europe/Stockholm")
+
+	query, err := convertQueryInput(input)
+	if err != nil {
+		t.Fatalf("Failed to convert query: %v", err)
+	}
+
+	rangeQuery, ok := query.Bool.Filter[0].Range["createdAt"].(types.DateRangeQuery)
+	if !ok {
+		t.Fatalf("Expected DateRangeQuery, got %T", query.Bool.Filter[0].Range["createdAt"])
+	}
+	if rangeQuery.Gte == nil || *rangeQuery.Gte != gte {
+		t.Errorf("Expected gte %q, got %v", gte, rangeQuery.Gte)
+	}
+	if rangeQuery.TimeZone == nil || *rangeQuery.TimeZone != "Europe/Stockholm" {
+		t.Errorf("Expected time zone to be propagated, got %v", rangeQuery.TimeZone)
+	}
+
+	// Date histograms keep an explicit time zone and get the default otherwise
+	utc := "UTC"
+	aggs := []*ESAggInput{
+		{Name: "perDay", DateHistogram: &ESDateHistogramAggInput{Field: "createdAt"}},
+		{Name: "perDayUTC", DateHistogram: &ESDateHistogramAggInput{Field: "createdAt", TimeZone: &utc}},
+	}
+	for _, agg := range aggs {
+		applyAggTimeZone(agg, "Europe/Stockholm")
+	}
+
+	converted, err := convertAggsInput(aggs)
+	if err != nil {
+		t.Fatalf("Failed to convert aggregations: %v", err)
+	}
+	if tz := converted["perDay"].DateHistogram.TimeZone; tz == nil || *tz != "Europe/Stockholm" {
+		t.Errorf("Expected default time zone, got %v", tz)
+	}
+	if tz := converted["perDayUTC"].DateHistogram.TimeZone; tz == nil || *tz != "UTC" {
+		t.Errorf("Expected explicit time zone to be kept, got %v", tz)
+	}
+}
+
+func TestDateRangeCustomFormat(t *testing.T) {
+	es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 0, "relation": "eq"}, "hits": []}`))
+	api := es.newAPI(t, &recordingBackend{}, WithQuery("events", &QueryConfig{
+		Mapping: IndexMapping{
+			IndexName:  "events",
+			Properties: map[string]*Field{"createdAt": {Name: "createdAt", Type: FieldTypeDate}},
+		},
+		EnableElasticQuerying: true,
+	}))
+
+	// Bounds in the range's format are passed to Elasticsearch as they are
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		RequestString: `{ events(query: {dateRange: {field: "createdAt", gte: "15/01/2024", lt: 1705314600000, format: "dd/MM/yyyy||epoch_millis"}}) { totalCount } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+	if body := es.requests()[0]; !strings.Contains(body, `"createdAt":{"format":"dd/MM/yyyy||epoch_millis","gte":"15/01/2024","lt":"1705314600000"}`) {
+		t.Errorf("Expected the bounds in their format, got %s", body)
+	}
+
+	// Without a format, bounds must be DateTime inputs
+	result = graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		RequestString: `{ events(query: {dateRange: {field: "createdAt", gte: "15/01/2024"}}) { totalCount } }`,
+	})
+	if len(result.Errors) == 0 {
+		t.Error("Expected an error for a bound without a format")
+	}
+}
@@ -2,8 +2,8 @@ package graphql
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
@@ -21,7 +21,11 @@ func executeTypedQuery(
 	limit *int,
 	offset *int,
 ) (*reveald.Result, error) {
-	// Build search request
+	return executeTypedRequest(ctx, client, indices, newTypedRequest(query, aggs, limit, offset))
+}
+
+// newTypedRequest builds a search request from a query, aggregations and pagination
+func newTypedRequest(query *types.Query, aggs map[string]types.Aggregations, limit, offset *int) *search.Request {
 	req := &search.Request{}
 
 	if query != nil {
@@ -40,16 +44,32 @@ func executeTypedQuery(
 		req.From = offset
 	}
 
-	// Execute search
-	searchReq := client.Search()
-	for _, idx := range indices {
-		searchReq = searchReq.Index(idx)
+	return req
+}
+
+// executeTypedRequest executes a search request using the typed API and returns a reveald Result
+func executeTypedRequest(ctx context.Context, client *elasticsearch.TypedClient, indices []string, req *search.Request) (*reveald.Result, error) {
+	resp, err := searchTyped(ctx, client, indices, req)
+	if err != nil {
+		return nil, err
 	}
-	resp, err := searchReq.Request(req).Do(ctx)
+	return typedResult(resp, req)
+}
+
+// searchTyped executes a search request using the typed API
+func searchTyped(ctx context.Context, client *elasticsearch.TypedClient, indices []string, req *search.Request) (*search.Response, error) {
+	resp, err := client.Search().
+		Index(strings.Join(indices, ",")).
+		Request(req).
+		Do(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
+	return resp, nil
+}
 
+// typedResult converts the response of a typed search request to a reveald Result
+func typedResult(resp *search.Response, req *search.Request) (*reveald.Result, error) {
 	// Parse response to reveald Result
 	result, err := parseESResponse(resp)
 	if err != nil {
@@ -58,17 +78,94 @@ func executeTypedQuery(
 
 	// Set pagination info
 	if result.Pagination != nil {
-		if offset != nil {
-			result.Pagination.Offset = *offset
+		if req.From != nil {
+			result.Pagination.Offset = *req.From
 		}
-		if limit != nil {
-			result.Pagination.PageSize = *limit
+		if req.Size != nil {
+			result.Pagination.PageSize = *req.Size
 		}
 	}
 
 	return result, nil
 }
 
+// typedHitsFeature fetches the hits of a feature-based query with the typed client
+// The backend can neither collapse, sort by distance nor send suggesters, so the hits are fetched
+// with the features' request through the typed client. The features read their aggregations from
+// the backend's result, so requests with aggregations are also sent through the backend, without
+// hits. It is registered last to see the full request.
+type typedHitsFeature struct {
+	ctx          context.Context
+	client       *elasticsearch.TypedClient
+	collapse     *collapseOptions
+	distanceSort *distanceSort
+	spellCheck   *SpellCheck
+	groups       *int64           // Group count, set by Process when requested
+	cursor       *string          // Cursor after the last group, set by Process when paged
+	suggestions  []map[string]any // Spelling suggestions, set by Process when requested
+}
+
+// Process implements reveald.Feature
+func (thf *typedHitsFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	req := builder.BuildRequest()
+	size, from := *req.Size, *req.From
+	req.Size, req.From = &size, &from
+
+	// The features' aggregations are computed by the backend
+	var result *reveald.Result
+	if len(req.Aggregations) > 0 {
+		req.Aggregations = nil
+		builder.Selection().Update(reveald.WithPageSize(0))
+		var err error
+		if result, err = next(builder); err != nil {
+			return nil, err
+		}
+	}
+
+	if thf.distanceSort != nil {
+		thf.distanceSort.apply(req)
+	}
+	if thf.collapse != nil {
+		if err := thf.collapse.apply(req); err != nil {
+			return nil, err
+		}
+	}
+	if thf.spellCheck != nil {
+		if text := searchText(req.Query); text != "" {
+			req.Suggest = thf.spellCheck.suggester(text)
+		}
+	}
+	resp, err := searchTyped(thf.ctx, thf.client, builder.Indices(), req)
+	if err != nil {
+		return nil, fmt.Errorf("hits search failed: %w", err)
+	}
+
+	if count, ok := takeGroupCount(resp); ok {
+		thf.groups = &count
+	}
+	hits, err := typedResult(resp, req)
+	if err != nil {
+		return nil, fmt.Errorf("hits search failed: %w", err)
+	}
+	if thf.distanceSort != nil {
+		thf.distanceSort.setDistances(resp, hits.Hits)
+	}
+	if thf.collapse != nil {
+		thf.cursor = thf.collapse.cursor(resp)
+	}
+	if thf.spellCheck != nil {
+		thf.suggestions = thf.spellCheck.spellingSuggestions(resp)
+	}
+
+	if result == nil {
+		// Like the backend, pagination is left to the features
+		hits.Pagination = nil
+		return hits, nil
+	}
+	result.Hits = hits.Hits
+	return result, nil
+}
+
 // parseESResponse parses ES response to reveald Result format
 func parseESResponse(resp *search.Response) (*reveald.Result, error) {
 	result := &reveald.Result{
@@ -85,22 +182,10 @@ func parseESResponse(resp *search.Response) (*reveald.Result, error) {
 
 	// Parse hits
 	for _, hit := range resp.Hits.Hits {
-		doc := make(map[string]any)
-
-		// Add _id
-		doc["id"] = hit.Id_
-
-		// Parse _source
-		if hit.Source_ != nil {
-			if err := json.Unmarshal(hit.Source_, &doc); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal hit source: %w", err)
-			}
-			// Ensure id is set even if not in source
-			if _, hasID := doc["id"]; !hasID {
-				doc["id"] = hit.Id_
-			}
+		doc, err := hitDocument(hit)
+		if err != nil {
+			return nil, err
 		}
-
 		result.Hits = append(result.Hits, doc)
 	}
 
@@ -116,6 +201,43 @@ func parseESResponse(resp *search.Response) (*reveald.Result, error) {
 	return result, nil
 }
 
+// hitDocument converts a hit to a document with its source, _id, _index, computed field values
+// and inner hits
+func hitDocument(hit types.Hit) (map[string]any, error) {
+	doc := make(map[string]any)
+
+	// Add _id and _index
+	doc["id"] = hit.Id_
+	doc[indexFieldName] = hit.Index_
+	if hit.Id_ != nil {
+		doc[hitIDKey] = *hit.Id_
+	}
+
+	// Parse _source
+	if hit.Source_ != nil {
+		if err := decodeSource(hit.Source_, &doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal hit source: %w", err)
+		}
+		// Ensure id is set even if not in source
+		if _, hasID := doc["id"]; !hasID {
+			doc["id"] = hit.Id_
+		}
+	}
+
+	// Runtime and script field values
+	for k, v := range hitFieldValues(hit.Fields) {
+		if _, exists := doc[k]; !exists {
+			doc[k] = v
+		}
+	}
+
+	// Matched nested objects
+	if len(hit.InnerHits) > 0 {
+		doc[innerHitsKey] = innerHitValues(hit.InnerHits)
+	}
+	return doc, nil
+}
+
 // parseAggregations parses ES aggregations to reveald format
 func parseAggregations(esAggs map[string]types.Aggregate) (map[string][]*reveald.ResultBucket, error) {
 	result := make(map[string][]*reveald.ResultBucket)
@@ -147,6 +269,25 @@ func parseAggregations(esAggs map[string]types.Aggregate) (map[string][]*reveald
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse histogram buckets for %s: %w", aggName, err)
 			}
+		case *types.GeoHashGridAggregate:
+			buckets, err = parseGeoHashGridBuckets(v.Buckets)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse geohash grid buckets for %s: %w", aggName, err)
+			}
+		case *types.GeoTileGridAggregate:
+			buckets, err = parseGeoTileGridBuckets(v.Buckets)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse geotile grid buckets for %s: %w", aggName, err)
+			}
+		case *types.GeoCentroidAggregate:
+			// Store the centroid as a single "lat,lon" bucket
+			if location, ok := geoCentroidValue(v)["location"].(map[string]any); ok {
+				bucket := &reveald.ResultBucket{
+					Value:    fmt.Sprintf("%v,%v", location["lat"], location["lon"]),
+					HitCount: v.Count,
+				}
+				buckets = []*reveald.ResultBucket{bucket}
+			}
 		case *types.FilterAggregate:
 			bucket := &reveald.ResultBucket{
 				Value:    aggName,
@@ -373,3 +514,59 @@ func parseRangeBuckets(esBuckets types.BucketsRangeBucket) ([]*reveald.ResultBuc
 
 	return buckets, nil
 }
+
+// parseGeoHashGridBuckets parses geohash grid aggregation buckets
+func parseGeoHashGridBuckets(esBuckets types.BucketsGeoHashGridBucket) ([]*reveald.ResultBucket, error) {
+	buckets := make([]*reveald.ResultBucket, 0)
+
+	// BucketsGeoHashGridBucket can be []GeoHashGridBucket or map[string]GeoHashGridBucket
+	switch v := esBuckets.(type) {
+	case []types.GeoHashGridBucket:
+		for _, b := range v {
+			bucket := &reveald.ResultBucket{
+				Value:    b.Key,
+				HitCount: b.DocCount,
+			}
+
+			if len(b.Aggregations) > 0 {
+				subAggs, err := parseAggregations(b.Aggregations)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse sub-aggregations: %w", err)
+				}
+				bucket.SubResultBuckets = subAggs
+			}
+
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	return buckets, nil
+}
+
+// parseGeoTileGridBuckets parses geotile grid aggregation buckets
+func parseGeoTileGridBuckets(esBuckets types.BucketsGeoTileGridBucket) ([]*reveald.ResultBucket, error) {
+	buckets := make([]*reveald.ResultBucket, 0)
+
+	// BucketsGeoTileGridBucket can be []GeoTileGridBucket or map[string]GeoTileGridBucket
+	switch v := esBuckets.(type) {
+	case []types.GeoTileGridBucket:
+		for _, b := range v {
+			bucket := &reveald.ResultBucket{
+				Value:    b.Key,
+				HitCount: b.DocCount,
+			}
+
+			if len(b.Aggregations) > 0 {
+				subAggs, err := parseAggregations(b.Aggregations)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse sub-aggregations: %w", err)
+				}
+				bucket.SubResultBuckets = subAggs
+			}
+
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,192 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/reveald/reveald/v2"
+)
+
+// fakeES is a fake Elasticsearch server that records the request bodies and answers each
+// search with the response fields after _shards (hits, aggregations, suggest) returned by respond
+type fakeES struct {
+	mu      sync.Mutex
+	respond func(index, body string) string
+	bodies  []string
+	url     string
+	client  *elasticsearch.TypedClient
+}
+
+// newFakeES starts a fake Elasticsearch server, closed when the test ends
+func newFakeES(t *testing.T, respond func(index, body string) string) *fakeES {
+	t.Helper()
+
+	es := &fakeES{respond: respond}
+	srv := httptest.NewServer(es)
+	t.Cleanup(srv.Close)
+	es.url = srv.URL
+
+	client, err := elasticsearch.NewTypedClient(elasticsearch.Config{Addresses: []string{srv.URL}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	es.client = client
+	return es
+}
+
+// staticResponse answers every search with the same response fields
+func staticResponse(fields string) func(index, body string) string {
+	return func(string, string) string {
+		return fields
+	}
+}
+
+func (f *fakeES) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, _ := io.ReadAll(r.Body)
+	body := string(data)
+	f.mu.Lock()
+	f.bodies = append(f.bodies, body)
+	f.mu.Unlock()
+
+	index := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	_, _ = w.Write([]byte(`{"took": 1, "timed_out": false, "_shards": {"total": 1, "successful": 1, "skipped": 0, "failed": 0},` + f.respond(index, body) + `}`))
+}
+
+// requests returns the bodies of the requests received so far
+func (f *fakeES) requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.bodies...)
+}
+
+// backend creates a reveald backend searching the fake server, so feature-based queries get
+// the raw ES response
+func (f *fakeES) backend(t *testing.T) reveald.Backend {
+	t.Helper()
+
+	backend, err := reveald.NewElasticBackend([]string{f.url})
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	return backend
+}
+
+// newAPI creates an API searching the fake server, with a backend for feature-based queries
+func (f *fakeES) newAPI(t *testing.T, backend reveald.Backend, opts ...ConfigOption) *GraphQLAPI {
+	t.Helper()
+
+	api, err := New(backend, NewConfig(opts...), WithESClient(f.client))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	return api
+}
+
+// docsResponse answers searches with the documents of an index matching the request's terms
+// query, or the documents accepted by match when it is set
+// Collapsed requests return the first document of each group with the group's documents as
+// inner hits, and at most size hits are returned
+func docsResponse(docs map[string][]map[string]any, match func(doc, req map[string]any) bool) func(index, body string) string {
+	return func(index, body string) string {
+		var req map[string]any
+		_ = json.Unmarshal([]byte(body), &req)
+		accept := match
+		if accept == nil {
+			field, values := findTerms(req)
+			accept = func(doc, _ map[string]any) bool {
+				return values[fakeKey(doc[strings.TrimSuffix(field, ".keyword")])]
+			}
+		}
+
+		var hits []map[string]any
+		groups := make(map[string]map[string]any)
+		collapse, _ := req["collapse"].(map[string]any)
+		for _, doc := range docs[index] {
+			if !accept(doc, req) {
+				continue
+			}
+			hit := map[string]any{"_index": index, "_id": doc["id"], "_source": doc}
+			if collapse == nil {
+				hits = append(hits, hit)
+				continue
+			}
+
+			key := fakeKey(doc[strings.TrimSuffix(collapse["field"].(string), ".keyword")])
+			top, ok := groups[key]
+			if !ok {
+				top = map[string]any{"_index": index, "_id": doc["id"], "_source": doc, "inner_hits": map[string]any{}}
+				groups[key] = top
+				hits = append(hits, top)
+			}
+			innerHits, _ := collapse["inner_hits"].([]any)
+			for _, item := range innerHits {
+				options := item.(map[string]any)
+				name := options["name"].(string)
+				group, _ := top["inner_hits"].(map[string]any)[name].(map[string]any)
+				if group == nil {
+					group = map[string]any{"hits": map[string]any{"hits": []any{}}}
+					top["inner_hits"].(map[string]any)[name] = group
+				}
+				list := group["hits"].(map[string]any)["hits"].([]any)
+				if size, ok := options["size"].(float64); !ok || len(list) < int(size) {
+					group["hits"].(map[string]any)["hits"] = append(list, hit)
+				}
+			}
+		}
+
+		if size, ok := req["size"].(float64); ok && len(hits) > int(size) {
+			hits = hits[:int(size)]
+		}
+		return hitsResponse(hits)
+	}
+}
+
+// hitsResponse returns the hits response fields of a list of hits
+func hitsResponse(hits []map[string]any) string {
+	data, _ := json.Marshal(map[string]any{"total": map[string]any{"value": len(hits), "relation": "eq"}, "hits": hits})
+	return `"hits": ` + string(data)
+}
+
+// findTerms returns the field and values of the first terms query in a request
+func findTerms(value any) (string, map[string]bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		if terms, ok := v["terms"].(map[string]any); ok {
+			for field, list := range terms {
+				values := make(map[string]bool)
+				for _, item := range list.([]any) {
+					values[fakeKey(item)] = true
+				}
+				return field, values
+			}
+		}
+		for _, child := range v {
+			if field, values := findTerms(child); field != "" {
+				return field, values
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if field, values := findTerms(child); field != "" {
+				return field, values
+			}
+		}
+	}
+	return "", nil
+}
+
+func fakeKey(value any) string {
+	keys := relationKeys(value)
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
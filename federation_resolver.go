@@ -2,10 +2,10 @@ package graphql
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"slices"
+	"strings"
 
 	elasticsearch "github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
@@ -28,14 +28,35 @@ type EntityTypeMapping struct {
 
 	// Common fields
 	Mapping    *IndexMapping
+	Indices    []string // Indices, aliases or patterns to search (defaults to Mapping.IndexName)
 	EntityKeys []string // The key fields for this entity (e.g., ["id"] or ["id", "conversationId"])
 }
 
+// searchIndices returns the indices to search when resolving this entity
+func (m *EntityTypeMapping) searchIndices() []string {
+	if len(m.Indices) > 0 {
+		return m.Indices
+	}
+	return []string{m.Mapping.IndexName}
+}
+
+// indexResolver returns the IndexResolver of the query this entity belongs to
+func (m *EntityTypeMapping) indexResolver() IndexResolver {
+	if m.UseFeatureFlow && m.QueryConfig != nil {
+		return m.QueryConfig.IndexResolver
+	}
+	if m.PrecompiledConfig != nil {
+		return m.PrecompiledConfig.IndexResolver
+	}
+	return nil
+}
+
 // EntityResolver resolves entities for Apollo Federation
 type EntityResolver struct {
 	esClient     *elasticsearch.TypedClient
 	backend      reveald.Backend
 	typeMappings map[string]*EntityTypeMapping // typename -> config mapping
+	names        *nameRegistry                 // GraphQL names of the ES fields of the entity types
 }
 
 // NewEntityResolver creates a new entity resolver
@@ -95,21 +116,30 @@ func (er *EntityResolver) resolveEntity(repr any, params graphql.ResolveParams)
 		return nil, fmt.Errorf("unknown entity type: %s", typename)
 	}
 
+	// Representations use the GraphQL names of the fields, the query and the entity ES names
+	sourceFields := er.esFields(typename, fields)
+
 	// Build ES query from key fields
-	query, err := er.buildEntityQuery(typeMapping, fields)
+	query, err := er.buildEntityQuery(typename, typeMapping, sourceFields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build entity query: %w", err)
 	}
 
-	// Get HTTP request from context (for RootQueryBuilder and RequestInterceptor)
+	// Get HTTP request from context (for RootQueryBuilder, RequestInterceptor and IndexResolver)
 	httpReq, _ := getHTTPRequest(params)
 
+	// Pick the indices to search, the representation fields act as arguments
+	indices, err := typeMapping.indexResolver().resolve(httpReq, fields, typeMapping.searchIndices())
+	if err != nil {
+		return nil, err
+	}
+
 	// Execute query based on whether it's a feature-based or precompiled query
 	var entity map[string]any
 	if typeMapping.UseFeatureFlow {
-		entity, err = er.resolveWithFeatures(typeMapping, query, httpReq, params.Context)
+		entity, err = er.resolveWithFeatures(typeMapping, query, indices, httpReq, params.Context)
 	} else {
-		entity, err = er.resolveWithPrecompiled(typeMapping, query, httpReq, params.Context)
+		entity, err = er.resolveWithPrecompiled(typeMapping, query, indices, httpReq, params.Context)
 	}
 
 	if err != nil {
@@ -119,9 +149,10 @@ func (er *EntityResolver) resolveEntity(repr any, params graphql.ResolveParams)
 	// Merge representation fields into entity (for @requires directive support)
 	// The gateway provides additional data in the representation that needs to be available to resolvers
 	if entity != nil {
-		for key, value := range fields {
+		keys := er.esKeys(typename, typeMapping)
+		for key, value := range sourceFields {
 			// Don't overwrite key fields that came from ES
-			isKeyField := slices.Contains(typeMapping.EntityKeys, key)
+			isKeyField := slices.Contains(keys, key)
 			// Don't overwrite __typename
 			if key == "__typename" {
 				continue
@@ -137,15 +168,42 @@ func (er *EntityResolver) resolveEntity(repr any, params graphql.ResolveParams)
 	return entity, nil
 }
 
-// buildEntityQuery builds an Elasticsearch query from entity key fields
-func (er *EntityResolver) buildEntityQuery(typeMapping *EntityTypeMapping, fields map[string]any) (*types.Query, error) {
+// esName returns the ES name of a field of an entity type
+func (er *EntityResolver) esName(typename, field string) string {
+	if name, ok := er.names.esName(typename, field); ok {
+		return name
+	}
+	return field
+}
+
+// esFields returns the representation fields keyed by their ES names
+func (er *EntityResolver) esFields(typename string, fields map[string]any) map[string]any {
+	translated := make(map[string]any, len(fields))
+	for field, value := range fields {
+		translated[er.esName(typename, field)] = value
+	}
+	return translated
+}
+
+// esKeys returns the ES names of the key fields of an entity type
+func (er *EntityResolver) esKeys(typename string, typeMapping *EntityTypeMapping) []string {
+	keys := make([]string, len(typeMapping.EntityKeys))
+	for i, key := range typeMapping.EntityKeys {
+		keys[i] = er.esName(typename, key)
+	}
+	return keys
+}
+
+// buildEntityQuery builds an Elasticsearch query from entity key fields (by their ES names)
+func (er *EntityResolver) buildEntityQuery(typename string, typeMapping *EntityTypeMapping, fields map[string]any) (*types.Query, error) {
 	if len(typeMapping.EntityKeys) == 0 {
 		return nil, fmt.Errorf("entity has no key fields defined")
 	}
 
 	// If single key field, use terms query
-	if len(typeMapping.EntityKeys) == 1 {
-		keyField := typeMapping.EntityKeys[0]
+	keys := er.esKeys(typename, typeMapping)
+	if len(keys) == 1 {
+		keyField := keys[0]
 		keyValue, ok := fields[keyField]
 		if !ok {
 			return nil, fmt.Errorf("missing key field: %s", keyField)
@@ -172,7 +230,7 @@ func (er *EntityResolver) buildEntityQuery(typeMapping *EntityTypeMapping, field
 
 	// Multiple key fields - use bool query with must
 	var mustQueries []types.Query
-	for _, keyField := range typeMapping.EntityKeys {
+	for _, keyField := range keys {
 		keyValue, ok := fields[keyField]
 		if !ok {
 			return nil, fmt.Errorf("missing key field: %s", keyField)
@@ -205,7 +263,7 @@ func (er *EntityResolver) buildEntityQuery(typeMapping *EntityTypeMapping, field
 }
 
 // resolveWithFeatures resolves entity using reveald features (for regular queries)
-func (er *EntityResolver) resolveWithFeatures(typeMapping *EntityTypeMapping, entityQuery *types.Query, httpReq *http.Request, ctx context.Context) (map[string]any, error) {
+func (er *EntityResolver) resolveWithFeatures(typeMapping *EntityTypeMapping, entityQuery *types.Query, indices []string, httpReq *http.Request, ctx context.Context) (map[string]any, error) {
 	// Create a minimal reveald request
 	request := reveald.NewRequest()
 
@@ -219,16 +277,16 @@ func (er *EntityResolver) resolveWithFeatures(typeMapping *EntityTypeMapping, en
 	// For entity resolution, we need to use typed ES query since reveald endpoints
 	// don't directly support merging arbitrary ES queries
 	// This ensures RootQueryBuilder and RequestInterceptor are still applied
-	return er.resolveWithTypedQuery(typeMapping, entityQuery, httpReq, ctx)
+	return er.resolveWithTypedQuery(typeMapping, entityQuery, indices, httpReq, ctx)
 }
 
 // resolveWithPrecompiled resolves entity using precompiled query config
-func (er *EntityResolver) resolveWithPrecompiled(typeMapping *EntityTypeMapping, entityQuery *types.Query, httpReq *http.Request, ctx context.Context) (map[string]any, error) {
-	return er.resolveWithTypedQuery(typeMapping, entityQuery, httpReq, ctx)
+func (er *EntityResolver) resolveWithPrecompiled(typeMapping *EntityTypeMapping, entityQuery *types.Query, indices []string, httpReq *http.Request, ctx context.Context) (map[string]any, error) {
+	return er.resolveWithTypedQuery(typeMapping, entityQuery, indices, httpReq, ctx)
 }
 
 // resolveWithTypedQuery resolves entity using Elasticsearch typed API
-func (er *EntityResolver) resolveWithTypedQuery(typeMapping *EntityTypeMapping, entityQuery *types.Query, httpReq *http.Request, ctx context.Context) (map[string]any, error) {
+func (er *EntityResolver) resolveWithTypedQuery(typeMapping *EntityTypeMapping, entityQuery *types.Query, indices []string, httpReq *http.Request, ctx context.Context) (map[string]any, error) {
 	if er.esClient == nil {
 		return nil, fmt.Errorf("ES client not configured - entity resolution requires typed ES client")
 	}
@@ -265,7 +323,7 @@ func (er *EntityResolver) resolveWithTypedQuery(typeMapping *EntityTypeMapping,
 	}
 
 	resp, err := er.esClient.Search().
-		Index(typeMapping.Mapping.IndexName).
+		Index(strings.Join(indices, ",")).
 		Request(&search.Request{
 			Size:  ptr(1),
 			Query: finalQuery,
@@ -288,7 +346,7 @@ func (er *EntityResolver) resolveWithTypedQuery(typeMapping *EntityTypeMapping,
 	hit := resp.Hits.Hits[0]
 	var source map[string]any
 	if hit.Source_ != nil {
-		if err := json.Unmarshal(hit.Source_, &source); err != nil {
+		if err := decodeSource(hit.Source_, &source); err != nil {
 			return nil, fmt.Errorf("failed to parse hit source: %w", err)
 		}
 	}
@@ -297,9 +355,10 @@ func (er *EntityResolver) resolveWithTypedQuery(typeMapping *EntityTypeMapping,
 	if _, hasID := source["id"]; !hasID {
 		source["id"] = hit.Id_
 	}
+	source[indexFieldName] = hit.Index_
 
-	// Normalize objects to arrays (same as regular queries)
-	normalizeObjectsToArrays(source, typeMapping.Mapping)
+	// Normalize object cardinality (same as regular queries)
+	normalizeObjectCardinality(source, typeMapping.Mapping)
 
 	return source, nil
 }
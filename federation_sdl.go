@@ -3,6 +3,7 @@ package graphql
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/graphql-go/graphql"
@@ -106,6 +107,14 @@ func ExportFederationSDL(schema graphql.Schema, config *Config, sdlEntityKeys ma
 		case *graphql.Interface:
 			sdl.WriteString(exportInterfaceType(t, enableFederation, sdlEntityKeys[typeName]))
 			sdl.WriteString("\n")
+
+		case *graphql.Scalar:
+			// Built-in scalars are implicit and _Any is written with the federation types
+			if isBuiltInScalar(typeName) || typeName == "_Any" {
+				continue
+			}
+			sdl.WriteString(exportScalarType(t))
+			sdl.WriteString("\n")
 		}
 	}
 
@@ -118,6 +127,28 @@ func ExportFederationSDL(schema graphql.Schema, config *Config, sdlEntityKeys ma
 	return sdl.String()
 }
 
+// isBuiltInScalar reports whether a scalar is one of the GraphQL built-in scalars
+func isBuiltInScalar(name string) bool {
+	switch name {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return true
+	default:
+		return false
+	}
+}
+
+// exportScalarType exports a custom GraphQL scalar as SDL
+func exportScalarType(scalarType *graphql.Scalar) string {
+	var sdl strings.Builder
+
+	if scalarType.Description() != "" {
+		sdl.WriteString(fmt.Sprintf("# %s\n", scalarType.Description()))
+	}
+	sdl.WriteString(fmt.Sprintf("scalar %s\n", scalarType.Name()))
+
+	return sdl.String()
+}
+
 // exportObjectType exports a GraphQL object type as SDL
 func exportObjectType(objType *graphql.Object, enableFederation bool, isExtended bool, entityKeyFields []string, isResolvable bool, typeFieldDirectives map[string]map[string]string) string {
 	var sdl strings.Builder
@@ -218,6 +249,7 @@ func exportObjectType(objType *graphql.Object, enableFederation bool, isExtended
 		}
 
 		fieldDecl := fmt.Sprintf("  %s%s: %s", fieldName, argsStr, fieldType)
+		fieldDecl += deprecatedDirective(field.DeprecationReason)
 
 		// Add field directives (e.g., @external, @requires)
 		if enableFederation && typeFieldDirectives != nil {
@@ -253,13 +285,25 @@ func exportEnumType(enumType *graphql.Enum) string {
 		if value.Description != "" {
 			sdl.WriteString(fmt.Sprintf("  \"\"\"%s\"\"\"\n", value.Description))
 		}
-		sdl.WriteString(fmt.Sprintf("  %s\n", value.Name))
+		sdl.WriteString(fmt.Sprintf("  %s%s\n", value.Name, deprecatedDirective(value.DeprecationReason)))
 	}
 
 	sdl.WriteString("}\n")
 	return sdl.String()
 }
 
+// deprecatedDirective returns the @deprecated directive for a deprecation reason, "" if not deprecated
+func deprecatedDirective(reason string) string {
+	switch reason {
+	case "":
+		return ""
+	case graphql.DefaultDeprecationReason:
+		return " @deprecated"
+	default:
+		return fmt.Sprintf(" @deprecated(reason: %s)", strconv.Quote(reason))
+	}
+}
+
 // exportInterfaceType exports a GraphQL interface type as SDL
 func exportInterfaceType(interfaceType *graphql.Interface, enableFederation bool, entityKeyFields []string) string {
 	var sdl strings.Builder
@@ -299,7 +343,7 @@ func exportInterfaceType(interfaceType *graphql.Interface, enableFederation bool
 			sdl.WriteString(fmt.Sprintf("  \"\"\"%s\"\"\"\n", field.Description))
 		}
 		fieldType := exportType(field.Type)
-		sdl.WriteString(fmt.Sprintf("  %s: %s\n", fieldName, fieldType))
+		sdl.WriteString(fmt.Sprintf("  %s: %s%s\n", fieldName, fieldType, deprecatedDirective(field.DeprecationReason)))
 	}
 
 	sdl.WriteString("}\n")
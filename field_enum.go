@@ -0,0 +1,185 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+)
+
+// defaultEnumSampleSize is the number of values sampled for a FieldEnum without SampleSize
+const defaultEnumSampleSize = 100
+
+// FieldEnum exposes a keyword field with a known set of values as a GraphQL enum
+// The enum is used for the document field and its filter argument. Enum value names are
+// sanitised (e.g., "in-progress" → IN_PROGRESS) while ES receives and returns the original
+// values; values outside the set resolve to null.
+type FieldEnum struct {
+	// Values are the known values of the field
+	Values []string
+
+	// Sample adds the values found by a terms aggregation when the API is created with an ES client
+	// (see SampleFieldEnums); without a client only Values are used
+	Sample bool
+
+	// SampleSize is the maximum number of sampled values (default 100)
+	SampleSize int
+
+	// TypeName is the name of the enum type (default "{Index}{Field}", e.g., "LeadsStatus")
+	// Queries using the same TypeName share the enum type
+	TypeName string
+}
+
+// withFieldEnums returns a copy of the mapping with enum values set on the fields
+// Fields without values (e.g., sampled enums without an ES client) keep their type
+func (m IndexMapping) withFieldEnums(enums map[string]FieldEnum) IndexMapping {
+	if len(enums) == 0 {
+		return m
+	}
+
+	copied := MergeMappings(m.IndexName, m)
+	for path, enum := range enums {
+		field := copied.GetField(path)
+		if field == nil || len(enum.Values) == 0 {
+			continue
+		}
+
+		field.EnumValues = enum.Values
+		field.EnumTypeName = enum.TypeName
+		if field.EnumTypeName == "" {
+			field.EnumTypeName = sanitizeTypeName(m.IndexName + "." + path)
+		}
+	}
+	return copied
+}
+
+// validateFieldEnums checks that the query's enums are on keyword fields and their values have distinct names
+func (sg *SchemaGenerator) validateFieldEnums(queryConfig *QueryConfig, mapping *IndexMapping) error {
+	for path := range queryConfig.FieldEnums {
+		field := mapping.GetField(path)
+		if field == nil {
+			return fmt.Errorf("enum field %s not found", path)
+		}
+		if field.Type != FieldTypeKeyword && field.Type != FieldTypeConstantKeyword {
+			return fmt.Errorf("enum field %s must be a keyword field, got %s", path, field.Type)
+		}
+		if len(field.EnumValues) == 0 {
+			continue
+		}
+		if _, err := sg.enumType(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enumType creates (or returns the cached) enum type of a field with enum values
+func (sg *SchemaGenerator) enumType(field *Field) (*graphql.Enum, error) {
+	if cached, ok := sg.enumCache[field.EnumTypeName]; ok {
+		return cached, nil
+	}
+
+	values := graphql.EnumValueConfigMap{}
+	for _, value := range field.EnumValues {
+		name := enumValueName(value)
+		if existing, ok := values[name]; ok {
+			if existing.Value == value {
+				continue
+			}
+			return nil, fmt.Errorf("enum %s: values %q and %q both map to %s", field.EnumTypeName, existing.Value, value, name)
+		}
+		values[name] = &graphql.EnumValueConfig{
+			Value:       value,
+			Description: value,
+		}
+	}
+
+	enum := graphql.NewEnum(graphql.EnumConfig{
+		Name:   field.EnumTypeName,
+		Values: values,
+	})
+	sg.enumCache[field.EnumTypeName] = enum
+	return enum, nil
+}
+
+// enumValueName converts a field value to an enum value name in SCREAMING_SNAKE_CASE
+// (e.g., "in-progress" → IN_PROGRESS, "newCustomer" → NEW_CUSTOMER, "2fa" → _2FA)
+func enumValueName(value string) string {
+	var result strings.Builder
+	runes := []rune(sanitizeFieldName(value))
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			result.WriteRune('_')
+		}
+		result.WriteRune(unicode.ToUpper(r))
+	}
+	return result.String()
+}
+
+// SampleFieldEnums adds the values of sampled FieldEnums (Sample: true) of all queries,
+// found with a terms aggregation on each field, to their Values
+// New calls it when an ES client is configured, call it before GenerateSchemaSDL to
+// export the sampled enums
+func SampleFieldEnums(ctx context.Context, client *elasticsearch.TypedClient, config *Config) error {
+	if client == nil {
+		return fmt.Errorf("ES client not configured")
+	}
+
+	for queryName, queryConfig := range config.Queries {
+		mapping := queryConfig.GetMapping()
+		for path, enum := range queryConfig.FieldEnums {
+			if !enum.Sample {
+				continue
+			}
+
+			sampled, err := sampleFieldValues(ctx, client, queryConfig.GetIndices(), exactFieldPath(path, &mapping), enum.SampleSize)
+			if err != nil {
+				return fmt.Errorf("failed to sample enum values of %s in query %s: %w", path, queryName, err)
+			}
+			enum.Values = mergeEnumValues(enum.Values, sampled)
+			queryConfig.FieldEnums[path] = enum
+		}
+	}
+	return nil
+}
+
+// sampleFieldValues returns the most common values of a field
+func sampleFieldValues(ctx context.Context, client *elasticsearch.TypedClient, indices []string, field string, size int) ([]string, error) {
+	if size <= 0 {
+		size = defaultEnumSampleSize
+	}
+
+	limit := 0
+	aggs := map[string]types.Aggregations{
+		"values": {Terms: &types.TermsAggregation{Field: &field, Size: &size}},
+	}
+	result, err := executeTypedQuery(ctx, client, indices, nil, aggs, &limit, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, bucket := range result.Aggregations["values"] {
+		values = append(values, fmt.Sprint(bucket.Value))
+	}
+	return values, nil
+}
+
+// mergeEnumValues adds sampled values to the static values, sorted and without duplicates
+func mergeEnumValues(values, sampled []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, value := range append(append([]string{}, values...), sampled...) {
+		if !seen[value] {
+			seen[value] = true
+			merged = append(merged, value)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/reveald/reveald/v2"
+)
+
+func TestFieldEnums(t *testing.T) {
+	mapping := IndexMapping{
+		IndexName: "leads",
+		Properties: map[string]*Field{
+			"status":   {Name: "status", Type: FieldTypeKeyword},
+			"leadType": {Name: "leadType", Type: FieldTypeKeyword},
+			"title":    {Name: "title", Type: FieldTypeText},
+		},
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		config := NewConfig(
+			WithQuery("leads", &QueryConfig{
+				Mapping: mapping,
+				FieldEnums: map[string]FieldEnum{
+					"status":   {Values: []string{"open", "in-progress", "closed"}},
+					"leadType": {Values: []string{"newCustomer"}, TypeName: "LeadType"},
+				},
+			}),
+		)
+
+		assertContains(t, generateSDL(t, config),
+			"enum LeadsStatus {",
+			"IN_PROGRESS",
+			"enum LeadType {",
+			"NEW_CUSTOMER",
+			"status: LeadsStatus",
+			"leadType: LeadType",
+			"status: [LeadsStatus]",
+		)
+	})
+
+	t.Run("sampled without client", func(t *testing.T) {
+		config := NewConfig(
+			WithQuery("leads", &QueryConfig{
+				Mapping:    mapping,
+				FieldEnums: map[string]FieldEnum{"status": {Sample: true}},
+			}),
+		)
+
+		// Sampled enums without values stay a String
+		sdl := generateSDL(t, config)
+		assertContains(t, sdl, "status: String")
+		assertNotContains(t, sdl, "enum LeadsStatus")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := map[string]map[string]FieldEnum{
+			"collides": {"status": {Values: []string{"open", "Open"}}},
+			"text":     {"title": {Values: []string{"a"}}},
+			"missing":  {"unknown": {Values: []string{"a"}}},
+		}
+
+		for name, enums := range tests {
+			t.Run(name, func(t *testing.T) {
+				config := NewConfig(WithQuery("leads", &QueryConfig{Mapping: mapping, FieldEnums: enums}))
+				if _, err := GenerateSchemaSDL(config); err == nil {
+					t.Error("Expected an error")
+				}
+			})
+		}
+	})
+
+	t.Run("execution", func(t *testing.T) {
+		backend := &recordingBackend{hits: []map[string]any{
+			{"status": "in-progress"},
+			{"status": "unknown"},
+		}}
+		api, err := New(backend, NewConfig(
+			WithQuery("leads", &QueryConfig{
+				Mapping:    mapping,
+				Features:   []reveald.Feature{&mockWrapperFeature{}},
+				FieldEnums: map[string]FieldEnum{"status": {Values: []string{"open", "in-progress"}}},
+			}),
+		))
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+
+		data := runQuery(t, api, `{ leads(status: [IN_PROGRESS, OPEN]) { hits { status } } }`)
+
+		// Filters reach ES with the original values
+		param, err := backend.request.Get("status")
+		if err != nil {
+			t.Fatalf("Expected status parameter: %v", err)
+		}
+		if strings.Join(param.Values(), ",") != "in-progress,open" {
+			t.Errorf("Expected original values, got %v", param.Values())
+		}
+
+		// Values are returned as enum names, unknown values as null
+		hits := data["leads"].(map[string]any)["hits"].([]any)
+		if status := hits[0].(map[string]any)["status"]; status != "IN_PROGRESS" {
+			t.Errorf("Expected IN_PROGRESS, got %v", status)
+		}
+		if status := hits[1].(map[string]any)["status"]; status != nil {
+			t.Errorf("Expected null for unknown value, got %v", status)
+		}
+	})
+}
+
+func TestEnumValueName(t *testing.T) {
+	tests := map[string]string{
+		"open":        "OPEN",
+		"in-progress": "IN_PROGRESS",
+		"newCustomer": "NEW_CUSTOMER",
+		"2fa":         "_2FA",
+		"a.b c":       "A_B_C",
+	}
+	for value, expected := range tests {
+		if got := enumValueName(value); got != expected {
+			t.Errorf("enumValueName(%q) = %q, want %q", value, got, expected)
+		}
+	}
+}
+
+func TestMergeEnumValues(t *testing.T) {
+	got := mergeEnumValues([]string{"open", "closed"}, []string{"open", "won"})
+	if strings.Join(got, ",") != "closed,open,won" {
+		t.Errorf("Unexpected merged values: %v", got)
+	}
+}
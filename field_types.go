@@ -0,0 +1,267 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// GeoPoint is the output type for ES geo_point fields
+// Source values in any of the ES formats (object, "lat,lon" string, [lon, lat] array,
+// geohash or WKT POINT) are normalised to latitude/longitude
+var GeoPoint = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "GeoPoint",
+	Description: "A geographic point",
+	Fields: graphql.Fields{
+		"lat": &graphql.Field{Type: graphql.Float, Description: "Latitude"},
+		"lon": &graphql.Field{Type: graphql.Float, Description: "Longitude"},
+	},
+})
+
+// rangeTypeNames maps ES range field types to the names of their GraphQL output types
+var rangeTypeNames = map[FieldType]string{
+	FieldTypeIntegerRange: "IntegerRange",
+	FieldTypeLongRange:    "LongRange",
+	FieldTypeFloatRange:   "FloatRange",
+	FieldTypeDoubleRange:  "DoubleRange",
+	FieldTypeDateRange:    "DateRange",
+	FieldTypeIPRange:      "IpRange",
+}
+
+// rangeBoundType returns the GraphQL type of the bounds (and filter value) of a range field
+func (sg *SchemaGenerator) rangeBoundType(fieldType FieldType) *graphql.Scalar {
+	switch fieldType {
+	case FieldTypeIntegerRange:
+		return graphql.Int
+	case FieldTypeLongRange:
+		return sg.longType()
+	case FieldTypeFloatRange, FieldTypeDoubleRange:
+		return graphql.Float
+	case FieldTypeDateRange:
+		return DateTime
+	default:
+		return graphql.String
+	}
+}
+
+// rangeType creates (or returns the cached) output type for a range field
+func (sg *SchemaGenerator) rangeType(fieldType FieldType) *graphql.Object {
+	typeName := rangeTypeNames[fieldType]
+	if cached, ok := sg.typeCache[typeName]; ok {
+		return cached
+	}
+
+	boundType := sg.rangeBoundType(fieldType)
+	rangeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: typeName,
+		Fields: graphql.Fields{
+			"gte": &graphql.Field{Type: boundType},
+			"gt":  &graphql.Field{Type: boundType},
+			"lte": &graphql.Field{Type: boundType},
+			"lt":  &graphql.Field{Type: boundType},
+		},
+	})
+
+	sg.typeCache[typeName] = rangeType
+	return rangeType
+}
+
+// isRangeFieldType reports whether a field type is one of the ES range types
+func isRangeFieldType(fieldType FieldType) bool {
+	_, ok := rangeTypeNames[fieldType]
+	return ok
+}
+
+// convertDocumentField converts a top-level mapping field to a GraphQL field
+// Alias fields take the type of their target and resolve the target's value,
+// since aliases are not present in the document source
+func (sg *SchemaGenerator) convertDocumentField(field *Field, mapping *IndexMapping) (*graphql.Field, error) {
+	if field.Type != FieldTypeAlias {
+		return sg.convertFieldToGraphQL(field)
+	}
+
+	target := mapping.GetField(field.Path)
+	if target == nil || target.Type == FieldTypeAlias {
+		return nil, fmt.Errorf("alias %s points to unknown field %q", field.Name, field.Path)
+	}
+
+	gqlField, err := sg.convertFieldToGraphQL(target)
+	if err != nil {
+		return nil, err
+	}
+
+	aliased := *gqlField
+	aliased.Resolve = aliasFieldResolver(field.Path, target.Name, gqlField.Resolve)
+	return &aliased, nil
+}
+
+// aliasFieldResolver resolves an alias by reading its target path from the source document
+// The target's own resolver (e.g., for DateTime formatting) is applied to the value
+func aliasFieldResolver(path, targetName string, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		value := selectJSONPath(p.Source, path)
+		if resolve == nil {
+			return value, nil
+		}
+
+		p.Source = map[string]any{targetName: value}
+		return resolve(p)
+	}
+}
+
+// resolveAliasField returns the target of an alias field, or the field itself
+func resolveAliasField(field *Field, mapping *IndexMapping) *Field {
+	if field.Type != FieldTypeAlias {
+		return field
+	}
+	if target := mapping.GetField(field.Path); target != nil {
+		return target
+	}
+	return field
+}
+
+// geoPointResolver resolves a geo_point field from the source document
+func geoPointResolver(field *Field) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+
+		point, ok := parseGeoPoint(source[field.Name])
+		if !ok {
+			return nil, nil
+		}
+		return point, nil
+	}
+}
+
+// parseGeoPoint parses a geo_point value in any of the formats accepted by Elasticsearch
+// Multi-valued fields return their first point
+func parseGeoPoint(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		lat, latOK := toFloat(v["lat"])
+		lon, lonOK := toFloat(v["lon"])
+		if latOK && lonOK {
+			return geoPointValue(lat, lon), true
+		}
+		// GeoJSON point
+		if coords, ok := v["coordinates"].([]any); ok {
+			return parseGeoPoint(coords)
+		}
+	case []any:
+		if len(v) >= 2 {
+			lon, lonOK := toFloat(v[0])
+			lat, latOK := toFloat(v[1])
+			if latOK && lonOK {
+				return geoPointValue(lat, lon), true
+			}
+		}
+		if len(v) > 0 {
+			return parseGeoPoint(v[0])
+		}
+	case string:
+		return parseGeoPointString(v)
+	}
+	return nil, false
+}
+
+// parseGeoPointString parses "lat,lon", WKT "POINT (lon lat)" and geohash strings
+func parseGeoPointString(value string) (map[string]any, bool) {
+	value = strings.TrimSpace(value)
+
+	if latStr, lonStr, ok := strings.Cut(value, ","); ok {
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+		if latErr != nil || lonErr != nil {
+			return nil, false
+		}
+		return geoPointValue(lat, lon), true
+	}
+
+	if upper := strings.ToUpper(value); strings.HasPrefix(upper, "POINT") {
+		coords := strings.Fields(strings.Trim(strings.TrimSpace(value[len("POINT"):]), "()"))
+		if len(coords) < 2 {
+			return nil, false
+		}
+		lon, lonErr := strconv.ParseFloat(coords[0], 64)
+		lat, latErr := strconv.ParseFloat(coords[1], 64)
+		if latErr != nil || lonErr != nil {
+			return nil, false
+		}
+		return geoPointValue(lat, lon), true
+	}
+
+	lat, lon, ok := decodeGeohash(value)
+	if !ok {
+		return nil, false
+	}
+	return geoPointValue(lat, lon), true
+}
+
+// geohashAlphabet is the base32 alphabet used by geohashes
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// decodeGeohash returns the center of a geohash cell
+func decodeGeohash(hash string) (float64, float64, bool) {
+	if hash == "" {
+		return 0, 0, false
+	}
+
+	latMin, latMax := -90.0, 90.0
+	lonMin, lonMax := -180.0, 180.0
+	even := true
+
+	for _, c := range strings.ToLower(hash) {
+		idx := strings.IndexRune(geohashAlphabet, c)
+		if idx < 0 {
+			return 0, 0, false
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := idx&(1<<bit) != 0
+			if even {
+				mid := (lonMin + lonMax) / 2
+				if set {
+					lonMin = mid
+				} else {
+					lonMax = mid
+				}
+			} else {
+				mid := (latMin + latMax) / 2
+				if set {
+					latMin = mid
+				} else {
+					latMax = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return (latMin + latMax) / 2, (lonMin + lonMax) / 2, true
+}
+
+func geoPointValue(lat, lon float64) map[string]any {
+	return map[string]any{"lat": lat, "lon": lon}
+}
+
+// toFloat converts a numeric JSON value to float64
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,184 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestExtendedFieldTypes(t *testing.T) {
+	mapping, err := ParseMapping("hosts", []byte(`{
+		"mappings": {
+			"properties": {
+				"hostname":  {"type": "wildcard"},
+				"address":   {"type": "ip"},
+				"location":  {"type": "geo_point"},
+				"area":      {"type": "geo_shape"},
+				"tenant":    {"type": "constant_keyword"},
+				"notes":     {"type": "match_only_text"},
+				"title":     {"type": "search_as_you_type"},
+				"host":      {"type": "alias", "path": "hostname"},
+				"ports":     {"type": "integer_range"},
+				"uptime":    {"type": "date_range"},
+				"price":     {"type": "scaled_float", "scaling_factor": 100},
+				"load":      {"type": "half_float"},
+				"embedding": {"type": "dense_vector", "dims": 3, "similarity": "cosine"},
+				"release":   {"type": "version"}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("hosts", &QueryConfig{Mapping: mapping})))
+		assertContains(t, sdl,
+			"hostname: String",
+			"address: String",
+			"location: GeoPoint",
+			"area(path: String): JSON",
+			"tenant: String",
+			"notes: String",
+			"title: String",
+			"host: String",
+			"ports: IntegerRange",
+			"uptime: DateRange",
+			"price: Float",
+			"load: Float",
+			"embedding: [Float]",
+			"release: String",
+			"type GeoPoint",
+			"type IntegerRange",
+		)
+
+		// Filter arguments
+		queryField := sdl[strings.Index(sdl, "hosts("):]
+		queryField = queryField[:strings.Index(queryField, "\n")]
+		assertContains(t, queryField,
+			"hostname: [String]",
+			"address: [String]",
+			"tenant: [String]",
+			"host: [String]",
+			"ports: Int",
+			"uptime: DateTime",
+			"price: Float",
+			"release: [String]",
+		)
+		assertNotContains(t, queryField, "location:", "embedding:", "notes:")
+	})
+
+	t.Run("mapping JSON", func(t *testing.T) {
+		data, err := mapping.MappingJSON()
+		if err != nil {
+			t.Fatalf("Failed to export mapping: %v", err)
+		}
+
+		parsed, err := ParseMapping("hosts", data)
+		if err != nil {
+			t.Fatalf("Failed to parse exported mapping: %v", err)
+		}
+		if field := parsed.GetField("host"); field.Path != "hostname" {
+			t.Errorf("Expected alias path to round-trip, got %q", field.Path)
+		}
+		if field := parsed.GetField("price"); field.ScalingFactor != 100 {
+			t.Errorf("Expected scaling factor to round-trip, got %v", field.ScalingFactor)
+		}
+		if field := parsed.GetField("embedding"); field.Dims != 3 || field.Similarity != "cosine" {
+			t.Errorf("Expected dims and similarity to round-trip, got %v and %q", field.Dims, field.Similarity)
+		}
+	})
+
+	t.Run("numeric filters", func(t *testing.T) {
+		numeric, err := ParseMapping("metrics", []byte(`{
+			"mappings": {
+				"properties": {
+					"l":  {"type": "long"},
+					"ul": {"type": "unsigned_long"},
+					"i":  {"type": "integer"},
+					"s":  {"type": "short"},
+					"b":  {"type": "byte"},
+					"d":  {"type": "double"},
+					"f":  {"type": "float"},
+					"hf": {"type": "half_float"},
+					"sf": {"type": "scaled_float", "scaling_factor": 100}
+				}
+			}
+		}`))
+		if err != nil {
+			t.Fatalf("Failed to parse mapping: %v", err)
+		}
+
+		// Every numeric type gets a filter argument of its GraphQL type
+		sdl := generateSDL(t, NewConfig(WithQuery("metrics", &QueryConfig{Mapping: numeric})))
+		queryField := sdl[strings.Index(sdl, "metrics("):]
+		queryField = queryField[:strings.Index(queryField, "\n")]
+		assertContains(t, queryField,
+			"l: Long", "ul: Long", "i: Int", "s: Int", "b: Int",
+			"d: Float", "f: Float", "hf: Float", "sf: Float",
+		)
+	})
+}
+
+func TestAliasFieldUnknownTarget(t *testing.T) {
+	config := NewConfig(
+		WithQuery("hosts", &QueryConfig{
+			Mapping: IndexMapping{
+				IndexName: "hosts",
+				Properties: map[string]*Field{
+					"host": {Name: "host", Type: FieldTypeAlias, Path: "missing"},
+				},
+			},
+		}),
+	)
+
+	if _, err := GenerateSchemaSDL(config); err == nil {
+		t.Error("Expected error for alias with unknown target")
+	}
+}
+
+func TestAliasFieldResolver(t *testing.T) {
+	resolve := aliasFieldResolver("server.createdAt", "createdAt", dateFieldResolver(&Field{Name: "createdAt", Type: FieldTypeDate}))
+	source := map[string]any{"server": map[string]any{"createdAt": "2024-01-15T10:30:00Z"}}
+
+	value, err := resolve(graphql.ResolveParams{Source: source, Args: map[string]any{"format": "yyyy-MM-dd"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := DateTime.Serialize(value); got != "2024-01-15" {
+		t.Errorf("Expected aliased date to be formatted, got %v", got)
+	}
+}
+
+func TestParseGeoPoint(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"object", map[string]any{"lat": 59.33, "lon": 18.07}},
+		{"string", "59.33,18.07"},
+		{"array", []any{18.07, 59.33}},
+		{"WKT", "POINT (18.07 59.33)"},
+		{"GeoJSON", map[string]any{"type": "Point", "coordinates": []any{18.07, 59.33}}},
+		{"geohash", "u6sce0t4hz"},
+		{"multi-valued", []any{"59.33,18.07", "0,0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			point, ok := parseGeoPoint(tt.value)
+			if !ok {
+				t.Fatalf("Failed to parse %v", tt.value)
+			}
+			lat, lon := point["lat"].(float64), point["lon"].(float64)
+			if lat < 59.32 || lat > 59.34 || lon < 18.06 || lon > 18.08 {
+				t.Errorf("Expected 59.33,18.07, got %v,%v", lat, lon)
+			}
+		})
+	}
+
+	if _, ok := parseGeoPoint("not a point!"); ok {
+		t.Error("Expected invalid point to fail")
+	}
+}
@@ -0,0 +1,494 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/distanceunit"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	// distanceKey is the hit key holding the distance to the origin of a distance sort
+	distanceKey = "_distance"
+
+	// defaultDistanceUnit is the unit of sort distances by default
+	defaultDistanceUnit = "km"
+)
+
+// distanceUnits are the units of sort distances
+var distanceUnits = map[string]distanceunit.DistanceUnit{
+	"km":  distanceunit.Kilometers,
+	"m":   distanceunit.Meters,
+	"mi":  distanceunit.Miles,
+	"yd":  distanceunit.Yards,
+	"ft":  distanceunit.Feet,
+	"nmi": distanceunit.Nauticmiles,
+}
+
+// GeoPointInput is the input type for a geo point
+var GeoPointInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "GeoPointInput",
+	Description: "A geographic point",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"lat": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float), Description: "Latitude"},
+		"lon": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float), Description: "Longitude"},
+	},
+})
+
+// GeoDistanceQueryInput matches geo points within a distance of an origin
+var GeoDistanceQueryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ESGeoDistanceQueryInput",
+	Description: "Matches geo points within a distance of an origin",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"origin":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(GeoPointInput)},
+		"distance": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String), Description: "Distance with a unit (e.g., \"50km\")"},
+	},
+})
+
+// GeoBoundingBoxQueryInput matches geo points within a bounding box
+var GeoBoundingBoxQueryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ESGeoBoundingBoxQueryInput",
+	Description: "Matches geo points within a bounding box",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"topLeft":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(GeoPointInput)},
+		"bottomRight": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(GeoPointInput)},
+	},
+})
+
+// GeoPolygonQueryInput matches geo points within a polygon
+var GeoPolygonQueryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ESGeoPolygonQueryInput",
+	Description: "Matches geo points within a polygon",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"points": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(GeoPointInput))), Description: "Vertices of the polygon (at least 3)"},
+	},
+})
+
+// GeoDistanceSortInput sorts hits by their distance to an origin
+// Queries get a copy of it with their geo fields as the field (see geoArgumentType)
+var GeoDistanceSortInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "GeoDistanceSortInput",
+	Description: "Sorts the hits by their distance to an origin",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"origin": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(GeoPointInput)},
+		"order":  &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "\"asc\" (default, nearest first) or \"desc\""},
+		"unit":   &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Unit of the hits' distance: km (default), m, mi, yd, ft or nmi"},
+	},
+})
+
+// readGeoPointInput reads the value of a GeoPointInput
+func readGeoPointInput(value any) (ESGeoPointInput, error) {
+	point, ok := value.(map[string]any)
+	if !ok {
+		return ESGeoPointInput{}, fmt.Errorf("geo point must be an object")
+	}
+	lat, latOK := point["lat"].(float64)
+	lon, lonOK := point["lon"].(float64)
+	if !latOK || !lonOK {
+		return ESGeoPointInput{}, fmt.Errorf("geo point needs lat and lon")
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return ESGeoPointInput{}, fmt.Errorf("geo point (%v, %v) is out of range", lat, lon)
+	}
+	return ESGeoPointInput{Lat: lat, Lon: lon}, nil
+}
+
+// readGeoDistanceInput reads the value of a GeoDistanceQueryInput
+func readGeoDistanceInput(input map[string]any) (*ESGeoDistanceQueryInput, error) {
+	origin, err := readGeoPointInput(input["origin"])
+	if err != nil {
+		return nil, fmt.Errorf("geoDistance origin: %w", err)
+	}
+	field, _ := input["field"].(string)
+	distance, _ := input["distance"].(string)
+	return &ESGeoDistanceQueryInput{Field: field, Origin: origin, Distance: distance}, nil
+}
+
+// readGeoBoundingBoxInput reads the value of a GeoBoundingBoxQueryInput
+func readGeoBoundingBoxInput(input map[string]any) (*ESGeoBoundingBoxQueryInput, error) {
+	topLeft, err := readGeoPointInput(input["topLeft"])
+	if err != nil {
+		return nil, fmt.Errorf("geoBoundingBox topLeft: %w", err)
+	}
+	bottomRight, err := readGeoPointInput(input["bottomRight"])
+	if err != nil {
+		return nil, fmt.Errorf("geoBoundingBox bottomRight: %w", err)
+	}
+	field, _ := input["field"].(string)
+	return &ESGeoBoundingBoxQueryInput{Field: field, TopLeft: topLeft, BottomRight: bottomRight}, nil
+}
+
+// readGeoPolygonInput reads the value of a GeoPolygonQueryInput
+func readGeoPolygonInput(input map[string]any) (*ESGeoPolygonQueryInput, error) {
+	field, _ := input["field"].(string)
+	polygon := &ESGeoPolygonQueryInput{Field: field}
+	points, _ := input["points"].([]any)
+	for _, value := range points {
+		point, err := readGeoPointInput(value)
+		if err != nil {
+			return nil, fmt.Errorf("geoPolygon points: %w", err)
+		}
+		polygon.Points = append(polygon.Points, point)
+	}
+	return polygon, nil
+}
+
+// latLon converts a geo point input to an ES geo location
+func (p ESGeoPointInput) latLon() types.GeoLocation {
+	return types.LatLonGeoLocation{Lat: types.Float64(p.Lat), Lon: types.Float64(p.Lon)}
+}
+
+// geoDistanceQuery converts a geo distance input to a geo_distance query
+func geoDistanceQuery(input *ESGeoDistanceQueryInput) (*types.Query, error) {
+	if input.Distance == "" {
+		return nil, fmt.Errorf("geoDistance needs a distance")
+	}
+	return &types.Query{GeoDistance: &types.GeoDistanceQuery{
+		Distance:         input.Distance,
+		GeoDistanceQuery: map[string]types.GeoLocation{input.Field: input.Origin.latLon()},
+	}}, nil
+}
+
+// geoBoundingBoxQuery converts a bounding box input to a geo_bounding_box query
+func geoBoundingBoxQuery(input *ESGeoBoundingBoxQueryInput) *types.Query {
+	return &types.Query{GeoBoundingBox: &types.GeoBoundingBoxQuery{
+		GeoBoundingBoxQuery: map[string]types.GeoBounds{input.Field: types.TopLeftBottomRightGeoBounds{
+			TopLeft:     input.TopLeft.latLon(),
+			BottomRight: input.BottomRight.latLon(),
+		}},
+	}}
+}
+
+// geoPolygonQuery converts a polygon input to a geo_shape query, which replaces the deprecated
+// geo_polygon query for geo_point fields
+func geoPolygonQuery(input *ESGeoPolygonQueryInput) (*types.Query, error) {
+	if len(input.Points) < 3 {
+		return nil, fmt.Errorf("geoPolygon needs at least 3 points, got %d", len(input.Points))
+	}
+
+	// GeoJSON polygons are closed rings of [lon, lat] coordinates
+	ring := make([][]float64, 0, len(input.Points)+1)
+	for _, point := range input.Points {
+		ring = append(ring, []float64{point.Lon, point.Lat})
+	}
+	if first, last := input.Points[0], input.Points[len(input.Points)-1]; first != last {
+		ring = append(ring, []float64{first.Lon, first.Lat})
+	}
+	shape, err := json.Marshal(map[string]any{"type": "polygon", "coordinates": [][][]float64{ring}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode geoPolygon: %w", err)
+	}
+
+	return &types.Query{GeoShape: &types.GeoShapeQuery{
+		GeoShapeQuery: map[string]types.GeoShapeFieldQuery{input.Field: {Shape: shape}},
+	}}, nil
+}
+
+// geoGridAggregations converts geohash_grid and geotile_grid inputs to aggregations
+func geoGridAggregations(hashGrid, tileGrid *ESGeoGridAggInput) (*types.GeoHashGridAggregation, *types.GeoTileGridAggregation) {
+	var hash *types.GeoHashGridAggregation
+	if hashGrid != nil {
+		hash = &types.GeoHashGridAggregation{Field: &hashGrid.Field, Size: hashGrid.Size}
+		if hashGrid.Precision != nil {
+			hash.Precision = *hashGrid.Precision
+		}
+	}
+	var tile *types.GeoTileGridAggregation
+	if tileGrid != nil {
+		tile = &types.GeoTileGridAggregation{Field: &tileGrid.Field, Precision: tileGrid.Precision, Size: tileGrid.Size}
+	}
+	return hash, tile
+}
+
+// geoCentroidValue converts a geo_centroid aggregate to a GeoCentroid value
+func geoCentroidValue(agg *types.GeoCentroidAggregate) map[string]any {
+	value := map[string]any{"count": agg.Count}
+	if agg.Location == nil {
+		return value
+	}
+	data, err := json.Marshal(agg.Location)
+	if err != nil {
+		return value
+	}
+	var location any
+	if err := json.Unmarshal(data, &location); err != nil {
+		return value
+	}
+	if point, ok := parseGeoPoint(location); ok {
+		value["location"] = point
+	}
+	return value
+}
+
+// geoCentroidType returns the type of geo_centroid aggregation results
+func (sg *SchemaGenerator) geoCentroidType() *graphql.Object {
+	if cached, ok := sg.typeCache["GeoCentroid"]; ok {
+		return cached
+	}
+	centroidType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "GeoCentroid",
+		Description: "Centroid of the geo points of an aggregation",
+		Fields: graphql.Fields{
+			"location": &graphql.Field{
+				Type:        GeoPoint,
+				Description: "The centroid",
+			},
+			"count": &graphql.Field{
+				Type:        sg.longType(),
+				Description: "Number of geo points",
+			},
+		},
+	})
+	sg.typeCache["GeoCentroid"] = centroidType
+	return centroidType
+}
+
+// validateGeoFields checks that the query's geo fields are geo_point fields
+func validateGeoFields(paths []string, mapping *IndexMapping) error {
+	for _, path := range paths {
+		field := mapping.GetField(path)
+		if field == nil {
+			return fmt.Errorf("geo field %s not found", path)
+		}
+		if field.Type != FieldTypeGeoPoint {
+			return fmt.Errorf("geo field %s must be a geo_point field, got %s", path, field.Type)
+		}
+	}
+	return nil
+}
+
+// geoTypePrefix returns the prefix of the names of a query's geo enum and input types
+func geoTypePrefix(queryName string, queryConfig *QueryConfig) string {
+	return strings.TrimSuffix(resultTypeName(queryName, queryConfig), "Result") + "Geo"
+}
+
+// addGeoArguments adds the geo filter and distance sort arguments to a query with geo fields
+// Their field is an enum of the query's geo fields
+func (sg *SchemaGenerator) addGeoArguments(args graphql.FieldConfigArgument, queryName string, queryConfig *QueryConfig) error {
+	if len(queryConfig.GeoFields) == 0 {
+		return nil
+	}
+
+	prefix := geoTypePrefix(queryName, queryConfig)
+	fieldEnum, err := sg.enumType(&Field{
+		EnumTypeName: prefix + "Field",
+		EnumValues:   queryConfig.GeoFields,
+	})
+	if err != nil {
+		return err
+	}
+
+	scope := argumentScope(queryName)
+	arguments := map[string]*graphql.ArgumentConfig{
+		"geoDistance": {
+			Type:        sg.geoArgumentType(prefix+"DistanceInput", GeoDistanceQueryInput, fieldEnum),
+			Description: "Only documents within a distance of an origin",
+		},
+		"geoBoundingBox": {
+			Type:        sg.geoArgumentType(prefix+"BoundingBoxInput", GeoBoundingBoxQueryInput, fieldEnum),
+			Description: "Only documents within a bounding box",
+		},
+		"geoPolygon": {
+			Type:        sg.geoArgumentType(prefix+"PolygonInput", GeoPolygonQueryInput, fieldEnum),
+			Description: "Only documents within a polygon",
+		},
+		"sortByDistance": {
+			Type:        sg.geoArgumentType(prefix+"DistanceSortInput", GeoDistanceSortInput, fieldEnum),
+			Description: "Sort the hits by distance to an origin, setting the distance of each hit",
+		},
+	}
+	for _, name := range sortedKeys(arguments) {
+		sg.names.reserve(scope, name)
+		args[name] = arguments[name]
+	}
+	return nil
+}
+
+// geoArgumentType returns the input type of a geo argument: a copy of the geo input type with
+// the field taken from the enum of the query's geo fields
+func (sg *SchemaGenerator) geoArgumentType(typeName string, base *graphql.InputObject, fieldEnum *graphql.Enum) *graphql.InputObject {
+	if cached, ok := sg.inputCache[typeName]; ok {
+		return cached
+	}
+
+	fields := graphql.InputObjectConfigFieldMap{}
+	for name, field := range base.Fields() {
+		fields[name] = &graphql.InputObjectFieldConfig{Type: field.Type, Description: field.Description()}
+	}
+	fields["field"] = &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(fieldEnum), Description: "Geo field"}
+
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        typeName,
+		Description: base.Description(),
+		Fields:      fields,
+	})
+	sg.inputCache[typeName] = inputType
+	return inputType
+}
+
+// addDistanceFields adds the distance field, set when the hits are sorted by distance, to the
+// document types of the queries with geo fields
+func (sg *SchemaGenerator) addDistanceFields() {
+	added := make(map[string]bool)
+	for _, queryName := range sortedKeys(sg.config.Queries) {
+		queryConfig := sg.config.Queries[queryName]
+		if len(queryConfig.GeoFields) == 0 || queryConfig.HitsType != nil || queryConfig.isMultiIndex() {
+			continue
+		}
+
+		mapping := queryConfig.GetMapping()
+		docType, ok := sg.typeCache[queryDocumentTypeName(queryConfig, &mapping)]
+		if !ok || added[docType.Name()] {
+			continue
+		}
+		added[docType.Name()] = true
+
+		sg.names.reserve(docType.Name(), "distance")
+		docType.AddFieldConfig("distance", &graphql.Field{
+			Type:        graphql.Float,
+			Description: "Distance to the origin of sortByDistance, in its unit (only set when sorted by distance)",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if source, ok := p.Source.(map[string]any); ok {
+					return source[distanceKey], nil
+				}
+				return nil, nil
+			},
+		})
+	}
+}
+
+// geoFieldPath checks that a field of a geo argument is one of the query's geo fields and
+// returns its ES path
+func geoFieldPath(field string, queryConfig *QueryConfig, mapping *IndexMapping) (string, error) {
+	for _, path := range queryConfig.GeoFields {
+		if path == field {
+			return exactFieldPath(path, mapping), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not a geo field, expected one of %s", field, strings.Join(queryConfig.GeoFields, ", "))
+}
+
+// geoQuery converts the geo filter arguments of a request to queries
+func (rb *ResolverBuilder) geoQuery(args map[string]any, queryConfig *QueryConfig, mapping *IndexMapping) (*types.Query, error) {
+	if len(queryConfig.GeoFields) == 0 {
+		return nil, nil
+	}
+
+	var queries []*types.Query
+	if input, ok := args["geoDistance"].(map[string]any); ok {
+		distance, err := readGeoDistanceInput(input)
+		if err != nil {
+			return nil, err
+		}
+		if distance.Field, err = geoFieldPath(distance.Field, queryConfig, mapping); err != nil {
+			return nil, fmt.Errorf("geoDistance: %w", err)
+		}
+		query, err := geoDistanceQuery(distance)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	if input, ok := args["geoBoundingBox"].(map[string]any); ok {
+		box, err := readGeoBoundingBoxInput(input)
+		if err != nil {
+			return nil, err
+		}
+		if box.Field, err = geoFieldPath(box.Field, queryConfig, mapping); err != nil {
+			return nil, fmt.Errorf("geoBoundingBox: %w", err)
+		}
+		queries = append(queries, geoBoundingBoxQuery(box))
+	}
+	if input, ok := args["geoPolygon"].(map[string]any); ok {
+		polygon, err := readGeoPolygonInput(input)
+		if err != nil {
+			return nil, err
+		}
+		if polygon.Field, err = geoFieldPath(polygon.Field, queryConfig, mapping); err != nil {
+			return nil, fmt.Errorf("geoPolygon: %w", err)
+		}
+		query, err := geoPolygonQuery(polygon)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	return mergeQueries(queries...), nil
+}
+
+// distanceSort sorts the hits of a request by distance to an origin
+type distanceSort struct {
+	field  string
+	origin ESGeoPointInput
+	order  sortorder.SortOrder
+	unit   distanceunit.DistanceUnit
+}
+
+// distanceSortRequest returns the distance sort of a request, or nil when it is not sorted by distance
+func distanceSortRequest(args map[string]any, queryConfig *QueryConfig, mapping *IndexMapping) (*distanceSort, error) {
+	input, ok := args["sortByDistance"].(map[string]any)
+	if !ok || len(queryConfig.GeoFields) == 0 {
+		return nil, nil
+	}
+
+	field, _ := input["field"].(string)
+	path, err := geoFieldPath(field, queryConfig, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("sortByDistance: %w", err)
+	}
+	origin, err := readGeoPointInput(input["origin"])
+	if err != nil {
+		return nil, fmt.Errorf("sortByDistance origin: %w", err)
+	}
+
+	sort := &distanceSort{field: path, origin: origin, order: sortorder.Asc, unit: distanceUnits[defaultDistanceUnit]}
+	switch order, _ := input["order"].(string); strings.ToLower(order) {
+	case "", "asc":
+	case "desc":
+		sort.order = sortorder.Desc
+	default:
+		return nil, fmt.Errorf("sortByDistance: unknown order %q, expected asc or desc", order)
+	}
+	if unit, ok := input["unit"].(string); ok && unit != "" {
+		if sort.unit, ok = distanceUnits[unit]; !ok {
+			return nil, fmt.Errorf("sortByDistance: unknown unit %q, expected one of %s", unit, strings.Join(sortedKeys(distanceUnits), ", "))
+		}
+	}
+	return sort, nil
+}
+
+// apply sorts a search request by distance, before its other sort options
+func (ds *distanceSort) apply(req *search.Request) {
+	order, unit := ds.order, ds.unit
+	sort := types.SortOptions{GeoDistance_: &types.GeoDistanceSort{
+		GeoDistanceSort: map[string][]types.GeoLocation{ds.field: {ds.origin.latLon()}},
+		Order:           &order,
+		Unit:            &unit,
+	}}
+	req.Sort = append([]types.SortCombinations{sort}, req.Sort...)
+}
+
+// setDistances copies the sort distance of each hit of a response onto the result hits
+func (ds *distanceSort) setDistances(resp *search.Response, hits []map[string]any) {
+	if len(resp.Hits.Hits) != len(hits) {
+		return
+	}
+	for i, hit := range resp.Hits.Hits {
+		if len(hit.Sort) == 0 {
+			continue
+		}
+		// Documents without a location sort last with an infinite distance, which is not set
+		if distance, ok := hit.Sort[0].(float64); ok && distance < 1e300 {
+			hits[i][distanceKey] = distance
+		}
+	}
+}
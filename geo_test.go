@@ -0,0 +1,183 @@
+package graphql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+)
+
+func TestGeo(t *testing.T) {
+	queryConfig := &QueryConfig{
+		Mapping: IndexMapping{
+			IndexName: "dealers",
+			Properties: map[string]*Field{
+				"id":       {Name: "id", Type: FieldTypeKeyword},
+				"name":     {Name: "name", Type: FieldTypeText},
+				"location": {Name: "location", Type: FieldTypeGeoPoint},
+			},
+		},
+		GeoFields:             []string{"location"},
+		EnableElasticQuerying: true,
+	}
+
+	// Two hits sorted by distance
+	response := `"hits": {"total": {"value": 2, "relation": "eq"}, "hits": [
+		{"_index": "dealers", "_id": "d1", "_score": null, "_source": {"name": "Central"}, "sort": [1.25]},
+		{"_index": "dealers", "_id": "d2", "_score": null, "_source": {"name": "North"}, "sort": [8.5]}
+	]}`
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("dealers", queryConfig)))
+		assertContains(t, sdl,
+			"geoDistance: DealersGeoDistanceInput",
+			"geoBoundingBox: DealersGeoBoundingBoxInput",
+			"geoPolygon: DealersGeoPolygonInput",
+			"sortByDistance: DealersGeoDistanceSortInput",
+			"enum DealersGeoField",
+			"field: DealersGeoField!",
+			"input GeoPointInput",
+			"points: [GeoPointInput!]!",
+			"distance: Float",
+			"location: GeoPoint",
+			"geohashGrid: ESGeohashGridAggInput",
+			"geotileGrid: ESGeotileGridAggInput",
+			"geoCentroid: ESGeoCentroidAggInput",
+		)
+
+		notGeo := *queryConfig
+		notGeo.GeoFields = []string{"name"}
+		if _, err := GenerateSchemaSDL(NewConfig(WithQuery("dealers", &notGeo))); err == nil {
+			t.Error("Expected an error for a field that is not a geo_point field")
+		}
+	})
+
+	t.Run("typed query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(response))
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("dealers", queryConfig))
+
+		data := runQuery(t, api, `{ dealers(
+			geoDistance: {field: LOCATION, origin: {lat: 59.33, lon: 18.06}, distance: "10km"},
+			query: {geoBoundingBox: {field: "location", topLeft: {lat: 60, lon: 17}, bottomRight: {lat: 59, lon: 19}}},
+			aggs: [{name: "cells", geohashGrid: {field: "location", precision: 4}}, {name: "center", geoCentroid: {field: "location"}}],
+			sortByDistance: {field: LOCATION, origin: {lat: 59.33, lon: 18.06}, unit: "mi"}
+		) { totalCount hits { id distance } } }`)
+		assertContains(t, es.requests()[0],
+			`"geo_distance":{"distance":"10km","location":{"lat":59.33,"lon":18.06}}`,
+			`"geo_bounding_box":{"location":{"bottom_right":{"lat":59,"lon":19},"top_left":{"lat":60,"lon":17}}}`,
+			`"sort":[{"_geo_distance":{"location":[{"lat":59.33,"lon":18.06}],"order":"asc","unit":"mi"}}]`,
+			`"geohash_grid":{"field":"location","precision":4}`,
+			`"geo_centroid":{"field":"location"}`,
+		)
+
+		hits := data["dealers"].(map[string]any)["hits"].([]any)
+		if len(hits) != 2 || hits[0].(map[string]any)["distance"] != 1.25 || hits[1].(map[string]any)["distance"] != 8.5 {
+			t.Errorf("Expected the sort distances on the hits, got %v", hits)
+		}
+
+		// Geo arguments only accept the configured geo fields
+		for _, query := range []string{
+			`{ dealers(geoDistance: {field: NAME, origin: {lat: 1, lon: 2}, distance: "1km"}) { totalCount } }`,
+			`{ dealers(geoPolygon: {field: LOCATION, points: [{lat: 1, lon: 2}, {lat: 3, lon: 4}]}) { totalCount } }`,
+			`{ dealers(sortByDistance: {field: LOCATION, origin: {lat: 91, lon: 0}}) { totalCount } }`,
+			`{ dealers(sortByDistance: {field: LOCATION, origin: {lat: 1, lon: 2}, unit: "parsec"}) { totalCount } }`,
+		} {
+			if result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: query}); len(result.Errors) == 0 {
+				t.Errorf("Expected an error for %s", query)
+			}
+		}
+	})
+
+	t.Run("feature query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(response))
+		featureBased := *queryConfig
+		featureBased.EnableElasticQuerying = false
+		backend := &recordingBackend{hits: make([]map[string]any, 2)}
+		api := es.newAPI(t, backend, WithQuery("dealers", &featureBased))
+
+		data := runQuery(t, api, `{ dealers(
+			geoPolygon: {field: LOCATION, points: [{lat: 59, lon: 17}, {lat: 60, lon: 18}, {lat: 59, lon: 19}]},
+			sortByDistance: {field: LOCATION, origin: {lat: 59.33, lon: 18.06}, order: "desc"}
+		) { totalCount hits { id distance } } }`)
+
+		// The features' request is sent once, sorted by distance, instead of through the backend
+		if backend.search != nil {
+			t.Errorf("Expected no backend search, got %v", backend.search)
+		}
+		if len(es.requests()) != 1 {
+			t.Fatalf("Expected a single search, got %v", es.requests())
+		}
+		assertContains(t, es.requests()[0],
+			`"geo_shape":{"location":{"shape":{"coordinates":[[[17,59],[18,60],[19,59],[17,59]]],"type":"polygon"}}}`,
+			`"_geo_distance":{"location":[{"lat":59.33,"lon":18.06}],"order":"desc","unit":"km"}`,
+		)
+
+		hits := data["dealers"].(map[string]any)["hits"].([]any)
+		if len(hits) != 2 || hits[0].(map[string]any)["id"] != "d1" || hits[0].(map[string]any)["distance"] != 1.25 {
+			t.Errorf("Expected the sorted hits with distances, got %v", hits)
+		}
+
+		// Without an ES client the backend cannot sort by distance
+		api, err := New(backend, NewConfig(WithQuery("dealers", queryConfig)))
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+		result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ dealers(sortByDistance: {field: LOCATION, origin: {lat: 1, lon: 2}}) { totalCount } }`})
+		if len(result.Errors) == 0 {
+			t.Error("Expected an error without an ES client")
+		}
+	})
+}
+
+func TestGeoTypedAggregations(t *testing.T) {
+	sg := NewSchemaGenerator(NewConfig(), nil)
+	field := "location"
+	precision := 8
+	aggsType := sg.generateTypedAggregationsType("dealers", map[string]types.Aggregations{
+		"tiles": {
+			GeotileGrid: &types.GeoTileGridAggregation{Field: &field, Precision: &precision},
+			Aggregations: map[string]types.Aggregations{
+				"center": {GeoCentroid: &types.GeoCentroidAggregation{Field: &field}},
+			},
+		},
+	})
+
+	tiles := aggsType.Fields()["tiles"]
+	if tiles == nil || tiles.Type.Name() != "DealersTiles" {
+		t.Fatalf("Expected a tiles field of type DealersTiles, got %v", tiles)
+	}
+	bucketType := tiles.Type.(*graphql.Object).Fields()["buckets"].Type.(*graphql.NonNull).OfType.(*graphql.List).OfType.(*graphql.NonNull).OfType.(*graphql.Object)
+	if center := bucketType.Fields()["center"]; center == nil || center.Type.Name() != "GeoCentroid" {
+		t.Errorf("Expected a center field of type GeoCentroid, got %v", center)
+	}
+	if _, ok := sg.geoCentroidType().Fields()["location"]; !ok {
+		t.Error("Expected a location field on GeoCentroid")
+	}
+}
+
+func TestGeoAggregateResults(t *testing.T) {
+	aggs := map[string]types.Aggregate{
+		"cells":  &types.GeoHashGridAggregate{Buckets: []types.GeoHashGridBucket{{Key: "u6sc", DocCount: 2}}},
+		"tiles":  &types.GeoTileGridAggregate{Buckets: []types.GeoTileGridBucket{{Key: "8/141/75", DocCount: 3}}},
+		"center": &types.GeoCentroidAggregate{Count: 2, Location: &types.LatLonGeoLocation{Lat: 59.33, Lon: 18.06}},
+	}
+
+	buckets, err := parseAggregations(aggs)
+	if err != nil {
+		t.Fatalf("Failed to parse aggregations: %v", err)
+	}
+	for name, expected := range map[string]string{"cells": "u6sc 2", "tiles": "8/141/75 3", "center": "59.33,18.06 2"} {
+		if len(buckets[name]) != 1 || fmt.Sprintf("%v %d", buckets[name][0].Value, buckets[name][0].HitCount) != expected {
+			t.Errorf("Expected %s bucket %s, got %v", name, expected, buckets[name])
+		}
+	}
+
+	typed := (&ResolverBuilder{}).convertESAggregatesToObject(aggs)
+	if cells := fmt.Sprint(typed["cells"]); cells != "map[buckets:[map[doc_count:2 key:u6sc]]]" {
+		t.Errorf("Expected the geohash buckets, got %s", cells)
+	}
+	if center := fmt.Sprint(typed["center"]); center != "map[count:2 location:map[lat:59.33 lon:18.06]]" {
+		t.Errorf("Expected the centroid, got %s", center)
+	}
+}
@@ -0,0 +1,53 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+// generateSDL generates the SDL of a config, failing the test if the config is invalid
+func generateSDL(t *testing.T, config *Config) string {
+	t.Helper()
+
+	sdl, err := GenerateSchemaSDL(config)
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+	return sdl
+}
+
+// assertContains checks that text contains every expected string
+func assertContains(t *testing.T, text string, expected ...string) {
+	t.Helper()
+
+	for _, e := range expected {
+		if !strings.Contains(text, e) {
+			t.Errorf("Expected %q in:\n%s", e, text)
+		}
+	}
+}
+
+// assertNotContains checks that text contains none of the unexpected strings
+func assertNotContains(t *testing.T, text string, unexpected ...string) {
+	t.Helper()
+
+	for _, u := range unexpected {
+		if strings.Contains(text, u) {
+			t.Errorf("Unexpected %q in:\n%s", u, text)
+		}
+	}
+}
+
+// runQuery executes a query in a request context, failing the test on errors, and returns its data
+func runQuery(t *testing.T, api *GraphQLAPI, query string) map[string]any {
+	t.Helper()
+
+	result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: query, Context: NewRequestContext(context.Background())})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+	return result.Data.(map[string]any)
+}
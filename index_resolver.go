@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/reveald/reveald/v2"
+)
+
+// validIndexPart matches values that are safe to embed in an index name
+// (no wildcards, commas or other characters that would widen the search)
+var validIndexPart = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// resolve returns the indices to search for a request, falling back to defaults
+// when no IndexResolver is configured
+func (ir IndexResolver) resolve(httpReq *http.Request, args map[string]any, defaults []string) ([]string, error) {
+	if ir == nil {
+		return defaults, nil
+	}
+
+	if httpReq == nil {
+		return nil, fmt.Errorf("HTTP request not available in context")
+	}
+
+	indices, err := ir(httpReq, args)
+	if err != nil {
+		return nil, fmt.Errorf("index resolver failed: %w", err)
+	}
+	if len(indices) == 0 {
+		return nil, fmt.Errorf("index resolver returned no indices")
+	}
+
+	return indices, nil
+}
+
+// queryEndpoints holds the endpoint of a query's configured indices, shared by its requests
+// Other index sets picked by an IndexResolver get an endpoint per request, so client-chosen
+// indices (e.g., from a header) are never kept around
+type queryEndpoints struct {
+	backend    reveald.Backend
+	features   []reveald.Feature
+	configured []string
+	endpoint   *reveald.Endpoint
+}
+
+// newQueryEndpoints creates the endpoint of the configured indices with the given features
+func newQueryEndpoints(backend reveald.Backend, features []reveald.Feature, configured []string) (*queryEndpoints, error) {
+	qe := &queryEndpoints{backend: backend, features: features, configured: configured}
+	endpoint, err := qe.create(configured)
+	if err != nil {
+		return nil, err
+	}
+	qe.endpoint = endpoint
+	return qe, nil
+}
+
+// get returns the shared endpoint for the configured indices and a new endpoint for any other set
+func (qe *queryEndpoints) get(indices []string, requestFeatures ...reveald.Feature) (*reveald.Endpoint, error) {
+	if len(requestFeatures) == 0 && slices.Equal(indices, qe.configured) {
+		return qe.endpoint, nil
+	}
+	return qe.create(indices, requestFeatures...)
+}
+
+// create builds an endpoint for the indices with the query's features and any request features
+func (qe *queryEndpoints) create(indices []string, requestFeatures ...reveald.Feature) (*reveald.Endpoint, error) {
+	endpoint := reveald.NewEndpoint(qe.backend, reveald.WithIndices(indices...))
+	if err := endpoint.Register(append(append([]reveald.Feature{}, qe.features...), requestFeatures...)...); err != nil {
+		return nil, fmt.Errorf("failed to register features: %w", err)
+	}
+	return endpoint, nil
+}
+
+// HeaderIndexResolver returns an IndexResolver that builds the index name from a request header
+// The pattern must contain a single %s which is replaced by the lowercased header value
+// Example: HeaderIndexResolver("X-Tenant-ID", "leads-%s") routes tenant "acme" to "leads-acme"
+// Header values containing anything but letters, digits, '_', '-' and '.' are rejected
+func HeaderIndexResolver(header, pattern string) IndexResolver {
+	return func(r *http.Request, args map[string]any) ([]string, error) {
+		value := strings.ToLower(strings.TrimSpace(r.Header.Get(header)))
+		if value == "" {
+			return nil, fmt.Errorf("missing %s header", header)
+		}
+		if !validIndexPart.MatchString(value) {
+			return nil, fmt.Errorf("invalid %s header value: %q", header, value)
+		}
+		return []string{fmt.Sprintf(pattern, value)}, nil
+	}
+}
+
+// MonthlyIndices returns one index name per month between from and to (inclusive)
+// The layout is a Go time layout appended to the prefix
+// Example: MonthlyIndices("events-", "2006.01", jan15, mar2) → ["events-2024.01", "events-2024.02", "events-2024.03"]
+func MonthlyIndices(prefix, layout string, from, to time.Time) []string {
+	if to.Before(from) {
+		from, to = to, from
+	}
+
+	to = to.In(from.Location())
+	current := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	last := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, from.Location())
+
+	var indices []string
+	for !current.After(last) {
+		indices = append(indices, prefix+current.Format(layout))
+		current = current.AddDate(0, 1, 0)
+	}
+
+	return indices
+}
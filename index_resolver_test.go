@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIndexResolverDefaults(t *testing.T) {
+	var resolver IndexResolver
+
+	indices, err := resolver.resolve(nil, nil, []string{"leads"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(indices, []string{"leads"}) {
+		t.Errorf("Expected default indices, got %v", indices)
+	}
+
+	// A configured resolver must not silently fall back when the request is missing
+	resolver = HeaderIndexResolver("X-Tenant-ID", "leads-%s")
+	if _, err := resolver.resolve(nil, nil, []string{"leads"}); err == nil {
+		t.Error("Expected error when HTTP request is not available")
+	}
+}
+
+func TestHeaderIndexResolver(t *testing.T) {
+	resolver := HeaderIndexResolver("X-Tenant-ID", "leads-%s")
+
+	tests := []struct {
+		name     string
+		tenant   string
+		expected []string
+		wantErr  bool
+	}{
+		{"tenant", "acme", []string{"leads-acme"}, false},
+		{"lowercased", "Acme", []string{"leads-acme"}, false},
+		{"missing", "", nil, true},
+		{"wildcard", "*", nil, true},
+		{"multiple indices", "acme,other", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/graphql", nil)
+			if tt.tenant != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenant)
+			}
+
+			indices, err := resolver.resolve(req, nil, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", indices)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(indices, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, indices)
+			}
+		})
+	}
+}
+
+func TestMonthlyIndices(t *testing.T) {
+	from := time.Date(2024, 11, 15, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	expected := []string{"events-2024.11", "events-2024.12", "events-2025.01", "events-2025.02"}
+	if got := MonthlyIndices("events-", "2006.01", from, to); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	// Reversed range gives the same result
+	if got := MonthlyIndices("events-", "2006.01", to, from); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v for reversed range, got %v", expected, got)
+	}
+}
+
+func TestQueryEndpoints(t *testing.T) {
+	endpoints, err := newQueryEndpoints(&recordingBackend{}, nil, []string{"leads"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	configured, _ := endpoints.get([]string{"leads"})
+	again, _ := endpoints.get([]string{"leads"})
+	if configured != again {
+		t.Error("Expected the endpoint of the configured indices to be reused")
+	}
+
+	// Resolver-picked index sets get a new endpoint per request and are not kept
+	acme, _ := endpoints.get([]string{"leads-acme"})
+	acmeAgain, _ := endpoints.get([]string{"leads-acme"})
+	if acme == acmeAgain || acme == configured {
+		t.Error("Expected a new endpoint per request for other index sets")
+	}
+
+	// Request features always get an endpoint of their own
+	if withFeatures, _ := endpoints.get([]string{"leads"}, &mockWrapperFeature{}); withFeatures == configured {
+		t.Error("Expected a new endpoint for request features")
+	}
+}
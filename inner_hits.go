@@ -0,0 +1,289 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/reveald/reveald/v2"
+)
+
+const (
+	// innerHitsKey is the hit key holding the matched nested objects by path
+	innerHitsKey = "_innerHits"
+
+	// defaultInnerHitsSize is the number of matched nested objects returned by default (as in ES)
+	defaultInnerHitsSize = 3
+)
+
+// innerHitsScope returns the scope registering the matched fields of a document type
+func innerHitsScope(typeName string) string {
+	return typeName + ".innerHits"
+}
+
+// innerHitsFieldName returns the name of the matched field of a nested path
+// (e.g., "processes.tasks" → "matchedProcessesTasks")
+func innerHitsFieldName(path string) string {
+	name := "matched"
+	for _, part := range splitPath(path) {
+		name += capitalize(sanitizeFieldName(part))
+	}
+	return name
+}
+
+// validateInnerHits checks that the query's inner hits paths are nested fields
+func validateInnerHits(paths []string, mapping *IndexMapping) error {
+	for _, path := range paths {
+		field := mapping.GetField(path)
+		if field == nil {
+			return fmt.Errorf("inner hits field %s not found", path)
+		}
+		if field.Type != FieldTypeNested {
+			return fmt.Errorf("inner hits field %s must be a nested field, got %s", path, field.Type)
+		}
+	}
+	return nil
+}
+
+// addInnerHitsFields adds the matched field of each inner hits path to a document type
+// Paths missing from the mapping (e.g., in one index of a multi-index query) are skipped
+func (sg *SchemaGenerator) addInnerHitsFields(fields graphql.Fields, typeName string, paths []string, mapping *IndexMapping) error {
+	for _, path := range paths {
+		field := mapping.GetField(path)
+		if field == nil || field.Type != FieldTypeNested {
+			continue
+		}
+
+		parentPath := ""
+		if i := strings.LastIndex(path, "."); i >= 0 {
+			parentPath = objectTypePath(mapping, path[:i])
+		}
+		objType, err := sg.esTypeToGraphQLType(field, parentPath)
+		if err != nil {
+			return fmt.Errorf("failed to convert inner hits field %s: %w", path, err)
+		}
+
+		gqlName := sg.names.register(innerHitsScope(typeName), path, innerHitsFieldName(path))
+		sg.names.reserve(typeName, gqlName)
+		fields[gqlName] = &graphql.Field{
+			Type:        objType,
+			Description: fmt.Sprintf("The %s entries matching the nested filters of the query", path),
+			Args: graphql.FieldConfigArgument{
+				"size": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: defaultInnerHitsSize,
+					Description:  "Maximum number of matched entries",
+				},
+				"sort": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.String),
+					Description: "Sort of the matched entries, as \"<field>-asc\" or \"<field>-desc\" (e.g., \"startedAt-desc\")",
+				},
+			},
+			Resolve: innerHitsResolver(path, field),
+		}
+	}
+	return nil
+}
+
+// innerHitsResolver returns the matched nested objects of a path
+// Hits without inner hits for the path (the query has no nested filter on it) resolve to null
+func innerHitsResolver(path string, field *Field) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		innerHits, ok := source[innerHitsKey].(map[string][]map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		matched, ok := innerHits[path]
+		if !ok {
+			return nil, nil
+		}
+
+		if size, ok := p.Args["size"].(int); ok && size >= 0 && len(matched) > size {
+			matched = matched[:size]
+		}
+		for _, obj := range matched {
+			normalizeNestedObject(obj, field)
+		}
+		return matched, nil
+	}
+}
+
+// innerHitsOptions are the arguments of a selected matched field
+type innerHitsOptions struct {
+	size int
+	sort []string
+}
+
+// selectedInnerHits returns the options of the matched fields selected on the hits, by nested path
+func (rb *ResolverBuilder) selectedInnerHits(info graphql.ResolveInfo, mapping *IndexMapping) (map[string]innerHitsOptions, error) {
+	selected := make(map[string]innerHitsOptions)
+	var err error
+	forSelectedFields(info, "hits", func(scopes []string, selection *ast.Field) {
+		for _, scope := range scopes {
+			path, ok := rb.names.esName(innerHitsScope(scope), selection.Name.Value)
+			if !ok {
+				continue
+			}
+
+			options, optionsErr := readInnerHitsOptions(selection, info.VariableValues, path, mapping)
+			if optionsErr != nil && err == nil {
+				err = optionsErr
+			}
+
+			// The largest size of a path selected several times (e.g., in fragments)
+			if existing, ok := selected[path]; !ok || existing.size < options.size {
+				selected[path] = options
+			}
+			return
+		}
+	})
+	return selected, err
+}
+
+// readInnerHitsOptions reads the size and sort arguments of a selected matched field
+// Sorts must be on sortable fields of the nested path (path is "" for top-level fields) and
+// text fields are routed to their keyword multi-field
+func readInnerHitsOptions(selection *ast.Field, variables map[string]any, path string, mapping *IndexMapping) (innerHitsOptions, error) {
+	options := innerHitsOptions{size: defaultInnerHitsSize}
+	if size, ok := argumentValue(selection, "size", variables).(int); ok {
+		options.size = size
+	}
+	switch sort := argumentValue(selection, "sort", variables).(type) {
+	case []any:
+		for _, option := range sort {
+			if s, ok := option.(string); ok {
+				options.sort = append(options.sort, s)
+			}
+		}
+	case string:
+		options.sort = []string{sort}
+	}
+	prefix := ""
+	if path != "" {
+		prefix = path + "."
+	}
+	for i, option := range options.sort {
+		name, order, ok := parseSortValue(option)
+		if !ok {
+			return options, fmt.Errorf("invalid sort %q for %s, expected \"<field>-asc\" or \"<field>-desc\"", option, selection.Name.Value)
+		}
+		exact := exactFieldPath(prefix+name, mapping)
+		field := mapping.GetField(exact)
+		if field == nil || field.hasProperties() || !isSortableMultiField(field) {
+			return options, fmt.Errorf("invalid sort %q for %s, %s is not a sortable field", option, selection.Name.Value, prefix+name)
+		}
+		options.sort[i] = strings.TrimPrefix(exact, prefix) + "-" + order.String()
+	}
+	return options, nil
+}
+
+// applyInnerHits requests inner hits from the nested queries of a query on the selected paths,
+// including nested queries inside other nested queries (e.g., on processes.tasks inside processes).
+// A path filtered by several nested queries gets one inner hits section per query ("processes",
+// "processes#2", ...), merged when the hits are read.
+func applyInnerHits(query *types.Query, selected map[string]innerHitsOptions) {
+	counts := make(map[string]int)
+
+	var walk func(query *types.Query)
+	walk = func(query *types.Query) {
+		if query == nil {
+			return
+		}
+		if query.Bool != nil {
+			for _, clauses := range [][]types.Query{query.Bool.Must, query.Bool.Filter, query.Bool.Should} {
+				for i := range clauses {
+					walk(&clauses[i])
+				}
+			}
+		}
+
+		nested := query.Nested
+		if nested == nil {
+			return
+		}
+		walk(&nested.Query)
+		options, ok := selected[nested.Path]
+		if !ok || nested.InnerHits != nil {
+			return
+		}
+
+		counts[nested.Path]++
+		name := nested.Path
+		if counts[nested.Path] > 1 {
+			name = fmt.Sprintf("%s#%d", nested.Path, counts[nested.Path])
+		}
+		size := options.size
+		innerHits := &types.InnerHits{Name: &name, Size: &size}
+		for _, option := range options.sort {
+			field, order, _ := parseSortValue(option)
+			innerHits.Sort = append(innerHits.Sort, types.SortOptions{
+				SortOptions: map[string]types.FieldSort{nested.Path + "." + field: {Order: &order}},
+			})
+		}
+		nested.InnerHits = innerHits
+	}
+	walk(query)
+}
+
+// innerHitValues converts the inner hits of a hit to the matched nested objects by path,
+// merging the sections of a path without duplicates
+// Inner hits of nested queries inside nested queries with inner hits are returned inside the outer
+// inner hits, they are collected under their own path
+func innerHitValues(innerHits map[string]types.InnerHitsResult) map[string][]map[string]any {
+	values := make(map[string][]map[string]any)
+	collectInnerHitValues(innerHits, values, make(map[string]map[string]bool))
+	return values
+}
+
+// collectInnerHitValues adds the matched nested objects of inner hits to values, skipping the
+// nested objects already seen (by their nested identity, which includes the outer offsets)
+func collectInnerHitValues(innerHits map[string]types.InnerHitsResult, values map[string][]map[string]any, seen map[string]map[string]bool) {
+	for _, name := range sortedKeys(innerHits) {
+		path, _, _ := strings.Cut(name, "#")
+		if seen[path] == nil {
+			seen[path] = make(map[string]bool)
+			values[path] = []map[string]any{}
+		}
+
+		for _, hit := range innerHits[name].Hits.Hits {
+			if len(hit.InnerHits) > 0 {
+				collectInnerHitValues(hit.InnerHits, values, seen)
+			}
+			if hit.Nested_ != nil {
+				identity, _ := json.Marshal(hit.Nested_)
+				if seen[path][string(identity)] {
+					continue
+				}
+				seen[path][string(identity)] = true
+			}
+
+			var obj map[string]any
+			if err := decodeSource(hit.Source_, &obj); err != nil {
+				continue
+			}
+			if _, ok := obj["id"]; !ok && hit.Nested_ == nil && hit.Id_ != nil {
+				obj["id"] = *hit.Id_
+			}
+			values[path] = append(values[path], obj)
+		}
+	}
+}
+
+// innerHitsFeature requests inner hits for the nested filters added by the query's features
+// It must run after the features adding nested queries, so it is registered last
+type innerHitsFeature struct {
+	selected map[string]innerHitsOptions
+}
+
+// Process implements reveald.Feature
+func (ihf *innerHitsFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	applyInnerHits(builder.RawQuery(), ihf.selected)
+	return next(builder)
+}
@@ -0,0 +1,185 @@
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+// nestedStatusFeature filters on processes.status with a nested query, like the nested filter features
+type nestedStatusFeature struct{}
+
+func (f *nestedStatusFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	for _, status := range []string{"done", "started"} {
+		builder.With(types.Query{Nested: &types.NestedQuery{
+			Path:  "processes",
+			Query: types.Query{Term: map[string]types.TermQuery{"processes.status": {Value: status}}},
+		}})
+	}
+	return next(builder)
+}
+
+func TestInnerHits(t *testing.T) {
+	mapping := IndexMapping{
+		IndexName: "vehicles",
+		Properties: map[string]*Field{
+			"regNo": {Name: "regNo", Type: FieldTypeKeyword},
+			"processes": {Name: "processes", Type: FieldTypeNested, Properties: map[string]*Field{
+				"name":      {Name: "name", Type: FieldTypeKeyword},
+				"status":    {Name: "status", Type: FieldTypeKeyword},
+				"startedAt": {Name: "startedAt", Type: FieldTypeDate},
+				"tasks": {Name: "tasks", Type: FieldTypeNested, Properties: map[string]*Field{
+					"process": {Name: "process", Type: FieldTypeKeyword},
+				}},
+			}},
+		},
+	}
+	queryConfig := &QueryConfig{
+		Mapping:               mapping,
+		Features:              []reveald.Feature{&nestedStatusFeature{}},
+		InnerHits:             []string{"processes", "processes.tasks"},
+		EnableElasticQuerying: true,
+	}
+
+	backend := &recordingBackend{}
+	api, err := New(backend, NewConfig(WithQuery("vehicles", queryConfig)))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("vehicles", queryConfig)))
+		assertContains(t, sdl, "matchedProcesses(", "): [ProcessesObject]", "matchedProcessesTasks(", "): [ProcessesTasksObject]", "size: Int", "sort: [String]")
+
+		notNested := *queryConfig
+		notNested.InnerHits = []string{"regNo"}
+		if _, err := GenerateSchemaSDL(NewConfig(WithQuery("vehicles", &notNested))); err == nil {
+			t.Error("Expected an error for a field that is not nested")
+		}
+	})
+
+	t.Run("requested", func(t *testing.T) {
+		runQuery(t, api, `{ vehicles { hits { regNo matchedProcesses(size: 2, sort: ["startedAt-desc"]) { name } } } }`)
+		assertContains(t, backend.searchBody(t),
+			`"inner_hits":{"name":"processes","size":2,"sort":[{"processes.startedAt":{"order":"desc"}}]}`,
+			`"inner_hits":{"name":"processes#2","size":2`,
+		)
+
+		// Inner hits are only requested when selected
+		runQuery(t, api, `{ vehicles { hits { regNo } } }`)
+		assertNotContains(t, backend.searchBody(t), "inner_hits")
+
+		result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ vehicles { hits { matchedProcesses(sort: ["startedAt"]) { name } } } }`})
+		if len(result.Errors) == 0 {
+			t.Error("Expected an error for an invalid sort")
+		}
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		for _, sort := range []string{"missing-desc", "tasks-asc"} {
+			result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ vehicles { hits { matchedProcesses(sort: ["` + sort + `"]) { name } } } }`})
+			if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, "not a sortable field") {
+				t.Errorf("Expected an error for sort %s, got %v", sort, result.Errors)
+			}
+		}
+	})
+
+	t.Run("typed query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [{
+			"_index": "vehicles", "_id": "v1",
+			"_source": {"regNo": "ABC123", "processes": [{"name": "Repair", "status": "done"}, {"name": "Wash", "status": "open"}]},
+			"inner_hits": {"processes": {"hits": {"hits": [
+				{"_index": "vehicles", "_nested": {"field": "processes", "offset": 0}, "_source": {"name": "Repair", "status": "done"}}
+			]}}}
+		}]}`))
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("vehicles", queryConfig))
+
+		data := runQuery(t, api, `{ vehicles(query: {nested: {path: "processes", query: {term: {field: "processes.status", value: "done"}}}}) {
+			hits { regNo matchedProcesses { name } }
+		} }`)
+
+		assertContains(t, es.requests()[0], `"inner_hits":{"name":"processes","size":3}`)
+		hit := data["vehicles"].(map[string]any)["hits"].([]any)[0].(map[string]any)
+		matched := hit["matchedProcesses"].([]any)
+		if len(matched) != 1 || matched[0].(map[string]any)["name"] != "Repair" {
+			t.Errorf("Expected the matched process only, got %v", matched)
+		}
+	})
+
+	t.Run("nested path", func(t *testing.T) {
+		// Inner hits of processes.tasks are returned inside the inner hits of processes
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [{
+			"_index": "vehicles", "_id": "v1",
+			"_source": {"regNo": "ABC123"},
+			"inner_hits": {"processes": {"hits": {"hits": [
+				{"_index": "vehicles", "_nested": {"field": "processes", "offset": 0}, "_source": {"name": "Repair"},
+					"inner_hits": {"processes.tasks": {"hits": {"hits": [
+						{"_index": "vehicles", "_nested": {"field": "processes", "offset": 0, "_nested": {"field": "tasks", "offset": 1}}, "_source": {"process": "paint"}}
+					]}}}},
+				{"_index": "vehicles", "_nested": {"field": "processes", "offset": 1}, "_source": {"name": "Wash"},
+					"inner_hits": {"processes.tasks": {"hits": {"hits": [
+						{"_index": "vehicles", "_nested": {"field": "processes", "offset": 1, "_nested": {"field": "tasks", "offset": 1}}, "_source": {"process": "rinse"}}
+					]}}}}
+			]}}}
+		}]}`))
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("vehicles", queryConfig))
+
+		data := runQuery(t, api, `{ vehicles(query: {nested: {path: "processes", query: {nested: {path: "processes.tasks", query: {term: {field: "processes.tasks.process", value: "paint"}}}}}}) {
+			hits { matchedProcesses { name } matchedProcessesTasks(sort: ["process-asc"]) { process } }
+		} }`)
+
+		assertContains(t, es.requests()[0],
+			`"inner_hits":{"name":"processes","size":3}`,
+			`"inner_hits":{"name":"processes.tasks","size":3,"sort":[{"processes.tasks.process":{"order":"asc"}}]}`,
+		)
+
+		// Tasks with the same offset in different processes are different matches
+		hit := data["vehicles"].(map[string]any)["hits"].([]any)[0].(map[string]any)
+		if tasks := hit["matchedProcessesTasks"].([]any); len(tasks) != 2 || tasks[1].(map[string]any)["process"] != "rinse" {
+			t.Errorf("Expected the matched tasks of both processes, got %v", tasks)
+		}
+		if processes := hit["matchedProcesses"].([]any); len(processes) != 2 {
+			t.Errorf("Expected the matched processes, got %v", processes)
+		}
+	})
+
+	t.Run("shared document type", func(t *testing.T) {
+		other := *queryConfig
+		other.InnerHits = []string{"processes"}
+
+		_, err := GenerateSchemaSDL(NewConfig(WithQuery("vehicles", queryConfig), WithQuery("vehicleProcesses", &other)))
+		if err == nil || !strings.Contains(err.Error(), "InnerHits") {
+			t.Errorf("Expected an error about the shared document type, got %v", err)
+		}
+	})
+}
+
+func TestInnerHitValues(t *testing.T) {
+	var innerHits map[string]types.InnerHitsResult
+	err := json.Unmarshal([]byte(`{
+		"processes": {"hits": {"hits": [
+			{"_index": "vehicles", "_nested": {"field": "processes", "offset": 2}, "_source": {"name": "Wash"}},
+			{"_index": "vehicles", "_nested": {"field": "processes", "offset": 0}, "_source": {"name": "Repair"}}
+		]}},
+		"processes#2": {"hits": {"hits": [
+			{"_index": "vehicles", "_nested": {"field": "processes", "offset": 0}, "_source": {"name": "Repair"}},
+			{"_index": "vehicles", "_nested": {"field": "processes", "offset": 1}, "_source": {"name": "Paint"}}
+		]}}
+	}`), &innerHits)
+	if err != nil {
+		t.Fatalf("Failed to parse inner hits: %v", err)
+	}
+
+	values := innerHitValues(innerHits)["processes"]
+	var names []string
+	for _, value := range values {
+		names = append(names, value["name"].(string))
+	}
+	if strings.Join(names, ",") != "Wash,Repair,Paint" {
+		t.Errorf("Expected merged matches without duplicates, got %v", names)
+	}
+}
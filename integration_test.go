@@ -193,7 +193,7 @@ func TestIntegration_MixedQueryTypes(t *testing.T) {
 	}
 
 	data1 := result1.Data.(map[string]any)["featureSearch"].(map[string]any)
-	totalCount1 := int(data1["totalCount"].(int))
+	totalCount1 := int(data1["totalCount"].(int64))
 
 	// Test 2: Typed ES query with same filter
 	query2 := `query { flexibleSearch(query: { term: { field: "brand.keyword", value: "TechBrand" } }) { hits { id name active } totalCount } }`
@@ -204,7 +204,7 @@ func TestIntegration_MixedQueryTypes(t *testing.T) {
 	}
 
 	data2 := result2.Data.(map[string]any)["flexibleSearch"].(map[string]any)
-	totalCount2 := int(data2["totalCount"].(int))
+	totalCount2 := int(data2["totalCount"].(int64))
 
 	// Both should return same count (active TechBrand products)
 	if totalCount1 != totalCount2 {
@@ -275,7 +275,7 @@ func TestIntegration_TypedQueryWithAggregations(t *testing.T) {
 	data := result.Data.(map[string]any)["search"].(map[string]any)
 
 	// Verify query executed successfully
-	if data["totalCount"].(int) == 0 {
+	if data["totalCount"].(int64) == 0 {
 		t.Error("expected some results for active products")
 	}
 }
@@ -523,7 +523,7 @@ func TestIntegration_RootQueryEnforcement(t *testing.T) {
 	}
 
 	// Should get 2 active TechBrand products (not 3 which would include inactive)
-	if data["totalCount"].(int) != 2 {
+	if data["totalCount"].(int64) != 2 {
 		t.Errorf("expected 2 active TechBrand products, got %v", data["totalCount"])
 	}
 }
@@ -608,7 +608,7 @@ func TestIntegration_ComplexNestedQuery(t *testing.T) {
 	}
 
 	// Should get TechBrand electronics under $100 (Wireless Mouse at $29.99)
-	if data["totalCount"].(int) == 0 {
+	if data["totalCount"].(int64) == 0 {
 		t.Error("expected at least one result matching complex criteria")
 	}
 }
@@ -0,0 +1,509 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+// JoinField is the output type for ES join fields
+// Parent documents store only their relation name, which is returned without a parent
+var JoinField = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "JoinField",
+	Description: "The relation of a document in a parent/child join",
+	Fields: graphql.Fields{
+		"name":   &graphql.Field{Type: graphql.String, Description: "Relation name (e.g., \"message\")"},
+		"parent": &graphql.Field{Type: graphql.String, Description: "ID of the parent document"},
+	},
+})
+
+// parseJoinRelations parses the relations of a join field, where a parent has one child
+// name or a list of them
+func parseJoinRelations(raw map[string]any) map[string][]string {
+	relations := make(map[string][]string, len(raw))
+	for parent, children := range raw {
+		switch c := children.(type) {
+		case string:
+			relations[parent] = []string{c}
+		case []any:
+			for _, child := range c {
+				if name, ok := child.(string); ok {
+					relations[parent] = append(relations[parent], name)
+				}
+			}
+		}
+	}
+	return relations
+}
+
+// joinRelationsJSON converts join relations to their ES mapping representation
+func joinRelationsJSON(relations map[string][]string) map[string]any {
+	result := make(map[string]any, len(relations))
+	for parent, children := range relations {
+		if len(children) == 1 {
+			result[parent] = children[0]
+		} else {
+			result[parent] = children
+		}
+	}
+	return result
+}
+
+// joinFieldResolver normalises join values, which are a relation name for parents and
+// an object with the name and parent ID for children
+func joinFieldResolver(field *Field) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		name, parent := joinValue(source[field.Name])
+		if name == "" {
+			return nil, nil
+		}
+
+		value := map[string]any{"name": name}
+		if parent != "" {
+			value["parent"] = parent
+		}
+		return value, nil
+	}
+}
+
+// joinValue returns the relation name and parent ID of a join field value
+func joinValue(value any) (string, string) {
+	switch v := value.(type) {
+	case string:
+		return v, ""
+	case map[string]any:
+		name, _ := v["name"].(string)
+		var parent string
+		if v["parent"] != nil {
+			parent = fmt.Sprint(v["parent"])
+		}
+		return name, parent
+	}
+	return "", ""
+}
+
+// joinRelations describes the join field of an index
+// ES allows one join field per index, at the top level of the mapping
+type joinRelations struct {
+	field    string              // Name of the join field
+	children map[string][]string // Child relation names by parent relation name
+	parents  map[string]string   // Parent relation name by child relation name
+}
+
+// mappingJoin returns the join field of a mapping, nil when it has none
+func mappingJoin(mapping *IndexMapping) *joinRelations {
+	for _, name := range sortedKeys(mapping.Properties) {
+		field := mapping.Properties[name]
+		if field.Type != FieldTypeJoin || len(field.Relations) == 0 {
+			continue
+		}
+
+		join := &joinRelations{field: name, children: field.Relations, parents: make(map[string]string)}
+		for parent, children := range field.Relations {
+			for _, child := range children {
+				join.parents[child] = parent
+			}
+		}
+		return join
+	}
+	return nil
+}
+
+// childNames returns the names of all child relations
+func (j *joinRelations) childNames() []string {
+	return sortedKeys(j.parents)
+}
+
+// parentNames returns the names of all parent relations
+func (j *joinRelations) parentNames() []string {
+	return sortedKeys(j.children)
+}
+
+// joinFilter is a hasChild or hasParent argument of a query
+type joinFilter struct {
+	argName   string // GraphQL argument name (e.g., "hasChild")
+	relation  string // Relation the documents must have a child or parent of
+	child     bool   // has_child when true, has_parent otherwise
+	inputType string // Name of the filter input type (e.g., "MessageFilterInput")
+}
+
+// joinFilters returns the join filter arguments of a join
+// A join with a single child (or parent) relation gets a hasChild (hasParent) argument, with
+// several relations the argument name includes the relation (e.g., hasChildMessage)
+func (j *joinRelations) joinFilters() []joinFilter {
+	var filters []joinFilter
+	add := func(prefix string, relations []string, child bool) {
+		for _, relation := range relations {
+			argName := prefix
+			if len(relations) > 1 {
+				argName += capitalize(sanitizeFieldName(relation))
+			}
+			filters = append(filters, joinFilter{
+				argName:   argName,
+				relation:  relation,
+				child:     child,
+				inputType: filterInputTypeName(relation),
+			})
+		}
+	}
+	add("hasChild", j.childNames(), true)
+	add("hasParent", j.parentNames(), false)
+	return filters
+}
+
+// filterInputTypeName returns the name of the filter input type of a relation
+func filterInputTypeName(relation string) string {
+	return capitalize(sanitizeFieldName(relation)) + "FilterInput"
+}
+
+// filterInputType creates (or returns the cached) input type with the filterable fields of a mapping
+// Input field names are registered in the type's scope so filters can be mapped back to ES fields
+func (sg *SchemaGenerator) filterInputType(typeName string, queryConfig *QueryConfig, mapping *IndexMapping) *graphql.InputObject {
+	if cached, ok := sg.inputCache[typeName]; ok {
+		return cached
+	}
+
+	fields := graphql.InputObjectConfigFieldMap{}
+	for _, fieldName := range sortedKeys(mapping.Properties) {
+		if !sg.shouldIncludeField(fieldName, queryConfig.FieldFilter) {
+			continue
+		}
+		field := mapping.Properties[fieldName]
+		target := resolveAliasField(field, mapping)
+		if !sg.isFilterableField(target) {
+			continue
+		}
+		gqlFieldName := sg.names.register(typeName, fieldName, sg.graphQLName(fieldName, field))
+		fields[gqlFieldName] = &graphql.InputObjectFieldConfig{
+			Type:        sg.getFilterArgumentType(target),
+			Description: describeArgument(target),
+		}
+	}
+
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   typeName,
+		Fields: fields,
+	})
+	sg.inputCache[typeName] = inputType
+	return inputType
+}
+
+// addJoinArguments adds the hasChild and hasParent arguments of a query searching an index with a join field
+func (sg *SchemaGenerator) addJoinArguments(args graphql.FieldConfigArgument, scope string, queryConfig *QueryConfig, mapping *IndexMapping) {
+	join := mappingJoin(mapping)
+	if join == nil {
+		return
+	}
+
+	for _, filter := range join.joinFilters() {
+		sg.names.reserve(scope, filter.argName)
+		description := fmt.Sprintf("Only documents with a %s child matching the filter", filter.relation)
+		if !filter.child {
+			description = fmt.Sprintf("Only documents with a %s parent matching the filter", filter.relation)
+		}
+		args[filter.argName] = &graphql.ArgumentConfig{
+			Type:        sg.filterInputType(filter.inputType, queryConfig, mapping),
+			Description: description,
+		}
+	}
+}
+
+// joinQuery converts the hasChild and hasParent arguments of a query to has_child and has_parent queries
+// Returns nil when none of them is set
+func (rb *ResolverBuilder) joinQuery(args map[string]any, mapping *IndexMapping) *types.Query {
+	join := mappingJoin(mapping)
+	if join == nil {
+		return nil
+	}
+
+	var queries []*types.Query
+	for _, filter := range join.joinFilters() {
+		input, ok := args[filter.argName].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		inner := rb.filterInputQuery(filter.inputType, input, mapping)
+		if filter.child {
+			queries = append(queries, &types.Query{HasChild: &types.HasChildQuery{Type: filter.relation, Query: *inner}})
+		} else {
+			queries = append(queries, &types.Query{HasParent: &types.HasParentQuery{ParentType: filter.relation, Query: *inner}})
+		}
+	}
+	return mergeQueries(queries...)
+}
+
+// filterInputQuery converts the value of a filter input type to a query
+// Lists match any of their values, other values match exactly; an empty filter matches all documents
+func (rb *ResolverBuilder) filterInputQuery(typeName string, input map[string]any, mapping *IndexMapping) *types.Query {
+	var queries []*types.Query
+	for _, gqlName := range sortedKeys(input) {
+		value := input[gqlName]
+		if value == nil {
+			continue
+		}
+		esName, ok := rb.names.esName(typeName, gqlName)
+		if !ok {
+			esName = gqlName
+		}
+		path := exactFieldPath(esName, mapping)
+
+		if list, ok := value.([]any); ok {
+			values := make([]types.FieldValue, len(list))
+			for i, v := range list {
+				values[i] = v
+			}
+			queries = append(queries, &types.Query{
+				Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{path: values}},
+			})
+			continue
+		}
+		queries = append(queries, &types.Query{
+			Term: map[string]types.TermQuery{path: {Value: value}},
+		})
+	}
+
+	if query := mergeQueries(queries...); query != nil {
+		return query
+	}
+	return &types.Query{MatchAll: &types.MatchAllQuery{}}
+}
+
+// filterQueryFeature adds the queries of a request's filter arguments (join and geo filters)
+type filterQueryFeature struct {
+	query *types.Query
+}
+
+// Process implements reveald.Feature
+func (fqf *filterQueryFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	builder.With(*fqf.query)
+	return next(builder)
+}
+
+// addJoinFields adds the children and parent fields to the document types of indices with a join field
+// Fields are added once every document type exists, since they return the type they are added to
+func (sg *SchemaGenerator) addJoinFields() error {
+	added := make(map[string]bool)
+	for _, queryName := range sortedKeys(sg.config.Queries) {
+		queryConfig := sg.config.Queries[queryName]
+		if queryConfig.HitsType != nil || queryConfig.isMultiIndex() {
+			continue
+		}
+
+		mapping := queryConfig.GetMapping()
+		join := mappingJoin(&mapping)
+		if join == nil {
+			continue
+		}
+		docType, ok := sg.typeCache[queryDocumentTypeName(queryConfig, &mapping)]
+		if !ok || added[docType.Name()] {
+			continue
+		}
+		added[docType.Name()] = true
+
+		relationEnum, err := sg.enumType(&Field{
+			EnumTypeName: docType.Name() + "ChildRelation",
+			EnumValues:   join.childNames(),
+		})
+		if err != nil {
+			return fmt.Errorf("query %s: %w", queryName, err)
+		}
+
+		sg.names.reserve(docType.Name(), "children")
+		docType.AddFieldConfig("children", &graphql.Field{
+			Type:        graphql.NewList(docType),
+			Description: "Child documents in the join",
+			Args: graphql.FieldConfigArgument{
+				"relation": &graphql.ArgumentConfig{
+					Type:        relationEnum,
+					Description: "Only children of this relation",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: defaultRelationLimit,
+					Description:  "Maximum number of children",
+				},
+			},
+			Resolve: sg.resolverBuilder.BuildJoinChildrenResolver(join, queryConfig),
+		})
+
+		sg.names.reserve(docType.Name(), "parent")
+		docType.AddFieldConfig("parent", &graphql.Field{
+			Type:        docType,
+			Description: "Parent document in the join",
+			Resolve:     sg.resolverBuilder.BuildJoinParentResolver(join, queryConfig),
+		})
+	}
+	return nil
+}
+
+// Aggregations of the children lookup
+const (
+	joinChildrenAggregation = "_children" // Terms aggregation on the parent ID of the children
+	joinChildrenHits        = "_hits"     // Children of each parent
+)
+
+// BuildJoinChildrenResolver creates the resolver of the children field, looking up the children
+// of all documents on a level with one terms query on the parent ID of the children
+// Documents are identified by the _id of their hit
+func (rb *ResolverBuilder) BuildJoinChildrenResolver(join *joinRelations, queryConfig *QueryConfig) graphql.FieldResolveFn {
+	mapping := queryConfig.GetMapping()
+	childrenID := &loaderID{field: "children"}
+
+	return func(params graphql.ResolveParams) (any, error) {
+		source, ok := params.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		name, _ := joinValue(source[join.field])
+		id := relationKeys(source[hitIDKey])
+		if name == "" || len(id) != 1 {
+			return nil, nil
+		}
+
+		var relations []string
+		for _, child := range join.children[name] {
+			if relation, ok := params.Args["relation"].(string); !ok || relation == child {
+				relations = append(relations, child)
+			}
+		}
+		limit, _ := params.Args["limit"].(int)
+		if len(relations) == 0 || limit <= 0 {
+			return []map[string]any{}, nil
+		}
+
+		httpReq, _ := getHTTPRequest(params)
+		variant := fmt.Sprintf("%s|%s|%d", name, strings.Join(relations, ","), limit)
+		loader := requestLoader(params, childrenID)
+		batch := loader.add(variant, id)
+
+		return func() (any, error) {
+			loader.run(variant, batch, func(ids []string) (*relatedHits, error) {
+				return rb.lookupChildren(params.Context, httpReq, join, queryConfig, &mapping, name, relations, ids, limit)
+			})
+			if batch.err != nil {
+				return nil, fmt.Errorf("failed to resolve children: %w", batch.err)
+			}
+			return batch.related.documents(id, limit), nil
+		}, nil
+	}
+}
+
+// lookupChildren fetches the children of the parents with the ids, up to limit per parent, with
+// a terms query and a terms aggregation on the parent ID of the children and the top hits of
+// each parent
+func (rb *ResolverBuilder) lookupChildren(ctx context.Context, httpReq *http.Request, join *joinRelations, queryConfig *QueryConfig, mapping *IndexMapping, parent string, relations, ids []string, limit int) (*relatedHits, error) {
+	if rb.esClient == nil {
+		return nil, fmt.Errorf("ES client not configured - relations require typed ES client")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Children hold the ID of their parent in the join's "<field>#<parent relation>" field, so
+	// one terms query finds the children of all parents
+	field := join.field + "#" + parent
+	query := &types.Query{Bool: &types.BoolQuery{Filter: []types.Query{
+		{Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{join.field: fieldValues(relations)}}},
+		{Terms: &types.TermsQuery{TermsQuery: map[string]types.TermsQueryField{field: fieldValues(ids)}}},
+	}}}
+	indices, req, err := relatedRequest(httpReq, queryConfig, query, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// The children are grouped by parent with their top hits
+	size := min(len(ids), maxRelationHits)
+	req.Aggregations = map[string]types.Aggregations{
+		joinChildrenAggregation: {
+			Terms: &types.TermsAggregation{Field: &field, Size: &size},
+			Aggregations: map[string]types.Aggregations{
+				joinChildrenHits: {TopHits: &types.TopHitsAggregation{Size: &limit}},
+			},
+		},
+	}
+
+	resp, err := searchTyped(ctx, rb.esClient, indices, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []map[string]any
+	terms, _ := resp.Aggregations[joinChildrenAggregation].(*types.StringTermsAggregate)
+	if terms != nil {
+		buckets, _ := terms.Buckets.([]types.StringTermsBucket)
+		for _, bucket := range buckets {
+			children, ok := bucket.Aggregations[joinChildrenHits].(*types.TopHitsAggregate)
+			if !ok {
+				continue
+			}
+			for _, hit := range children.Hits.Hits {
+				doc, err := hitDocument(hit)
+				if err != nil {
+					return nil, err
+				}
+				normalizeObjectCardinality(doc, mapping)
+				hits = append(hits, doc)
+			}
+		}
+	}
+	return groupHits(hits, func(hit map[string]any) []string {
+		if _, parent := joinValue(hit[join.field]); parent != "" {
+			return []string{parent}
+		}
+		return nil
+	}), nil
+}
+
+// BuildJoinParentResolver creates the resolver of the parent field, looking up the parents
+// of all documents on a level with one ids query
+func (rb *ResolverBuilder) BuildJoinParentResolver(join *joinRelations, queryConfig *QueryConfig) graphql.FieldResolveFn {
+	mapping := queryConfig.GetMapping()
+	parentID := &loaderID{field: "parent"}
+
+	return func(params graphql.ResolveParams) (any, error) {
+		source, ok := params.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		_, parent := joinValue(source[join.field])
+		if parent == "" {
+			return nil, nil
+		}
+
+		httpReq, _ := getHTTPRequest(params)
+		loader := requestLoader(params, parentID)
+		batch := loader.add("", []string{parent})
+
+		return func() (any, error) {
+			loader.run("", batch, func(ids []string) (*relatedHits, error) {
+				query := &types.Query{Ids: &types.IdsQuery{Values: ids}}
+				hits, err := rb.searchRelated(params.Context, httpReq, queryConfig, &mapping, query, min(len(ids), maxRelationHits), nil, nil)
+				if err != nil {
+					return nil, err
+				}
+				return groupHits(hits, func(hit map[string]any) []string {
+					return relationKeys(hit[hitIDKey])
+				}), nil
+			})
+			if batch.err != nil {
+				return nil, fmt.Errorf("failed to resolve parent: %w", batch.err)
+			}
+
+			parents := batch.related.documents([]string{parent}, 1)
+			if len(parents) == 0 {
+				return nil, nil
+			}
+			return parents[0], nil
+		}, nil
+	}
+}
@@ -0,0 +1,249 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+const joinTestMappingJSON = `{
+	"mappings": {
+		"properties": {
+			"id":       {"type": "keyword"},
+			"author":   {"type": "keyword"},
+			"channel":  {"type": "keyword"},
+			"relation": {"type": "join", "relations": {"conversation": "message"}}
+		}
+	}
+}`
+
+func joinTestConfig(t *testing.T) *Config {
+	t.Helper()
+
+	mapping, err := ParseMapping("conversations", []byte(joinTestMappingJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+	return NewConfig(WithQuery("conversations", &QueryConfig{
+		Mapping:  mapping,
+		Features: []reveald.Feature{&mockWrapperFeature{}},
+	}))
+}
+
+func TestParseJoinMapping(t *testing.T) {
+	mapping, err := ParseMapping("qa", []byte(`{"properties": {"qa": {"type": "join", "relations": {"question": ["answer", "comment"], "answer": "vote"}}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	field := mapping.GetField("qa")
+	if field.Type != FieldTypeJoin {
+		t.Fatalf("Expected join field, got %s", field.Type)
+	}
+	if children := field.Relations["question"]; len(children) != 2 || children[1] != "comment" {
+		t.Errorf("Expected question children, got %v", children)
+	}
+
+	data, err := mapping.MappingJSON()
+	if err != nil {
+		t.Fatalf("Failed to encode mapping: %v", err)
+	}
+	if !strings.Contains(string(data), `"answer": "vote"`) {
+		t.Errorf("Expected relations in mapping JSON, got %s", data)
+	}
+
+	join := mappingJoin(&mapping)
+	var args []string
+	for _, filter := range join.joinFilters() {
+		args = append(args, filter.argName)
+	}
+	expected := "hasChildAnswer hasChildComment hasChildVote hasParentAnswer hasParentQuestion"
+	if strings.Join(args, " ") != expected {
+		t.Errorf("Expected arguments %s, got %v", expected, args)
+	}
+}
+
+func TestJoinSchema(t *testing.T) {
+	sdl, err := GenerateSchemaSDL(joinTestConfig(t))
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	expected := []string{
+		"relation: JoinField",
+		"relation: ConversationsDocumentChildRelation",
+		"limit: Int",
+		"children(",
+		"parent: ConversationsDocument",
+		"hasChild: MessageFilterInput",
+		"hasParent: ConversationFilterInput",
+		"input MessageFilterInput",
+	}
+	for _, e := range expected {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+}
+
+func TestJoinFilters(t *testing.T) {
+	backend := &recordingBackend{}
+	api, err := New(backend, joinTestConfig(t))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		RequestString: `{ conversations(hasChild: {author: ["anna"]}, hasParent: {channel: ["sales"]}) { totalCount } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+
+	data, err := json.Marshal(backend.search)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	for _, e := range []string{
+		`"has_child":{"query":{"terms":{"author":["anna"]}},"type":"message"}`,
+		`"has_parent":{"parent_type":"conversation","query":{"terms":{"channel":["sales"]}}}`,
+	} {
+		if !strings.Contains(string(data), e) {
+			t.Errorf("Request should contain %s, got %s", e, data)
+		}
+	}
+	if backend.request.Has("hasChild") {
+		t.Error("Join filters should not be passed as parameters")
+	}
+}
+
+func TestJoinChildrenAndParent(t *testing.T) {
+	// The id field differs from _id, documents are identified by _id
+	docs := []map[string]any{
+		{"id": "c1", "relation": "conversation"},
+		{"id": "c2", "relation": "conversation"},
+		{"id": "m1", "relation": map[string]any{"name": "message", "parent": "_c1"}},
+		{"id": "m2", "relation": map[string]any{"name": "message", "parent": "_c1"}},
+		{"id": "m3", "relation": map[string]any{"name": "message", "parent": "_c2"}},
+	}
+	es := newFakeES(t, joinResponse(docs))
+	api, err := New(es.backend(t), joinTestConfig(t), WithESClient(es.client))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		Context:       NewRequestContext(context.Background()),
+		RequestString: `{ conversations { hits { id relation { name parent } children(limit: 1) { id } parent { id } } } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+
+	// The search, one lookup for the children of c1 and c2 and one for the parents of the messages
+	bodies := es.requests()
+	if len(bodies) != 3 {
+		t.Fatalf("Expected a search and 2 lookups, got %d: %v", len(bodies), bodies)
+	}
+	assertContains(t, bodies[1],
+		`"filter":[{"terms":{"relation":["message"]}},{"terms":{"relation#conversation":["_c1","_c2"]}}]`,
+		`"terms":{"field":"relation#conversation","size":2}`,
+		`"top_hits":{"size":1}`,
+	)
+
+	hits := result.Data.(map[string]any)["conversations"].(map[string]any)["hits"].([]any)
+	conversation := hits[0].(map[string]any)
+	if children := conversation["children"].([]any); len(children) != 1 || children[0].(map[string]any)["id"] != "m1" {
+		t.Errorf("Expected first child m1, got %v", children)
+	}
+	if conversation["parent"] != nil {
+		t.Errorf("Conversations have no parent, got %v", conversation["parent"])
+	}
+
+	message := hits[4].(map[string]any)
+	if relation := message["relation"].(map[string]any); relation["name"] != "message" || relation["parent"] != "_c2" {
+		t.Errorf("Expected message relation, got %v", relation)
+	}
+	if parent := message["parent"].(map[string]any); parent["id"] != "c2" {
+		t.Errorf("Expected parent c2, got %v", parent)
+	}
+	if children := message["children"].([]any); len(children) != 0 {
+		t.Errorf("Messages have no children, got %v", children)
+	}
+}
+
+// joinResponse answers searches of a join index with _id "_<id>": the children lookup with the
+// children of the parents in its terms query (in a terms aggregation on the parent ID with top hits), the parents
+// lookup with the documents of the ids and other searches with all documents
+func joinResponse(docs []map[string]any) func(index, body string) string {
+	return func(index, body string) string {
+		var req struct {
+			Query struct {
+				Ids *struct {
+					Values []string `json:"values"`
+				} `json:"ids"`
+				Bool struct {
+					Filter []struct {
+						Terms map[string][]string `json:"terms"`
+					} `json:"filter"`
+				} `json:"bool"`
+			} `json:"query"`
+			Aggs map[string]struct {
+				Aggs map[string]struct {
+					TopHits struct {
+						Size int `json:"size"`
+					} `json:"top_hits"`
+				} `json:"aggregations"`
+			} `json:"aggregations"`
+		}
+		_ = json.Unmarshal([]byte(body), &req)
+
+		hit := func(doc map[string]any) map[string]any {
+			return map[string]any{"_index": index, "_id": "_" + doc["id"].(string), "_source": doc}
+		}
+		var hits []map[string]any
+		children, lookupChildren := req.Aggs[joinChildrenAggregation]
+		switch {
+		case lookupChildren:
+			var parents []string
+			for _, filter := range req.Query.Bool.Filter {
+				parents = append(parents, filter.Terms["relation#conversation"]...)
+			}
+			var buckets []map[string]any
+			byParent := make(map[string][]map[string]any)
+			for _, doc := range docs {
+				if _, parent := joinValue(doc["relation"]); parent != "" && slices.Contains(parents, parent) {
+					if len(byParent[parent]) == 0 {
+						buckets = append(buckets, map[string]any{"key": parent})
+					}
+					byParent[parent] = append(byParent[parent], hit(doc))
+				}
+			}
+			for _, bucket := range buckets {
+				parentHits := byParent[bucket["key"].(string)]
+				bucket["doc_count"] = len(parentHits)
+				bucket["top_hits#"+joinChildrenHits] = map[string]any{"hits": map[string]any{"hits": parentHits[:min(len(parentHits), children.Aggs[joinChildrenHits].TopHits.Size)]}}
+			}
+			data, _ := json.Marshal(map[string]any{"sterms#" + joinChildrenAggregation: map[string]any{"buckets": buckets}})
+			return hitsResponse(nil) + `, "aggregations": ` + string(data)
+		case req.Query.Ids != nil:
+			for _, doc := range docs {
+				if slices.Contains(req.Query.Ids.Values, "_"+doc["id"].(string)) {
+					hits = append(hits, hit(doc))
+				}
+			}
+		default:
+			for _, doc := range docs {
+				hits = append(hits, hit(doc))
+			}
+		}
+		return hitsResponse(hits)
+	}
+}
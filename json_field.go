@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// parseJSONLiteral converts an inline GraphQL literal to its Go value
+func parseJSONLiteral(valueAST ast.Value) any {
+	switch v := valueAST.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		if i, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return i
+		}
+		return nil
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f
+		}
+		return nil
+	case *ast.ListValue:
+		values := make([]any, 0, len(v.Values))
+		for _, item := range v.Values {
+			values = append(values, parseJSONLiteral(item))
+		}
+		return values
+	case *ast.ObjectValue:
+		object := make(map[string]any, len(v.Fields))
+		for _, field := range v.Fields {
+			object[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return object
+	}
+	return nil
+}
+
+// jsonFieldArgs returns the arguments added to JSON document fields
+func jsonFieldArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"path": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Dot-separated path selecting a value inside the object (e.g., \"a.b\")",
+		},
+	}
+}
+
+// jsonFieldResolver resolves a JSON field from the source document, applying the path argument
+func jsonFieldResolver(field *Field) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+
+		value := source[field.Name]
+		if path, ok := p.Args["path"].(string); ok && path != "" {
+			value = selectJSONPath(value, path)
+		}
+
+		return value, nil
+	}
+}
+
+// selectJSONPath returns the value at a dot-separated path
+// Arrays are traversed element-wise, so "items.name" returns the name of every item
+// Flattened fields may store dotted keys as-is, so the full remaining path is tried first
+func selectJSONPath(value any, path string) any {
+	if path == "" || value == nil {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if direct, ok := v[path]; ok {
+			return direct
+		}
+		key, rest, _ := strings.Cut(path, ".")
+		child, ok := v[key]
+		if !ok {
+			return nil
+		}
+		return selectJSONPath(child, rest)
+	case []any:
+		var values []any
+		for _, item := range v {
+			if selected := selectJSONPath(item, path); selected != nil {
+				values = append(values, selected)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
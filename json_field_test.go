@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+func TestJSONFieldSchema(t *testing.T) {
+	config := NewConfig(
+		WithQuery("products", &QueryConfig{
+			Mapping: IndexMapping{
+				IndexName: "products",
+				Properties: map[string]*Field{
+					"name":       {Name: "name", Type: FieldTypeKeyword},
+					"metadata":   {Name: "metadata", Type: FieldTypeObject},
+					"labels":     {Name: "labels", Type: FieldTypeFlattened},
+					"attributes": {Name: "attributes", Type: FieldTypeNested},
+					"shape":      {Name: "shape", Type: "geo_shape_custom"},
+				},
+			},
+		}),
+	)
+
+	sdl, err := GenerateSchemaSDL(config)
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	expected := []string{
+		"scalar JSON",
+		"metadata(path: String): JSON",
+		"labels(path: String): JSON",
+		"attributes(path: String): [JSON]",
+		"shape(path: String): JSON",
+	}
+	for _, e := range expected {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+}
+
+func TestJSONFieldResolver(t *testing.T) {
+	resolve := jsonFieldResolver(&Field{Name: "metadata", Type: FieldTypeObject})
+	source := map[string]any{
+		"metadata": map[string]any{
+			"a":          map[string]any{"b": "value"},
+			"items":      []any{map[string]any{"name": "x"}, map[string]any{"name": "y"}},
+			"dotted.key": 42,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected any
+	}{
+		{"whole object", "", source["metadata"]},
+		{"nested path", "a.b", "value"},
+		{"array elements", "items.name", []any{"x", "y"}},
+		{"dotted key", "dotted.key", 42},
+		{"missing path", "a.c", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{}
+			if tt.path != "" {
+				args["path"] = tt.path
+			}
+			got, err := resolve(graphql.ResolveParams{Source: source, Args: args})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseJSONLiteral(t *testing.T) {
+	literal := &ast.ObjectValue{
+		Fields: []*ast.ObjectField{
+			{Name: &ast.Name{Value: "s"}, Value: &ast.StringValue{Value: "text"}},
+			{Name: &ast.Name{Value: "i"}, Value: &ast.IntValue{Value: "3"}},
+			{Name: &ast.Name{Value: "f"}, Value: &ast.FloatValue{Value: "1.5"}},
+			{Name: &ast.Name{Value: "b"}, Value: &ast.BooleanValue{Value: true}},
+			{Name: &ast.Name{Value: "l"}, Value: &ast.ListValue{Values: []ast.Value{&ast.IntValue{Value: "1"}}}},
+		},
+	}
+
+	expected := map[string]any{"s": "text", "i": int64(3), "f": 1.5, "b": true, "l": []any{int64(1)}}
+	if got := parseJSONLiteral(literal); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,337 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+// defaultKNNK is the number of nearest neighbors returned by default
+const defaultKNNK = 10
+
+// Embedder turns query text into a vector for kNN search (e.g., by calling an embedding model)
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// EmbedderFunc adapts a function to the Embedder interface
+type EmbedderFunc func(ctx context.Context, text string) ([]float32, error)
+
+// Embed implements Embedder
+func (f EmbedderFunc) Embed(ctx context.Context, text string) ([]float32, error) {
+	return f(ctx, text)
+}
+
+// KNN configures approximate nearest neighbor search over dense_vector fields
+type KNN struct {
+	// Fields are the dense_vector fields that can be searched (the first one is the default)
+	Fields []string
+
+	// Embedder turns the knn text (or the query's search text) into a vector
+	// Without an embedder, the knn argument needs a vector
+	Embedder Embedder
+
+	// K is the number of nearest neighbors (default: 10)
+	K int
+
+	// NumCandidates is the number of candidates considered per shard (default: the ES default)
+	NumCandidates int
+}
+
+// k returns the number of nearest neighbors
+func (knn *KNN) k() int {
+	if knn.K > 0 {
+		return knn.K
+	}
+	return defaultKNNK
+}
+
+// knnInputTypeName returns the name of a query's knn input type
+func knnInputTypeName(queryName string, queryConfig *QueryConfig) string {
+	return strings.TrimSuffix(resultTypeName(queryName, queryConfig), "Result") + "KnnInput"
+}
+
+// knnEnumName returns the name of the enum of a query's vector fields
+func knnEnumName(queryName string, queryConfig *QueryConfig) string {
+	return strings.TrimSuffix(resultTypeName(queryName, queryConfig), "Result") + "VectorField"
+}
+
+// validateKNN checks that the kNN fields are dense_vector fields
+func validateKNN(knn *KNN, mapping *IndexMapping) error {
+	if knn == nil {
+		return nil
+	}
+	if len(knn.Fields) == 0 {
+		return fmt.Errorf("knn needs at least one field")
+	}
+	if knn.K < 0 || knn.NumCandidates < 0 {
+		return fmt.Errorf("knn k and numCandidates must not be negative")
+	}
+	if knn.NumCandidates > 0 && knn.NumCandidates < knn.k() {
+		return fmt.Errorf("knn numCandidates (%d) must be at least k (%d)", knn.NumCandidates, knn.k())
+	}
+	for _, path := range knn.Fields {
+		field := mapping.GetField(path)
+		if field == nil {
+			return fmt.Errorf("knn field %s not found", path)
+		}
+		if field.Type != FieldTypeDenseVector {
+			return fmt.Errorf("knn field %s must be a dense_vector field, got %s", path, field.Type)
+		}
+	}
+	return nil
+}
+
+// addKNNArgument adds the knn argument and its input type to a query
+func (sg *SchemaGenerator) addKNNArgument(args graphql.FieldConfigArgument, queryName string, queryConfig *QueryConfig) error {
+	if queryConfig.KNN == nil {
+		return nil
+	}
+
+	fieldEnum, err := sg.enumType(&Field{
+		EnumTypeName: knnEnumName(queryName, queryConfig),
+		EnumValues:   queryConfig.KNN.Fields,
+	})
+	if err != nil {
+		return err
+	}
+
+	typeName := knnInputTypeName(queryName, queryConfig)
+	inputType, ok := sg.inputCache[typeName]
+	if !ok {
+		inputType = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name:        typeName,
+			Description: "Nearest neighbor search over a vector field, combined with the query's text search",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"field": &graphql.InputObjectFieldConfig{
+					Type:        fieldEnum,
+					Description: fmt.Sprintf("Vector field to search (default: %s)", enumValueName(queryConfig.KNN.Fields[0])),
+				},
+				"vector": &graphql.InputObjectFieldConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.Float)),
+					Description: "Query vector",
+				},
+				"text": &graphql.InputObjectFieldConfig{
+					Type:        graphql.String,
+					Description: "Text turned into the query vector by the embedder (default: the query's search text)",
+				},
+				"k": &graphql.InputObjectFieldConfig{
+					Type:        graphql.Int,
+					Description: fmt.Sprintf("Number of nearest neighbors (default: %d)", queryConfig.KNN.k()),
+				},
+				"numCandidates": &graphql.InputObjectFieldConfig{
+					Type:        graphql.Int,
+					Description: "Number of candidates considered per shard",
+				},
+				"similarity": &graphql.InputObjectFieldConfig{
+					Type:        graphql.Float,
+					Description: "Minimum similarity of the neighbors",
+				},
+				"boost": &graphql.InputObjectFieldConfig{
+					Type:        graphql.Float,
+					Description: "Weight of the vector score relative to the text score",
+				},
+			},
+		})
+		sg.inputCache[typeName] = inputType
+	}
+
+	sg.names.reserve(argumentScope(queryName), "knn")
+	args["knn"] = &graphql.ArgumentConfig{
+		Type:        inputType,
+		Description: "Rank the hits by vector similarity, combined with the text query and filters",
+	}
+	return nil
+}
+
+// knnOptions are the kNN search options of a request
+type knnOptions struct {
+	field         *Field
+	path          string
+	vector        []float32
+	text          string
+	k             int
+	numCandidates int
+	similarity    *float32
+	boost         *float32
+}
+
+// knnRequest returns the kNN options of a request, or nil when the knn argument is not set
+func knnRequest(args map[string]any, knn *KNN, mapping *IndexMapping) (*knnOptions, error) {
+	input, ok := args["knn"].(map[string]any)
+	if !ok || knn == nil {
+		return nil, nil
+	}
+
+	path := knn.Fields[0]
+	if field, ok := input["field"].(string); ok && field != "" {
+		path = field
+	}
+	options := &knnOptions{
+		field:         mapping.GetField(path),
+		path:          exactFieldPath(path, mapping),
+		k:             knn.k(),
+		numCandidates: knn.NumCandidates,
+	}
+	if k, ok := input["k"].(int); ok {
+		if k <= 0 {
+			return nil, fmt.Errorf("knn k must be positive, got %d", k)
+		}
+		options.k = k
+	}
+	if numCandidates, ok := input["numCandidates"].(int); ok {
+		options.numCandidates = numCandidates
+	}
+	if options.numCandidates > 0 && options.numCandidates < options.k {
+		return nil, fmt.Errorf("knn numCandidates (%d) must be at least k (%d)", options.numCandidates, options.k)
+	}
+	if similarity, ok := input["similarity"].(float64); ok {
+		value := float32(similarity)
+		options.similarity = &value
+	}
+	if boost, ok := input["boost"].(float64); ok {
+		value := float32(boost)
+		options.boost = &value
+	}
+	options.text, _ = input["text"].(string)
+
+	// An empty vector is a missing vector, embedded from the text
+	if vector, ok := input["vector"].([]any); ok && len(vector) > 0 {
+		for _, v := range vector {
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("knn vector values must be numbers, got %v", v)
+			}
+			options.vector = append(options.vector, float32(f))
+		}
+		if err := options.checkDims(); err != nil {
+			return nil, err
+		}
+	} else if knn.Embedder == nil {
+		return nil, fmt.Errorf("knn needs a vector (no embedder is configured)")
+	}
+	return options, nil
+}
+
+// checkDims checks that the query vector matches the dimensions of the vector field
+func (ko *knnOptions) checkDims() error {
+	if ko.field != nil && ko.field.Dims > 0 && len(ko.vector) != ko.field.Dims {
+		return fmt.Errorf("knn vector for %s must have %d dimensions, got %d", ko.path, ko.field.Dims, len(ko.vector))
+	}
+	return nil
+}
+
+// query returns the knn query, embedding the knn text (or the search text of the query)
+// when no vector is given
+func (ko *knnOptions) query(ctx context.Context, embedder Embedder, query *types.Query) (*types.Query, error) {
+	vector := ko.vector
+	if len(vector) == 0 {
+		if embedder == nil {
+			return nil, fmt.Errorf("knn needs a vector (no embedder is configured)")
+		}
+		text := ko.text
+		if text == "" {
+			text = searchText(query)
+		}
+		if text == "" {
+			return nil, fmt.Errorf("knn needs a vector or a text to embed")
+		}
+		var err error
+		if vector, err = embedder.Embed(ctx, text); err != nil {
+			return nil, fmt.Errorf("failed to embed knn text: %w", err)
+		}
+		embedded := knnOptions{field: ko.field, path: ko.path, vector: vector}
+		if err := embedded.checkDims(); err != nil {
+			return nil, err
+		}
+	}
+
+	k := ko.k
+	knn := &types.KnnQuery{
+		Field:       ko.path,
+		QueryVector: vector,
+		K:           &k,
+		Similarity:  ko.similarity,
+		Boost:       ko.boost,
+	}
+	if ko.numCandidates > 0 {
+		numCandidates := ko.numCandidates
+		knn.NumCandidates = &numCandidates
+	}
+	return &types.Query{Knn: knn}, nil
+}
+
+// hybridQuery combines a knn query with a query
+// Clauses without search text become filters, which ES applies as pre-filters of the knn query,
+// so only allowed documents are among the nearest neighbors. Full-text clauses are combined with
+// the knn query in should clauses: documents match either, and the scores of both are summed.
+func hybridQuery(knn *types.Query, query *types.Query) *types.Query {
+	hybrid := &types.BoolQuery{}
+	ranking := []types.Query{*knn}
+
+	var add func(query *types.Query)
+	add = func(query *types.Query) {
+		if query == nil {
+			return
+		}
+		// Flatten bool queries of required clauses (e.g., merged root queries)
+		if b := query.Bool; b != nil && len(b.Should) == 0 && b.MinimumShouldMatch == nil && b.Boost == nil {
+			for i := range b.Must {
+				add(&b.Must[i])
+			}
+			hybrid.Filter = append(hybrid.Filter, b.Filter...)
+			hybrid.MustNot = append(hybrid.MustNot, b.MustNot...)
+			return
+		}
+		if searchText(query) != "" {
+			ranking = append(ranking, *query)
+			return
+		}
+		hybrid.Filter = append(hybrid.Filter, *query)
+	}
+	add(query)
+
+	if len(ranking) == 1 {
+		hybrid.Must = ranking
+	} else {
+		hybrid.Must = []types.Query{{Bool: &types.BoolQuery{Should: ranking, MinimumShouldMatch: 1}}}
+	}
+	return &types.Query{Bool: hybrid}
+}
+
+// knnFeature ranks the hits of a feature-based query by vector similarity
+// It rewrites the query built by the features into a hybrid query, and is registered after them
+// (before the spell check and collapse features, which read the final query)
+type knnFeature struct {
+	ctx      context.Context
+	options  *knnOptions
+	embedder Embedder
+}
+
+// Process implements reveald.Feature
+func (kf *knnFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	raw := builder.RawQuery()
+	knn, err := kf.options.query(kf.ctx, kf.embedder, raw)
+	if err != nil {
+		return nil, err
+	}
+	if raw.Bool == nil {
+		return nil, fmt.Errorf("knn search needs a bool query")
+	}
+
+	// The builder's bool query is shared, so it is rewritten in place (its should clauses stay
+	// optional boosts)
+	hybrid := hybridQuery(knn, &types.Query{Bool: &types.BoolQuery{
+		Must:    raw.Bool.Must,
+		Filter:  raw.Bool.Filter,
+		MustNot: raw.Bool.MustNot,
+	}})
+	raw.Bool.Must = hybrid.Bool.Must
+	raw.Bool.Filter = hybrid.Bool.Filter
+	raw.Bool.MustNot = hybrid.Bool.MustNot
+	return next(builder)
+}
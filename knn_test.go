@@ -0,0 +1,145 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+// brandFilterFeature filters on a brand like a dynamic filter feature
+type brandFilterFeature struct{}
+
+func (f *brandFilterFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	builder.With(types.Query{Term: map[string]types.TermQuery{"brand": {Value: "apple"}}})
+	return next(builder)
+}
+
+// recordingEmbedder returns a fixed vector and records the embedded texts
+type recordingEmbedder struct {
+	texts []string
+}
+
+func (e *recordingEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	e.texts = append(e.texts, text)
+	return []float32{0.5, 0.25, 0.125}, nil
+}
+
+func TestKNN(t *testing.T) {
+	queryConfig := &QueryConfig{
+		Mapping: IndexMapping{
+			IndexName: "products",
+			Properties: map[string]*Field{
+				"id":        {Name: "id", Type: FieldTypeKeyword},
+				"title":     {Name: "title", Type: FieldTypeText},
+				"brand":     {Name: "brand", Type: FieldTypeKeyword},
+				"tenant":    {Name: "tenant", Type: FieldTypeKeyword},
+				"embedding": {Name: "embedding", Type: FieldTypeDenseVector, Dims: 3, Similarity: "cosine"},
+			},
+		},
+		Features:              []reveald.Feature{&titleSearchFeature{}, &brandFilterFeature{}},
+		KNN:                   &KNN{Fields: []string{"embedding"}, K: 5},
+		EnableElasticQuerying: true,
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("products", queryConfig)))
+		assertContains(t, sdl, "knn: ProductsKnnInput", "input ProductsKnnInput", "field: ProductsVectorField", "vector: [Float!]", "numCandidates: Int", "similarity: Float")
+
+		notVector := *queryConfig
+		notVector.KNN = &KNN{Fields: []string{"title"}}
+		if _, err := GenerateSchemaSDL(NewConfig(WithQuery("products", &notVector))); err == nil {
+			t.Error("Expected an error for a field that is not a dense_vector field")
+		}
+	})
+
+	t.Run("feature query", func(t *testing.T) {
+		embedder := &recordingEmbedder{}
+		withEmbedder := *queryConfig
+		withEmbedder.KNN = &KNN{Fields: []string{"embedding"}, Embedder: embedder, K: 5}
+		backend := &recordingBackend{}
+		api, err := New(backend, NewConfig(WithQuery("products", &withEmbedder)))
+		if err != nil {
+			t.Fatalf("Failed to create API: %v", err)
+		}
+
+		// Without a vector, the features' search text is embedded
+		runQuery(t, api, `{ products(knn: {numCandidates: 50, boost: 2}) { totalCount } }`)
+		if len(embedder.texts) != 1 || embedder.texts[0] != "iphnoe caes" {
+			t.Errorf("Expected the search text to be embedded, got %v", embedder.texts)
+		}
+
+		query, _ := json.Marshal(backend.search.Query)
+		assertContains(t, string(query),
+			`"filter":[{"term":{"brand":{"value":"apple"}}}]`,
+			`"should":[{"knn":{"boost":2,"field":"embedding","k":5,"num_candidates":50,"query_vector":[0.5,0.25,0.125]}},{"match":{"title":{"query":"iphnoe caes"}}}]`,
+			`"minimum_should_match":1`,
+		)
+
+		// Vectors must match the field's dimensions
+		result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ products(knn: {vector: [1, 2]}) { totalCount } }`})
+		if len(result.Errors) == 0 {
+			t.Error("Expected an error for a vector with the wrong dimensions")
+		}
+	})
+
+	t.Run("typed query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [
+			{"_index": "products", "_id": "p1", "_score": 1.5, "_source": {"title": "iPhone case"}}
+		]}`))
+		tenant := *queryConfig
+		tenant.RootQueryBuilder = tenantRootQuery
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("products", &tenant))
+
+		result := graphql.Do(graphql.Params{
+			Schema:        api.GetSchema(),
+			Context:       tenantContext("acme"),
+			RequestString: `{ products(query: {term: {field: "brand", value: "apple"}}, knn: {vector: [0.1, 0.2, 0.3], k: 3, similarity: 0.8}) { totalCount hits { id } } }`,
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Query failed: %v", result.Errors)
+		}
+
+		body := es.requests()[0]
+		assertContains(t, body,
+			`"must":[{"knn":{"field":"embedding","k":3,"query_vector":[0.1,0.2,0.3],"similarity":0.8}}]`,
+			`"term":{"tenant":{"value":"acme"}}`,
+			`"term":{"brand":{"value":"apple"}}`,
+		)
+
+		// The filters are pre-filters of a pure kNN search
+		assertNotContains(t, body, "should")
+
+		// Without an embedder a vector is required
+		result = graphql.Do(graphql.Params{Schema: api.GetSchema(), Context: tenantContext("acme"), RequestString: `{ products(knn: {text: "case"}) { totalCount } }`})
+		if len(result.Errors) == 0 {
+			t.Error("Expected an error without a vector or an embedder")
+		}
+	})
+
+	t.Run("empty vector", func(t *testing.T) {
+		// An empty vector is missing, also on a field without known dimensions
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 0, "relation": "eq"}, "hits": []}`))
+		withoutDims := *queryConfig
+		withoutDims.Mapping = IndexMapping{
+			IndexName: "products",
+			Properties: map[string]*Field{
+				"title":     {Name: "title", Type: FieldTypeText},
+				"embedding": {Name: "embedding", Type: FieldTypeDenseVector},
+			},
+		}
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("products", &withoutDims))
+
+		for _, query := range []string{
+			`{ products(knn: {vector: []}) { totalCount } }`,
+			`{ products(query: {match: {field: "title", query: "case"}}, knn: {vector: []}) { totalCount } }`,
+		} {
+			if result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: query}); len(result.Errors) == 0 {
+				t.Errorf("Expected an error for an empty vector without an embedder: %s", query)
+			}
+		}
+	})
+}
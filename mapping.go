@@ -9,18 +9,94 @@ import (
 type FieldType string
 
 const (
-	FieldTypeText    FieldType = "text"
-	FieldTypeKeyword FieldType = "keyword"
-	FieldTypeLong    FieldType = "long"
-	FieldTypeInteger FieldType = "integer"
-	FieldTypeShort   FieldType = "short"
-	FieldTypeByte    FieldType = "byte"
-	FieldTypeDouble  FieldType = "double"
-	FieldTypeFloat   FieldType = "float"
-	FieldTypeBoolean FieldType = "boolean"
-	FieldTypeDate    FieldType = "date"
-	FieldTypeObject  FieldType = "object"
-	FieldTypeNested  FieldType = "nested"
+	FieldTypeText            FieldType = "text"
+	FieldTypeKeyword         FieldType = "keyword"
+	FieldTypeLong            FieldType = "long"
+	FieldTypeUnsignedLong    FieldType = "unsigned_long"
+	FieldTypeInteger         FieldType = "integer"
+	FieldTypeShort           FieldType = "short"
+	FieldTypeByte            FieldType = "byte"
+	FieldTypeDouble          FieldType = "double"
+	FieldTypeFloat           FieldType = "float"
+	FieldTypeHalfFloat       FieldType = "half_float"
+	FieldTypeScaledFloat     FieldType = "scaled_float"
+	FieldTypeBoolean         FieldType = "boolean"
+	FieldTypeDate            FieldType = "date"
+	FieldTypeDateNanos       FieldType = "date_nanos"
+	FieldTypeObject          FieldType = "object"
+	FieldTypeNested          FieldType = "nested"
+	FieldTypeFlattened       FieldType = "flattened"
+	FieldTypeIP              FieldType = "ip"
+	FieldTypeGeoPoint        FieldType = "geo_point"
+	FieldTypeGeoShape        FieldType = "geo_shape"
+	FieldTypeConstantKeyword FieldType = "constant_keyword"
+	FieldTypeWildcard        FieldType = "wildcard"
+	FieldTypeMatchOnlyText   FieldType = "match_only_text"
+	FieldTypeSearchAsYouType FieldType = "search_as_you_type"
+	FieldTypeAlias           FieldType = "alias"
+	FieldTypeIntegerRange    FieldType = "integer_range"
+	FieldTypeLongRange       FieldType = "long_range"
+	FieldTypeFloatRange      FieldType = "float_range"
+	FieldTypeDoubleRange     FieldType = "double_range"
+	FieldTypeDateRange       FieldType = "date_range"
+	FieldTypeIPRange         FieldType = "ip_range"
+	FieldTypeDenseVector     FieldType = "dense_vector"
+	FieldTypeVersion         FieldType = "version"
+	FieldTypeJoin            FieldType = "join"
+	FieldTypeCompletion      FieldType = "completion"
+	FieldTypeBinary          FieldType = "binary"
+)
+
+// knownFieldTypes holds the field types above, used to validate types given by hand
+// (e.g., in `es` struct tags)
+var knownFieldTypes = map[FieldType]bool{
+	FieldTypeText:            true,
+	FieldTypeKeyword:         true,
+	FieldTypeLong:            true,
+	FieldTypeUnsignedLong:    true,
+	FieldTypeInteger:         true,
+	FieldTypeShort:           true,
+	FieldTypeByte:            true,
+	FieldTypeDouble:          true,
+	FieldTypeFloat:           true,
+	FieldTypeHalfFloat:       true,
+	FieldTypeScaledFloat:     true,
+	FieldTypeBoolean:         true,
+	FieldTypeDate:            true,
+	FieldTypeDateNanos:       true,
+	FieldTypeObject:          true,
+	FieldTypeNested:          true,
+	FieldTypeFlattened:       true,
+	FieldTypeIP:              true,
+	FieldTypeGeoPoint:        true,
+	FieldTypeGeoShape:        true,
+	FieldTypeConstantKeyword: true,
+	FieldTypeWildcard:        true,
+	FieldTypeMatchOnlyText:   true,
+	FieldTypeSearchAsYouType: true,
+	FieldTypeAlias:           true,
+	FieldTypeIntegerRange:    true,
+	FieldTypeLongRange:       true,
+	FieldTypeFloatRange:      true,
+	FieldTypeDoubleRange:     true,
+	FieldTypeDateRange:       true,
+	FieldTypeIPRange:         true,
+	FieldTypeDenseVector:     true,
+	FieldTypeVersion:         true,
+	FieldTypeJoin:            true,
+	FieldTypeCompletion:      true,
+	FieldTypeBinary:          true,
+}
+
+// Cardinality determines whether an object field holds a single object or a list of objects
+// ES mappings do not record this, any object field may contain an array of objects
+type Cardinality string
+
+const (
+	// CardinalityOne exposes the field as a single object (the default for object fields)
+	CardinalityOne Cardinality = "one"
+	// CardinalityMany exposes the field as a list (the default for nested fields)
+	CardinalityMany Cardinality = "many"
 )
 
 // Field represents a field in an Elasticsearch mapping
@@ -29,12 +105,58 @@ type Field struct {
 	Type       FieldType
 	Properties map[string]*Field
 	Fields     map[string]*Field // Multi-fields (e.g., text.keyword)
+	Format     string            // Date format for date fields (e.g., "yyyy-MM-dd HH:mm:ss||epoch_millis")
+
+	Path          string  // Target field path for alias fields
+	ScalingFactor float64 // Scaling factor for scaled_float fields
+	Dims          int     // Number of dimensions for dense_vector fields
+	Similarity    string  // Similarity metric of dense_vector fields (e.g., "cosine", "dot_product")
+
+	// Relations of join fields: the child relation names by parent relation name
+	// (e.g., {"conversation": ["message"]})
+	Relations map[string][]string
+
+	// Cardinality overrides whether an object field is a single object or a list
+	// When empty, nested fields are lists and object fields are single objects
+	Cardinality Cardinality
+
+	// GraphQLName overrides the GraphQL name of the field (set from the field's meta
+	// "graphqlName" or QueryConfig.FieldAliases)
+	// When empty, the name is derived from Name with the configured naming strategy
+	GraphQLName string
+
+	// Description and DeprecationReason are read from the field's meta (or the mapping's _meta)
+	// and added to the GraphQL field, a non-empty DeprecationReason deprecates the field
+	Description       string
+	DeprecationReason string
+
+	// EnumValues and EnumTypeName expose a keyword field as a GraphQL enum (set from QueryConfig.FieldEnums)
+	EnumValues   []string
+	EnumTypeName string
+}
+
+// isList reports whether an object or nested field is exposed as a list
+func (f *Field) isList() bool {
+	switch f.Cardinality {
+	case CardinalityOne:
+		return false
+	case CardinalityMany:
+		return true
+	default:
+		return f.Type == FieldTypeNested
+	}
 }
 
 // IndexMapping represents the parsed Elasticsearch index mapping
 type IndexMapping struct {
 	IndexName  string
 	Properties map[string]*Field
+
+	// Read from the mapping's _meta: the description and name of the document type,
+	// and the deprecation of the queries searching the index
+	Description       string
+	GraphQLName       string
+	DeprecationReason string
 }
 
 // ParseMapping parses an Elasticsearch mapping JSON into an IndexMapping
@@ -50,10 +172,11 @@ func ParseMapping(indexName string, mappingJSON []byte) (IndexMapping, error) {
 	// - {"mappings": {"properties": {...}}}
 	// - {"index_name": {"mappings": {"properties": {...}}}}
 
-	properties, err := extractProperties(raw)
+	mappings, err := extractMappings(raw)
 	if err != nil {
 		return IndexMapping{}, err
 	}
+	properties, _ := mappings["properties"].(map[string]any)
 
 	fields := make(map[string]*Field)
 	for name, prop := range properties {
@@ -64,23 +187,32 @@ func ParseMapping(indexName string, mappingJSON []byte) (IndexMapping, error) {
 		fields[name] = field
 	}
 
-	return IndexMapping{
+	mapping := IndexMapping{
 		IndexName:  indexName,
 		Properties: fields,
-	}, nil
+	}
+
+	// Index-level metadata, and metadata of fields that cannot have meta (e.g., objects)
+	if meta, ok := mappings["_meta"].(map[string]any); ok {
+		if err := mapping.applyMappingMeta(meta); err != nil {
+			return IndexMapping{}, fmt.Errorf("failed to parse _meta: %w", err)
+		}
+	}
+
+	return mapping, nil
 }
 
-// extractProperties extracts the properties map from various ES mapping formats
-func extractProperties(raw map[string]any) (map[string]any, error) {
+// extractMappings extracts the object holding properties (and _meta) from various ES mapping formats
+func extractMappings(raw map[string]any) (map[string]any, error) {
 	// Try direct properties
-	if props, ok := raw["properties"].(map[string]any); ok {
-		return props, nil
+	if _, ok := raw["properties"].(map[string]any); ok {
+		return raw, nil
 	}
 
 	// Try mappings.properties
 	if mappings, ok := raw["mappings"].(map[string]any); ok {
-		if props, ok := mappings["properties"].(map[string]any); ok {
-			return props, nil
+		if _, ok := mappings["properties"].(map[string]any); ok {
+			return mappings, nil
 		}
 	}
 
@@ -88,8 +220,8 @@ func extractProperties(raw map[string]any) (map[string]any, error) {
 	for _, value := range raw {
 		if indexData, ok := value.(map[string]any); ok {
 			if mappings, ok := indexData["mappings"].(map[string]any); ok {
-				if props, ok := mappings["properties"].(map[string]any); ok {
-					return props, nil
+				if _, ok := mappings["properties"].(map[string]any); ok {
+					return mappings, nil
 				}
 			}
 		}
@@ -119,6 +251,33 @@ func parseField(name string, raw any) (*Field, error) {
 		field.Type = FieldTypeObject
 	}
 
+	// Get date format
+	if format, ok := fieldMap["format"].(string); ok {
+		field.Format = format
+	}
+
+	// Get type-specific parameters
+	if path, ok := fieldMap["path"].(string); ok {
+		field.Path = path
+	}
+	if factor, ok := fieldMap["scaling_factor"].(float64); ok {
+		field.ScalingFactor = factor
+	}
+	if dims, ok := fieldMap["dims"].(float64); ok {
+		field.Dims = int(dims)
+	}
+	if similarity, ok := fieldMap["similarity"].(string); ok {
+		field.Similarity = similarity
+	}
+	if relations, ok := fieldMap["relations"].(map[string]any); ok {
+		field.Relations = parseJoinRelations(relations)
+	}
+
+	// Get description, deprecation and GraphQL name
+	if meta, ok := fieldMap["meta"].(map[string]any); ok {
+		applyFieldMeta(field, meta)
+	}
+
 	// Parse nested properties (for object and nested types)
 	if props, ok := fieldMap["properties"].(map[string]any); ok {
 		for propName, propData := range props {
@@ -144,6 +303,201 @@ func parseField(name string, raw any) (*Field, error) {
 	return field, nil
 }
 
+// MergeMappings merges several index mappings into a single mapping
+// This is used when one query searches several indices (or an alias/pattern
+// covering indices) whose mappings differ. Fields present in more than one
+// mapping keep the type of the first mapping that defines them, while object
+// properties and multi-fields are merged recursively.
+func MergeMappings(indexName string, mappings ...IndexMapping) IndexMapping {
+	merged := IndexMapping{
+		IndexName:  indexName,
+		Properties: make(map[string]*Field),
+	}
+
+	for _, mapping := range mappings {
+		mergeFieldMaps(merged.Properties, mapping.Properties)
+
+		// Metadata of the first mapping that sets it
+		if merged.Description == "" {
+			merged.Description = mapping.Description
+		}
+		if merged.GraphQLName == "" {
+			merged.GraphQLName = mapping.GraphQLName
+		}
+		if merged.DeprecationReason == "" {
+			merged.DeprecationReason = mapping.DeprecationReason
+		}
+	}
+
+	return merged
+}
+
+// mergeFieldMaps merges the fields in src into dst without modifying src
+func mergeFieldMaps(dst, src map[string]*Field) {
+	for name, field := range src {
+		existing, ok := dst[name]
+		if !ok {
+			dst[name] = copyField(field)
+			continue
+		}
+		mergeFieldMaps(existing.Properties, field.Properties)
+		mergeFieldMaps(existing.Fields, field.Fields)
+	}
+}
+
+// copyField returns a deep copy of a field
+func copyField(field *Field) *Field {
+	copied := &Field{
+		Name:       field.Name,
+		Type:       field.Type,
+		Properties: make(map[string]*Field),
+		Fields:     make(map[string]*Field),
+		Format:     field.Format,
+
+		Path:          field.Path,
+		ScalingFactor: field.ScalingFactor,
+		Dims:          field.Dims,
+		Similarity:    field.Similarity,
+		Relations:     field.Relations,
+		Cardinality:   field.Cardinality,
+		GraphQLName:   field.GraphQLName,
+
+		Description:       field.Description,
+		DeprecationReason: field.DeprecationReason,
+		EnumValues:        field.EnumValues,
+		EnumTypeName:      field.EnumTypeName,
+	}
+	mergeFieldMaps(copied.Properties, field.Properties)
+	mergeFieldMaps(copied.Fields, field.Fields)
+	return copied
+}
+
+// withCardinality returns a copy of the mapping with cardinality overrides applied
+// Overrides are keyed by field path (e.g., "customer" or "order.lines")
+func (m IndexMapping) withCardinality(overrides map[string]Cardinality) IndexMapping {
+	if len(overrides) == 0 {
+		return m
+	}
+
+	copied := MergeMappings(m.IndexName, m)
+	for path, cardinality := range overrides {
+		if field := copied.GetField(path); field != nil {
+			field.Cardinality = cardinality
+		}
+	}
+	return copied
+}
+
+// validateCardinality checks that cardinality overrides name object or nested fields of a mapping
+func validateCardinality(overrides map[string]Cardinality, mapping *IndexMapping) error {
+	for _, path := range sortedKeys(overrides) {
+		field := mapping.GetField(path)
+		if field == nil {
+			return fmt.Errorf("object cardinality: field %s not found", path)
+		}
+		if field.Type != FieldTypeObject && field.Type != FieldTypeNested {
+			return fmt.Errorf("object cardinality: field %s must be an object or nested field, got %s", path, field.Type)
+		}
+		if cardinality := overrides[path]; cardinality != CardinalityOne && cardinality != CardinalityMany {
+			return fmt.Errorf("object cardinality: unknown cardinality %q for field %s", cardinality, path)
+		}
+	}
+	return nil
+}
+
+// withFieldAliases returns a copy of the mapping with GraphQL names set from aliases
+// Aliases are keyed by field path (e.g., "created_at" or "customer.first_name")
+func (m IndexMapping) withFieldAliases(aliases map[string]string) IndexMapping {
+	if len(aliases) == 0 {
+		return m
+	}
+
+	copied := MergeMappings(m.IndexName, m)
+	for path, name := range aliases {
+		if field := copied.GetField(path); field != nil {
+			field.GraphQLName = name
+		}
+	}
+	return copied
+}
+
+// validateFieldAliases checks that field aliases name fields of a mapping
+func validateFieldAliases(aliases map[string]string, mapping *IndexMapping) error {
+	for _, path := range sortedKeys(aliases) {
+		if mapping.GetField(path) == nil {
+			return fmt.Errorf("field aliases: field %s not found", path)
+		}
+	}
+	return nil
+}
+
+// MappingJSON returns the mapping as Elasticsearch mapping JSON
+// The output can be used as a create index body and parsed back with ParseMapping
+func (m IndexMapping) MappingJSON() ([]byte, error) {
+	mappings := map[string]any{
+		"properties": fieldMapToJSON(m.Properties),
+	}
+	if meta := m.mappingMetaJSON(); len(meta) > 0 {
+		mappings["_meta"] = meta
+	}
+	body := map[string]any{"mappings": mappings}
+
+	data, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mapping JSON: %w", err)
+	}
+	return data, nil
+}
+
+// fieldMapToJSON converts fields to their ES mapping representation
+func fieldMapToJSON(fields map[string]*Field) map[string]any {
+	properties := make(map[string]any, len(fields))
+	for name, field := range fields {
+		properties[name] = fieldToJSON(field)
+	}
+	return properties
+}
+
+// fieldToJSON converts a single field to its ES mapping representation
+func fieldToJSON(field *Field) map[string]any {
+	result := make(map[string]any)
+
+	// Objects are the default when properties are present, so the type is omitted
+	if field.Type != FieldTypeObject || len(field.Properties) == 0 {
+		result["type"] = string(field.Type)
+	}
+	if field.Format != "" {
+		result["format"] = field.Format
+	}
+	if field.Path != "" {
+		result["path"] = field.Path
+	}
+	if field.ScalingFactor != 0 {
+		result["scaling_factor"] = field.ScalingFactor
+	}
+	if field.Dims != 0 {
+		result["dims"] = field.Dims
+	}
+	if field.Similarity != "" {
+		result["similarity"] = field.Similarity
+	}
+	if len(field.Relations) > 0 {
+		result["relations"] = joinRelationsJSON(field.Relations)
+	}
+	// Object fields, and fields with meta values too long for ES, keep their meta in the mapping's _meta
+	if meta := fieldMetaJSON(field); len(meta) > 0 && !field.hasProperties() && fitsFieldMeta(meta) {
+		result["meta"] = meta
+	}
+	if len(field.Properties) > 0 {
+		result["properties"] = fieldMapToJSON(field.Properties)
+	}
+	if len(field.Fields) > 0 {
+		result["fields"] = fieldMapToJSON(field.Fields)
+	}
+
+	return result
+}
+
 // GetField retrieves a field by path (e.g., "user.name" or "tags.keyword")
 func (m IndexMapping) GetField(path string) *Field {
 	return getFieldByPath(m.Properties, path)
@@ -0,0 +1,271 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/feature"
+)
+
+// FetchMapping reads the mapping of an index, alias or pattern from a live cluster
+// When several indices match (e.g., an alias over time-based indices), their mappings
+// are merged; the newest index (by its creation_date setting) wins on type conflicts
+func FetchMapping(ctx context.Context, client *elasticsearch.TypedClient, indexOrAlias string) (IndexMapping, error) {
+	if client == nil {
+		return IndexMapping{}, fmt.Errorf("ES client not configured")
+	}
+
+	resp, err := client.Indices.Get(indexOrAlias).Features(feature.Mappings, feature.Settings).Do(ctx)
+	if err != nil {
+		return IndexMapping{}, fmt.Errorf("failed to fetch mapping for %s: %w", indexOrAlias, err)
+	}
+
+	return mappingFromResponse(indexOrAlias, resp)
+}
+
+// mappingFromResponse converts a get index response into a single IndexMapping
+func mappingFromResponse(indexOrAlias string, resp map[string]types.IndexState) (IndexMapping, error) {
+	if len(resp) == 0 {
+		return IndexMapping{}, fmt.Errorf("no indices found for %s", indexOrAlias)
+	}
+
+	// Newest first, so it wins when merging; names only break ties, as "logs-9" sorts after "logs-10"
+	indexNames := make([]string, 0, len(resp))
+	created := make(map[string]int64, len(resp))
+	for name, state := range resp {
+		indexNames = append(indexNames, name)
+		created[name] = creationDate(state.Settings)
+	}
+	sort.Slice(indexNames, func(i, j int) bool {
+		a, b := indexNames[i], indexNames[j]
+		if created[a] != created[b] {
+			return created[a] > created[b]
+		}
+		return a > b
+	})
+
+	mappings := make([]IndexMapping, 0, len(indexNames))
+	for _, name := range indexNames {
+		record := resp[name]
+		mappingJSON, err := json.Marshal(record.Mappings)
+		if err != nil {
+			return IndexMapping{}, fmt.Errorf("failed to encode mapping for %s: %w", name, err)
+		}
+
+		mapping, err := ParseMapping(name, mappingJSON)
+		if err != nil {
+			return IndexMapping{}, fmt.Errorf("failed to parse mapping for %s: %w", name, err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return MergeMappings(indexOrAlias, mappings...), nil
+}
+
+// creationDate returns the creation time of an index in epoch millis, or 0 when unknown
+func creationDate(settings *types.IndexSettings) int64 {
+	if settings == nil {
+		return 0
+	}
+
+	// Nested under "index" by default, at the top level with flat settings
+	value := settings.CreationDate
+	if settings.Index != nil && settings.Index.CreationDate != nil {
+		value = settings.Index.CreationDate
+	}
+
+	switch v := value.(type) {
+	case string:
+		millis, _ := strconv.ParseInt(v, 10, 64)
+		return millis
+	case float64:
+		return int64(v)
+	case json.Number:
+		millis, _ := v.Int64()
+		return millis
+	}
+	return 0
+}
+
+// FieldTypeChange describes a field whose type differs between two mappings
+type FieldTypeChange struct {
+	Path     string
+	Expected FieldType
+	Actual   FieldType
+}
+
+// MappingDrift describes the differences between a configured mapping and the cluster
+type MappingDrift struct {
+	// Index is the index, alias or pattern that was checked
+	Index string
+
+	// Added lists fields present in the cluster but not in the configured mapping
+	Added []string
+
+	// Removed lists fields present in the configured mapping but not in the cluster
+	Removed []string
+
+	// Retyped lists fields whose type differs
+	Retyped []FieldTypeChange
+}
+
+// HasDrift reports whether any differences were found
+func (d MappingDrift) HasDrift() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Retyped) > 0
+}
+
+// String returns a human readable summary of the drift
+func (d MappingDrift) String() string {
+	if !d.HasDrift() {
+		return fmt.Sprintf("%s: no mapping drift", d.Index)
+	}
+
+	var parts []string
+	if len(d.Added) > 0 {
+		parts = append(parts, fmt.Sprintf("added [%s]", strings.Join(d.Added, ", ")))
+	}
+	if len(d.Removed) > 0 {
+		parts = append(parts, fmt.Sprintf("removed [%s]", strings.Join(d.Removed, ", ")))
+	}
+	if len(d.Retyped) > 0 {
+		changes := make([]string, 0, len(d.Retyped))
+		for _, change := range d.Retyped {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", change.Path, change.Expected, change.Actual))
+		}
+		parts = append(parts, fmt.Sprintf("retyped [%s]", strings.Join(changes, ", ")))
+	}
+
+	return fmt.Sprintf("%s: %s", d.Index, strings.Join(parts, "; "))
+}
+
+// DiffMappings compares a configured mapping against the actual mapping
+// Field paths use dot notation, including object properties and multi-fields (e.g., "name.keyword")
+func DiffMappings(configured, actual IndexMapping) MappingDrift {
+	drift := MappingDrift{Index: configured.IndexName}
+	diffFieldMaps(&drift, "", configured.Properties, actual.Properties)
+
+	sort.Strings(drift.Added)
+	sort.Strings(drift.Removed)
+	sort.Slice(drift.Retyped, func(i, j int) bool {
+		return drift.Retyped[i].Path < drift.Retyped[j].Path
+	})
+
+	return drift
+}
+
+// diffFieldMaps records differences between two field maps at the given path prefix
+func diffFieldMaps(drift *MappingDrift, prefix string, configured, actual map[string]*Field) {
+	for name, field := range configured {
+		path := prefix + name
+		actualField, ok := actual[name]
+		if !ok {
+			drift.Removed = append(drift.Removed, path)
+			continue
+		}
+
+		if field.Type != actualField.Type {
+			drift.Retyped = append(drift.Retyped, FieldTypeChange{
+				Path:     path,
+				Expected: field.Type,
+				Actual:   actualField.Type,
+			})
+			continue
+		}
+
+		diffFieldMaps(drift, path+".", field.Properties, actualField.Properties)
+		diffFieldMaps(drift, path+".", field.Fields, actualField.Fields)
+	}
+
+	for name := range actual {
+		if _, ok := configured[name]; !ok {
+			drift.Added = append(drift.Added, prefix+name)
+		}
+	}
+}
+
+// CheckMappingDrift compares the mappings of all configured queries against the cluster
+// Only queries with differences are returned
+func (api *GraphQLAPI) CheckMappingDrift(ctx context.Context) ([]MappingDrift, error) {
+	if api.esClient == nil {
+		return nil, fmt.Errorf("ES client not configured - use WithESClient to enable mapping drift checks")
+	}
+
+	var configured []IndexMapping
+	for _, queryConfig := range api.config.Queries {
+		configured = append(configured, queryConfig.indexMappings()...)
+	}
+	for _, queryConfig := range api.config.PrecompiledQueries {
+		mapping := queryConfig.GetMapping()
+		mapping.IndexName = strings.Join(queryConfig.GetIndices(), ",")
+		configured = append(configured, mapping)
+	}
+	for _, queryConfig := range api.config.SuggestQueries {
+		mapping := queryConfig.Mapping
+		mapping.IndexName = strings.Join(queryConfig.GetIndices(), ",")
+		configured = append(configured, mapping)
+	}
+
+	// Queries on the same index share a mapping, check each index once
+	seen := make(map[string]bool)
+	var drifts []MappingDrift
+	for _, mapping := range configured {
+		if mapping.IndexName == "" || seen[mapping.IndexName] {
+			continue
+		}
+		seen[mapping.IndexName] = true
+
+		actual, err := FetchMapping(ctx, api.esClient, mapping.IndexName)
+		if err != nil {
+			return drifts, err
+		}
+
+		if drift := DiffMappings(mapping, actual); drift.HasDrift() {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Index < drifts[j].Index
+	})
+
+	return drifts, nil
+}
+
+// WatchMappingDrift checks for mapping drift immediately and then at every interval
+// until ctx is cancelled. onDrift is called whenever drift is found or the check fails.
+// Run it in a goroutine:
+//
+//	go api.WatchMappingDrift(ctx, 10*time.Minute, func(drifts []MappingDrift, err error) {
+//	    for _, drift := range drifts {
+//	        log.Printf("mapping drift: %s", drift)
+//	    }
+//	})
+func (api *GraphQLAPI) WatchMappingDrift(ctx context.Context, interval time.Duration, onDrift func([]MappingDrift, error)) {
+	check := func() {
+		drifts, err := api.CheckMappingDrift(ctx)
+		if err != nil || len(drifts) > 0 {
+			onDrift(drifts, err)
+		}
+	}
+
+	check()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
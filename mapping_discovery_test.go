@@ -0,0 +1,138 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/indices/get"
+)
+
+func TestMappingFromResponse(t *testing.T) {
+	// Two indices behind an alias, as returned by GET /leads
+	body := []byte(`{
+		"leads-2023": {
+			"mappings": {
+				"properties": {
+					"id": {"type": "keyword"},
+					"amount": {"type": "integer"}
+				}
+			}
+		},
+		"leads-2024": {
+			"mappings": {
+				"properties": {
+					"id": {"type": "keyword"},
+					"amount": {"type": "long"},
+					"title": {"type": "text", "fields": {"keyword": {"type": "keyword"}}},
+					"customer": {"properties": {"name": {"type": "keyword"}}}
+				}
+			}
+		}
+	}`)
+
+	var resp get.Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	mapping, err := mappingFromResponse("leads", resp)
+	if err != nil {
+		t.Fatalf("Failed to convert mapping: %v", err)
+	}
+
+	if mapping.IndexName != "leads" {
+		t.Errorf("Expected index name 'leads', got %s", mapping.IndexName)
+	}
+
+	// Newest index wins on type conflicts
+	if field := mapping.GetField("amount"); field == nil || field.Type != FieldTypeLong {
+		t.Errorf("Expected amount to be long, got %+v", field)
+	}
+
+	if field := mapping.GetField("title"); field == nil || field.Fields["keyword"] == nil {
+		t.Errorf("Expected title with keyword multi-field, got %+v", field)
+	}
+
+	if field := mapping.GetField("customer.name"); field == nil || field.Type != FieldTypeKeyword {
+		t.Errorf("Expected customer.name to be keyword, got %+v", field)
+	}
+
+	if _, err := mappingFromResponse("missing", get.Response{}); err == nil {
+		t.Error("Expected error for empty response")
+	}
+}
+
+func TestMappingFromResponseCreationDate(t *testing.T) {
+	// "logs-9" sorts after "logs-10" by name, but logs-10 was created later
+	body := []byte(`{
+		"logs-9": {
+			"mappings": {"properties": {"status": {"type": "integer"}}},
+			"settings": {"index": {"creation_date": "1700000000000"}}
+		},
+		"logs-10": {
+			"mappings": {"properties": {"status": {"type": "keyword"}}},
+			"settings": {"index": {"creation_date": "1710000000000"}}
+		}
+	}`)
+
+	var resp get.Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	mapping, err := mappingFromResponse("logs", resp)
+	if err != nil {
+		t.Fatalf("Failed to convert mapping: %v", err)
+	}
+
+	if field := mapping.GetField("status"); field == nil || field.Type != FieldTypeKeyword {
+		t.Errorf("Expected the most recently created index to win, got %+v", field)
+	}
+}
+
+func TestDiffMappings(t *testing.T) {
+	configured := IndexMapping{
+		IndexName: "leads",
+		Properties: map[string]*Field{
+			"id":     {Name: "id", Type: FieldTypeKeyword},
+			"amount": {Name: "amount", Type: FieldTypeInteger},
+			"legacy": {Name: "legacy", Type: FieldTypeKeyword},
+			"customer": {Name: "customer", Type: FieldTypeObject, Properties: map[string]*Field{
+				"name": {Name: "name", Type: FieldTypeKeyword},
+			}},
+		},
+	}
+	actual := IndexMapping{
+		IndexName: "leads",
+		Properties: map[string]*Field{
+			"id":     {Name: "id", Type: FieldTypeKeyword},
+			"amount": {Name: "amount", Type: FieldTypeLong},
+			"status": {Name: "status", Type: FieldTypeKeyword},
+			"customer": {Name: "customer", Type: FieldTypeObject, Properties: map[string]*Field{
+				"name":  {Name: "name", Type: FieldTypeKeyword},
+				"email": {Name: "email", Type: FieldTypeKeyword},
+			}},
+		},
+	}
+
+	drift := DiffMappings(configured, actual)
+
+	if !drift.HasDrift() {
+		t.Fatal("Expected drift")
+	}
+	if !reflect.DeepEqual(drift.Added, []string{"customer.email", "status"}) {
+		t.Errorf("Unexpected added fields: %v", drift.Added)
+	}
+	if !reflect.DeepEqual(drift.Removed, []string{"legacy"}) {
+		t.Errorf("Unexpected removed fields: %v", drift.Removed)
+	}
+	expectedRetyped := []FieldTypeChange{{Path: "amount", Expected: FieldTypeInteger, Actual: FieldTypeLong}}
+	if !reflect.DeepEqual(drift.Retyped, expectedRetyped) {
+		t.Errorf("Unexpected retyped fields: %v", drift.Retyped)
+	}
+
+	if DiffMappings(actual, actual).HasDrift() {
+		t.Error("Expected no drift for identical mappings")
+	}
+}
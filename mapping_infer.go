@@ -0,0 +1,220 @@
+package graphql
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+)
+
+// maxKeywordLength is the longest string that is inferred as keyword rather than text
+const maxKeywordLength = 256
+
+// inferDateLayouts are the string formats recognised as dates
+var inferDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.000",
+	time.DateOnly,
+}
+
+// InferMapping builds an IndexMapping from sample documents
+// This is meant for prototyping a schema before the index mapping is finalised.
+// Types are detected per field across all documents:
+//   - strings that parse as dates become date, short strings without whitespace
+//     become keyword, other strings become text with a keyword multi-field
+//   - whole numbers become long, numbers with a fraction become double
+//   - objects become object, arrays of objects become nested
+//   - arrays of scalars take the type of their elements
+//
+// When documents disagree, the more general type wins (e.g., long and double → double,
+// keyword and text → text). Fields that are only ever null are omitted.
+// Use MappingJSON to emit the inferred Elasticsearch mapping.
+func InferMapping(indexName string, docs []map[string]any) IndexMapping {
+	properties := make(map[string]*Field)
+	for _, doc := range docs {
+		inferProperties(properties, doc)
+	}
+
+	return IndexMapping{
+		IndexName:  indexName,
+		Properties: properties,
+	}
+}
+
+// inferProperties adds the fields of one document to properties
+func inferProperties(properties map[string]*Field, doc map[string]any) {
+	for name, value := range doc {
+		field := inferField(name, value)
+		if field == nil {
+			continue
+		}
+		properties[name] = mergeInferredField(properties[name], field)
+	}
+}
+
+// inferField infers the field for a single value, returns nil when the type cannot be determined
+func inferField(name string, value any) *Field {
+	field := &Field{
+		Name:       name,
+		Properties: make(map[string]*Field),
+		Fields:     make(map[string]*Field),
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bool:
+		field.Type = FieldTypeBoolean
+	case string:
+		field.Type = inferStringType(v)
+	case float64:
+		field.Type = inferNumberType(v)
+	case float32:
+		field.Type = inferNumberType(float64(v))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		field.Type = FieldTypeLong
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			field.Type = FieldTypeLong
+		} else {
+			field.Type = FieldTypeDouble
+		}
+	case map[string]any:
+		field.Type = FieldTypeObject
+		inferProperties(field.Properties, v)
+	case []any:
+		return inferArrayField(name, v)
+	default:
+		return nil
+	}
+
+	if field.Type == FieldTypeText {
+		field.Fields["keyword"] = &Field{Name: "keyword", Type: FieldTypeKeyword}
+	}
+
+	return field
+}
+
+// inferArrayField infers the field for an array value
+// Arrays of objects become nested, arrays of scalars take their element type
+func inferArrayField(name string, values []any) *Field {
+	var field *Field
+	for _, value := range values {
+		elem := inferField(name, value)
+		if elem == nil {
+			continue
+		}
+		if elem.Type == FieldTypeObject {
+			elem.Type = FieldTypeNested
+		}
+		field = mergeInferredField(field, elem)
+	}
+	return field
+}
+
+// inferStringType detects dates, keywords and text
+func inferStringType(value string) FieldType {
+	for _, layout := range inferDateLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return FieldTypeDate
+		}
+	}
+
+	if len(value) > maxKeywordLength || strings.ContainsAny(value, " \t\n") {
+		return FieldTypeText
+	}
+
+	return FieldTypeKeyword
+}
+
+// inferNumberType detects whole numbers (JSON decodes all numbers as float64)
+func inferNumberType(value float64) FieldType {
+	if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+		return FieldTypeLong
+	}
+	return FieldTypeDouble
+}
+
+// mergeInferredField combines the types inferred for the same field from different values
+func mergeInferredField(existing, field *Field) *Field {
+	if existing == nil {
+		return field
+	}
+
+	existing.Type = widenFieldType(existing.Type, field.Type)
+
+	for name, prop := range field.Properties {
+		existing.Properties[name] = mergeInferredField(existing.Properties[name], prop)
+	}
+
+	// Text keeps its keyword multi-field, other types have none
+	if existing.Type == FieldTypeText {
+		existing.Fields["keyword"] = &Field{Name: "keyword", Type: FieldTypeKeyword}
+	} else {
+		existing.Fields = make(map[string]*Field)
+	}
+
+	return existing
+}
+
+// widenFieldType returns the most general type that can hold values of both types
+func widenFieldType(a, b FieldType) FieldType {
+	if a == b {
+		return a
+	}
+
+	isObject := func(t FieldType) bool { return t == FieldTypeObject || t == FieldTypeNested }
+	isNumber := func(t FieldType) bool { return t == FieldTypeLong || t == FieldTypeDouble }
+
+	switch {
+	case isObject(a) && isObject(b):
+		return FieldTypeNested
+	case isObject(a):
+		return a
+	case isObject(b):
+		return b
+	case isNumber(a) && isNumber(b):
+		return FieldTypeDouble
+	case a == FieldTypeText || b == FieldTypeText:
+		return FieldTypeText
+	default:
+		// Mixed scalars (e.g., dates and free-form strings) are kept as keyword
+		return FieldTypeKeyword
+	}
+}
+
+// InferCardinality detects object fields that hold arrays of objects in sample documents
+// The result can be used as QueryConfig.ObjectCardinality for existing mappings, where
+// object fields are otherwise exposed as single objects. Only fields seen as arrays are
+// returned (as CardinalityMany), keyed by field path.
+func InferCardinality(docs []map[string]any) map[string]Cardinality {
+	cardinality := make(map[string]Cardinality)
+	for _, doc := range docs {
+		inferObjectCardinality(cardinality, "", doc)
+	}
+	return cardinality
+}
+
+// inferObjectCardinality records the object fields of one object below prefix
+func inferObjectCardinality(cardinality map[string]Cardinality, prefix string, obj map[string]any) {
+	for name, value := range obj {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			inferObjectCardinality(cardinality, path, v)
+		case []any:
+			for _, item := range v {
+				if itemMap, ok := item.(map[string]any); ok {
+					cardinality[path] = CardinalityMany
+					inferObjectCardinality(cardinality, path, itemMap)
+				}
+			}
+		}
+	}
+}
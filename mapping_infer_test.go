@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInferMapping(t *testing.T) {
+	var docs []map[string]any
+	if err := json.Unmarshal([]byte(`[
+		{
+			"id": "lead-1",
+			"title": "Blue car for sale",
+			"amount": 100,
+			"score": 4,
+			"active": true,
+			"createdAt": "2024-01-15T10:00:00Z",
+			"customer": {"name": "Anna", "age": 32},
+			"processes": [{"name": "sale", "tasks": [{"status": "open"}]}],
+			"tags": ["new", "hot"],
+			"notes": null
+		},
+		{
+			"id": "lead-2",
+			"title": "Red",
+			"amount": 99.5,
+			"createdAt": "2024-02-01",
+			"customer": {"email": "anna@example.com"}
+		}
+	]`), &docs); err != nil {
+		t.Fatalf("Failed to decode documents: %v", err)
+	}
+
+	mapping := InferMapping("leads", docs)
+
+	expected := map[string]FieldType{
+		"id":                     FieldTypeKeyword,
+		"title":                  FieldTypeText,
+		"amount":                 FieldTypeDouble,
+		"score":                  FieldTypeLong,
+		"active":                 FieldTypeBoolean,
+		"createdAt":              FieldTypeDate,
+		"customer":               FieldTypeObject,
+		"customer.name":          FieldTypeKeyword,
+		"customer.age":           FieldTypeLong,
+		"customer.email":         FieldTypeKeyword,
+		"processes":              FieldTypeNested,
+		"processes.tasks":        FieldTypeNested,
+		"processes.tasks.status": FieldTypeKeyword,
+		"tags":                   FieldTypeKeyword,
+		"title.keyword":          FieldTypeKeyword,
+	}
+	for path, fieldType := range expected {
+		field := mapping.GetField(path)
+		if field == nil {
+			t.Errorf("Expected field %s", path)
+			continue
+		}
+		if field.Type != fieldType {
+			t.Errorf("Expected %s to be %s, got %s", path, fieldType, field.Type)
+		}
+	}
+
+	if mapping.GetField("notes") != nil {
+		t.Error("Fields that are only null should be omitted")
+	}
+
+	// The emitted mapping JSON parses back to the same mapping
+	data, err := mapping.MappingJSON()
+	if err != nil {
+		t.Fatalf("Failed to emit mapping JSON: %v", err)
+	}
+	parsed, err := ParseMapping("leads", data)
+	if err != nil {
+		t.Fatalf("Failed to parse emitted mapping: %v", err)
+	}
+	if drift := DiffMappings(mapping, parsed); drift.HasDrift() {
+		t.Errorf("Emitted mapping should round-trip, got drift: %s", drift)
+	}
+}
+
+func TestInferCardinality(t *testing.T) {
+	docs := []map[string]any{
+		{
+			"customer": map[string]any{"name": "Alice"},
+			"order": map[string]any{
+				"lines": []any{map[string]any{"sku": "A"}, map[string]any{"sku": "B"}},
+			},
+		},
+		{
+			"customer": map[string]any{"name": "Bob"},
+			"tags":     []any{map[string]any{"label": "vip"}},
+			"labels":   []any{"a", "b"},
+		},
+	}
+
+	expected := map[string]Cardinality{
+		"order.lines": CardinalityMany,
+		"tags":        CardinalityMany,
+	}
+	if got := InferCardinality(docs); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
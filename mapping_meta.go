@@ -0,0 +1,171 @@
+package graphql
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Keys read from field `meta` and mapping `_meta`
+// Field meta only holds strings in ES, so "deprecated" may be "true" or true
+const (
+	metaDescription       = "description"
+	metaDeprecated        = "deprecated"
+	metaDeprecationReason = "deprecationReason"
+	metaGraphQLName       = "graphqlName"
+	metaFields            = "fields" // _meta only: meta of fields keyed by path, including object fields
+)
+
+// maxFieldMetaLength is the maximum length of a field meta value in ES
+const maxFieldMetaLength = 50
+
+// applyFieldMeta sets the description, deprecation and GraphQL name of a field from its meta
+func applyFieldMeta(field *Field, meta map[string]any) {
+	if description, ok := meta[metaDescription].(string); ok {
+		field.Description = description
+	}
+	if name, ok := meta[metaGraphQLName].(string); ok {
+		field.GraphQLName = name
+	}
+	if reason := deprecationReason(meta); reason != "" {
+		field.DeprecationReason = reason
+	}
+}
+
+// deprecationReason returns the deprecation reason in meta, or "" if not deprecated
+// A reason alone marks the field as deprecated, "deprecated" without a reason uses the GraphQL default
+func deprecationReason(meta map[string]any) string {
+	if reason, ok := meta[metaDeprecationReason].(string); ok && reason != "" {
+		return reason
+	}
+	switch deprecated := meta[metaDeprecated].(type) {
+	case bool:
+		if deprecated {
+			return graphql.DefaultDeprecationReason
+		}
+	case string:
+		if deprecated == "true" {
+			return graphql.DefaultDeprecationReason
+		}
+	}
+	return ""
+}
+
+// applyMappingMeta applies the index-level _meta of a mapping
+// Field entries override the field's own meta
+func (m *IndexMapping) applyMappingMeta(meta map[string]any) error {
+	if description, ok := meta[metaDescription].(string); ok {
+		m.Description = description
+	}
+	if name, ok := meta[metaGraphQLName].(string); ok {
+		m.GraphQLName = name
+	}
+	m.DeprecationReason = deprecationReason(meta)
+
+	fields, _ := meta[metaFields].(map[string]any)
+	for path, raw := range fields {
+		fieldMeta, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("_meta of field %s is not an object", path)
+		}
+		// Entries of unknown fields are skipped: the mapping may have changed since the _meta
+		// was written, e.g., a field was removed
+		if field := m.GetField(path); field != nil {
+			applyFieldMeta(field, fieldMeta)
+		}
+	}
+	return nil
+}
+
+// fieldMetaJSON returns the meta of a field as ES field meta (string values only)
+func fieldMetaJSON(field *Field) map[string]any {
+	meta := make(map[string]any)
+	if field.Description != "" {
+		meta[metaDescription] = field.Description
+	}
+	if field.GraphQLName != "" {
+		meta[metaGraphQLName] = field.GraphQLName
+	}
+	if field.DeprecationReason != "" {
+		meta[metaDeprecated] = "true"
+		meta[metaDeprecationReason] = field.DeprecationReason
+	}
+	return meta
+}
+
+// fitsFieldMeta reports whether meta can be stored as ES field meta, which limits the length of values
+func fitsFieldMeta(meta map[string]any) bool {
+	for _, value := range meta {
+		if s, ok := value.(string); ok && utf8.RuneCountInString(s) > maxFieldMetaLength {
+			return false
+		}
+	}
+	return true
+}
+
+// mappingMetaJSON returns the _meta of a mapping
+// Object fields cannot have `meta` in ES, and field meta values are limited to 50 characters,
+// so the meta of object fields and of fields with longer values is stored under "fields"
+func (m IndexMapping) mappingMetaJSON() map[string]any {
+	meta := make(map[string]any)
+	if m.Description != "" {
+		meta[metaDescription] = m.Description
+	}
+	if m.GraphQLName != "" {
+		meta[metaGraphQLName] = m.GraphQLName
+	}
+	if m.DeprecationReason != "" {
+		meta[metaDeprecated] = true
+		meta[metaDeprecationReason] = m.DeprecationReason
+	}
+
+	fields := make(map[string]any)
+	collectFieldMeta(m.Properties, "", fields)
+	if len(fields) > 0 {
+		meta[metaFields] = fields
+	}
+	return meta
+}
+
+// collectFieldMeta collects, by path, the meta of fields that cannot be stored as field meta
+func collectFieldMeta(properties map[string]*Field, prefix string, fields map[string]any) {
+	for name, field := range properties {
+		path := prefix + name
+		if meta := fieldMetaJSON(field); len(meta) > 0 && (field.hasProperties() || !fitsFieldMeta(meta)) {
+			fields[path] = meta
+		}
+		if field.hasProperties() {
+			collectFieldMeta(field.Properties, path+".", fields)
+		}
+	}
+}
+
+// hasProperties reports whether a field is an object or nested field
+func (f *Field) hasProperties() bool {
+	return f.Type == FieldTypeObject || f.Type == FieldTypeNested
+}
+
+// documentTypeName returns the name of the document type of an index
+// (the mapping's graphqlName, or "{IndexName}Document")
+func documentTypeName(mapping *IndexMapping) string {
+	if mapping.GraphQLName != "" {
+		return capitalize(sanitizeFieldName(mapping.GraphQLName))
+	}
+	return fmt.Sprintf("%sDocument", sanitizeTypeName(mapping.IndexName))
+}
+
+// describeArgument returns the description of a filter argument, noting deprecated fields
+// Arguments cannot be deprecated in the GraphQL version used, so the reason is part of the description
+func describeArgument(field *Field) string {
+	switch {
+	case field == nil:
+		return ""
+	case field.DeprecationReason == "":
+		return field.Description
+	case field.Description == "":
+		return "Deprecated: " + field.DeprecationReason
+	default:
+		return field.Description + " (deprecated: " + field.DeprecationReason + ")"
+	}
+}
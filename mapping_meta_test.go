@@ -0,0 +1,250 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+	"github.com/reveald/reveald/v2/featureset"
+)
+
+const metaTestMappingJSON = `{
+	"mappings": {
+		"_meta": {
+			"description": "Products in the catalogue",
+			"graphqlName": "Product",
+			"fields": {
+				"dimensions": {"description": "Package dimensions"}
+			}
+		},
+		"properties": {
+			"sku": {"type": "keyword", "meta": {"description": "Stock keeping unit"}},
+			"legacy_code": {"type": "keyword", "meta": {"deprecated": "true"}},
+			"old_price": {"type": "float", "meta": {"deprecationReason": "Use price"}},
+			"price": {"type": "float"},
+			"cust_nm": {"type": "keyword", "meta": {"graphqlName": "customerName"}},
+			"dimensions": {
+				"properties": {
+					"width": {"type": "float", "meta": {"description": "Width in cm"}}
+				}
+			}
+		}
+	}
+}`
+
+func TestParseMappingMeta(t *testing.T) {
+	mapping, err := ParseMapping("products", []byte(metaTestMappingJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	if mapping.Description != "Products in the catalogue" || mapping.GraphQLName != "Product" {
+		t.Errorf("Unexpected index meta: %q, %q", mapping.Description, mapping.GraphQLName)
+	}
+
+	tests := []struct {
+		path        string
+		description string
+		deprecation string
+		graphQLName string
+	}{
+		{"sku", "Stock keeping unit", "", ""},
+		{"legacy_code", "", graphql.DefaultDeprecationReason, ""},
+		{"old_price", "", "Use price", ""},
+		{"cust_nm", "", "", "customerName"},
+		{"dimensions", "Package dimensions", "", ""},
+		{"dimensions.width", "Width in cm", "", ""},
+	}
+	for _, tt := range tests {
+		field := mapping.GetField(tt.path)
+		if field == nil {
+			t.Fatalf("Field %s not found", tt.path)
+		}
+		if field.Description != tt.description || field.DeprecationReason != tt.deprecation || field.GraphQLName != tt.graphQLName {
+			t.Errorf("%s: got (%q, %q, %q), want (%q, %q, %q)", tt.path,
+				field.Description, field.DeprecationReason, field.GraphQLName,
+				tt.description, tt.deprecation, tt.graphQLName)
+		}
+	}
+}
+
+func TestParseMappingMetaUnknownField(t *testing.T) {
+	// A field removed from the mapping is skipped, not an error
+	mapping, err := ParseMapping("products", []byte(`{"_meta": {"fields": {"missing": {}, "sku": {"description": "SKU"}}}, "properties": {"sku": {"type": "keyword"}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+	if mapping.GetField("sku").Description != "SKU" {
+		t.Errorf("Expected the meta of known fields to apply, got %q", mapping.GetField("sku").Description)
+	}
+}
+
+func TestMappingJSONLongMeta(t *testing.T) {
+	description := strings.Repeat("Stock keeping unit, ", 3)
+	mapping := IndexMapping{IndexName: "products", Properties: map[string]*Field{
+		"sku":  {Name: "sku", Type: FieldTypeKeyword, Description: description},
+		"name": {Name: "name", Type: FieldTypeKeyword, Description: "Name"},
+	}}
+
+	data, err := mapping.MappingJSON()
+	if err != nil {
+		t.Fatalf("Failed to encode mapping: %v", err)
+	}
+
+	// ES limits field meta values to 50 characters, longer ones are kept in _meta
+	var raw struct {
+		Mappings struct {
+			Meta struct {
+				Fields map[string]map[string]string `json:"fields"`
+			} `json:"_meta"`
+			Properties map[string]struct {
+				Meta map[string]string `json:"meta"`
+			} `json:"properties"`
+		} `json:"mappings"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Failed to decode mapping: %v", err)
+	}
+	if raw.Mappings.Properties["sku"].Meta != nil || raw.Mappings.Meta.Fields["sku"]["description"] != description {
+		t.Errorf("Expected the long description in _meta, got %s", data)
+	}
+	if raw.Mappings.Properties["name"].Meta["description"] != "Name" {
+		t.Errorf("Expected the short description in field meta, got %s", data)
+	}
+
+	parsed, err := ParseMapping("products", data)
+	if err != nil {
+		t.Fatalf("Failed to parse encoded mapping: %v", err)
+	}
+	if parsed.GetField("sku").Description != description {
+		t.Errorf("Round trip changed the description:\n%s", data)
+	}
+}
+
+func TestMappingJSONMetaRoundTrip(t *testing.T) {
+	mapping, err := ParseMapping("products", []byte(metaTestMappingJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	data, err := mapping.MappingJSON()
+	if err != nil {
+		t.Fatalf("Failed to encode mapping: %v", err)
+	}
+
+	parsed, err := ParseMapping("products", data)
+	if err != nil {
+		t.Fatalf("Failed to parse encoded mapping: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, mapping) {
+		t.Errorf("Round trip changed the mapping:\n%s", data)
+	}
+}
+
+func metaTestConfig(t *testing.T) *Config {
+	mapping, err := ParseMapping("products", []byte(metaTestMappingJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	return NewConfig(
+		WithQuery("products", &QueryConfig{
+			Mapping: mapping,
+			Features: []reveald.Feature{
+				featureset.NewSortingFeature("sort",
+					featureset.WithSortOption("old_price-desc", "old_price", false),
+					featureset.WithSortOption("price-desc", "price", false),
+				),
+			},
+			AggregationFields:  []string{"legacy_code"},
+			EnableAggregations: true,
+			EnableSorting:      true,
+		}),
+	)
+}
+
+func TestMetaSchemaSDL(t *testing.T) {
+	sdl, err := GenerateSchemaSDL(metaTestConfig(t))
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	expected := []string{
+		"# Products in the catalogue\ntype Product {",
+		"\"\"\"Stock keeping unit\"\"\"\n  sku: String",
+		"legacy_code: String @deprecated\n",
+		"old_price: Float @deprecated(reason: \"Use price\")",
+		"customerName: String",
+		"\"\"\"Width in cm\"\"\"\n  width: Float",
+		"legacy_code: [Bucket] @deprecated\n",
+		"old_price_desc @deprecated(reason: \"Use price\")",
+		"hits: [Product]",
+	}
+	for _, e := range expected {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+
+	if strings.Contains(sdl, "price_desc @deprecated\n") || strings.Contains(sdl, "  price: Float @deprecated") {
+		t.Errorf("price should not be deprecated:\n%s", sdl)
+	}
+}
+
+func TestMetaIntrospection(t *testing.T) {
+	schema, err := GenerateSchema(metaTestConfig(t))
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			product: __type(name: "Product") {
+				description
+				fields(includeDeprecated: true) { name description isDeprecated deprecationReason }
+			}
+			query: __type(name: "Query") {
+				fields { name args { name description } }
+			}
+		}`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Introspection failed: %v", result.Errors)
+	}
+
+	data := result.Data.(map[string]any)
+	product := data["product"].(map[string]any)
+	if product["description"] != "Products in the catalogue" {
+		t.Errorf("Expected type description, got %v", product["description"])
+	}
+
+	fields := make(map[string]map[string]any)
+	for _, f := range product["fields"].([]any) {
+		field := f.(map[string]any)
+		fields[field["name"].(string)] = field
+	}
+	if fields["old_price"]["isDeprecated"] != true || fields["old_price"]["deprecationReason"] != "Use price" {
+		t.Errorf("Expected old_price to be deprecated, got %v", fields["old_price"])
+	}
+	if fields["sku"]["description"] != "Stock keeping unit" || fields["sku"]["isDeprecated"] != false {
+		t.Errorf("Unexpected sku field: %v", fields["sku"])
+	}
+
+	args := make(map[string]any)
+	for _, f := range data["query"].(map[string]any)["fields"].([]any) {
+		for _, a := range f.(map[string]any)["args"].([]any) {
+			arg := a.(map[string]any)
+			args[arg["name"].(string)] = arg["description"]
+		}
+	}
+	if args["sku"] != "Stock keeping unit" {
+		t.Errorf("Expected sku argument description, got %v", args["sku"])
+	}
+	if args["legacy_code"] != "Deprecated: "+graphql.DefaultDeprecationReason {
+		t.Errorf("Expected legacy_code argument to note the deprecation, got %v", args["legacy_code"])
+	}
+}
@@ -0,0 +1,226 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// MappingFromStruct builds an IndexMapping from the document struct T
+// Field names come from `json` tags, types from `es` tags:
+//
+//	type Lead struct {
+//	    ID        string    `json:"id" es:"keyword"`
+//	    Title     string    `json:"title" es:"text,keyword"`          // text with a keyword multi-field
+//	    Name      string    `json:"name" es:"text,sortable:keyword"` // named multi-field
+//	    CreatedAt time.Time `json:"createdAt" es:"date"`
+//	    Processes []Process `json:"processes" es:"nested"`
+//	    Internal  string    `json:"internal" es:"-"`                  // excluded
+//	}
+//
+// Fields without an `es` tag get their type from the Go type: string → keyword,
+// integers → long/integer/short/byte (uint64 → unsigned_long), floats → double/float,
+// bool → boolean, time.Time → date, []byte → binary, structs and slices of structs → object.
+// Use MappingJSON to export the Elasticsearch mapping.
+func MappingFromStruct[T any](indexName string) (IndexMapping, error) {
+	return MappingFromType(indexName, reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// MappingFromType is the reflection-based equivalent of MappingFromStruct
+func MappingFromType(indexName string, t reflect.Type) (IndexMapping, error) {
+	t = derefType(t)
+	if t.Kind() != reflect.Struct {
+		return IndexMapping{}, fmt.Errorf("expected a struct type, got %s", t)
+	}
+
+	properties, err := structProperties(t, map[reflect.Type]bool{})
+	if err != nil {
+		return IndexMapping{}, err
+	}
+
+	return IndexMapping{
+		IndexName:  indexName,
+		Properties: properties,
+	}, nil
+}
+
+// structProperties converts the exported fields of a struct to mapping fields
+// visiting tracks the struct types on the current path to reject recursive types
+func structProperties(t reflect.Type, visiting map[reflect.Type]bool) (map[string]*Field, error) {
+	if visiting[t] {
+		return nil, fmt.Errorf("recursive type %s is not supported", t)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := make(map[string]*Field)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+
+		esTag, hasESTag := sf.Tag.Lookup("es")
+		if esTag == "-" {
+			continue
+		}
+
+		name, skip := jsonFieldName(sf)
+		if skip {
+			continue
+		}
+
+		// Embedded structs without a json name are flattened, as encoding/json does
+		if sf.Anonymous && sf.Tag.Get("json") == "" && derefType(sf.Type).Kind() == reflect.Struct && !hasESTag {
+			embedded, err := structProperties(derefType(sf.Type), visiting)
+			if err != nil {
+				return nil, err
+			}
+			for embeddedName, field := range embedded {
+				if _, exists := properties[embeddedName]; !exists {
+					properties[embeddedName] = field
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		field, err := structField(name, sf.Type, esTag, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", t.Name(), sf.Name, err)
+		}
+		if field != nil {
+			properties[name] = field
+		}
+	}
+
+	return properties, nil
+}
+
+// structField converts a single struct field to a mapping field
+func structField(name string, t reflect.Type, esTag string, visiting map[reflect.Type]bool) (*Field, error) {
+	field := &Field{
+		Name:       name,
+		Properties: make(map[string]*Field),
+		Fields:     make(map[string]*Field),
+	}
+
+	// Parse the `es` tag: type first, then multi-fields as "name" or "name:type"
+	var parts []string
+	if esTag != "" {
+		parts = strings.Split(esTag, ",")
+		field.Type = FieldType(strings.TrimSpace(parts[0]))
+		if !knownFieldTypes[field.Type] {
+			return nil, fmt.Errorf("unknown Elasticsearch type %q in es tag", field.Type)
+		}
+	}
+	for _, part := range parts[min(1, len(parts)):] {
+		multiName, multiType, found := strings.Cut(strings.TrimSpace(part), ":")
+		if !found {
+			multiType = multiName
+		}
+		if multiName == "" {
+			continue
+		}
+		if !knownFieldTypes[FieldType(multiType)] {
+			return nil, fmt.Errorf("unknown Elasticsearch type %q of multi-field %s in es tag", multiType, multiName)
+		}
+		field.Fields[multiName] = &Field{
+			Name:       multiName,
+			Type:       FieldType(multiType),
+			Properties: make(map[string]*Field),
+			Fields:     make(map[string]*Field),
+		}
+	}
+
+	// Slices and arrays map to their element type (ES fields hold arrays natively), except
+	// []byte which encoding/json writes as a Base64 string
+	elem := derefType(t)
+	for (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && !isByteSlice(elem) {
+		elem = derefType(elem.Elem())
+	}
+
+	if field.Type == "" {
+		field.Type = goTypeToFieldType(elem)
+		if field.Type == "" {
+			return nil, fmt.Errorf("cannot derive Elasticsearch type from %s, add an es tag", t)
+		}
+	}
+
+	// Struct properties for object and nested fields
+	if (field.Type == FieldTypeObject || field.Type == FieldTypeNested) && elem.Kind() == reflect.Struct && elem != timeType {
+		properties, err := structProperties(elem, visiting)
+		if err != nil {
+			return nil, err
+		}
+		field.Properties = properties
+	}
+
+	return field, nil
+}
+
+// goTypeToFieldType returns the default ES type for a Go type
+func goTypeToFieldType(t reflect.Type) FieldType {
+	if t == timeType {
+		return FieldTypeDate
+	}
+	if isByteSlice(t) {
+		return FieldTypeBinary
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return FieldTypeKeyword
+	case reflect.Bool:
+		return FieldTypeBoolean
+	case reflect.Uint, reflect.Uint64:
+		return FieldTypeUnsignedLong
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return FieldTypeLong
+	case reflect.Int32, reflect.Uint16:
+		return FieldTypeInteger
+	case reflect.Int16, reflect.Uint8:
+		return FieldTypeShort
+	case reflect.Int8:
+		return FieldTypeByte
+	case reflect.Float64:
+		return FieldTypeDouble
+	case reflect.Float32:
+		return FieldTypeFloat
+	case reflect.Struct, reflect.Map:
+		return FieldTypeObject
+	default:
+		return ""
+	}
+}
+
+// isByteSlice reports whether a type is a byte slice
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether it is skipped
+func jsonFieldName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, false
+}
+
+// derefType strips pointer types
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
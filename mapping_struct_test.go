@@ -0,0 +1,132 @@
+package graphql
+
+import (
+	"testing"
+	"time"
+)
+
+type structMappingTask struct {
+	Status string `json:"status"`
+}
+
+type structMappingProcess struct {
+	Name  string              `json:"name" es:"text,keyword"`
+	Tasks []structMappingTask `json:"tasks" es:"nested"`
+}
+
+type structMappingBase struct {
+	ID string `json:"id" es:"keyword"`
+}
+
+type structMappingLead struct {
+	structMappingBase
+	Title     string                 `json:"title" es:"text,keyword,sortable:keyword"`
+	Amount    float64                `json:"amount"`
+	Count     int                    `json:"count"`
+	Version   uint64                 `json:"version"`
+	Avatar    []byte                 `json:"avatar"`
+	Checksum  [4]byte                `json:"checksum"`
+	Active    *bool                  `json:"active,omitempty"`
+	CreatedAt time.Time              `json:"createdAt" es:"date"`
+	UpdatedAt *time.Time             `json:"updatedAt"`
+	Tags      []string               `json:"tags"`
+	Customer  struct{ Name string }  `json:"customer"`
+	Processes []structMappingProcess `json:"processes" es:"nested"`
+	Metadata  map[string]any         `json:"metadata"`
+	Internal  string                 `json:"internal" es:"-"`
+	Ignored   string                 `json:"-"`
+	private   string
+}
+
+func TestMappingFromStruct(t *testing.T) {
+	mapping, err := MappingFromStruct[structMappingLead]("leads")
+	if err != nil {
+		t.Fatalf("Failed to build mapping: %v", err)
+	}
+
+	expected := map[string]FieldType{
+		"id":                     FieldTypeKeyword,
+		"title":                  FieldTypeText,
+		"title.keyword":          FieldTypeKeyword,
+		"title.sortable":         FieldTypeKeyword,
+		"amount":                 FieldTypeDouble,
+		"count":                  FieldTypeLong,
+		"version":                FieldTypeUnsignedLong,
+		"avatar":                 FieldTypeBinary,
+		"checksum":               FieldTypeShort,
+		"active":                 FieldTypeBoolean,
+		"createdAt":              FieldTypeDate,
+		"updatedAt":              FieldTypeDate,
+		"tags":                   FieldTypeKeyword,
+		"customer":               FieldTypeObject,
+		"customer.Name":          FieldTypeKeyword,
+		"processes":              FieldTypeNested,
+		"processes.name":         FieldTypeText,
+		"processes.tasks":        FieldTypeNested,
+		"processes.tasks.status": FieldTypeKeyword,
+		"metadata":               FieldTypeObject,
+	}
+	for path, fieldType := range expected {
+		field := mapping.GetField(path)
+		if field == nil {
+			t.Errorf("Expected field %s", path)
+			continue
+		}
+		if field.Type != fieldType {
+			t.Errorf("Expected %s to be %s, got %s", path, fieldType, field.Type)
+		}
+	}
+
+	for _, name := range []string{"internal", "Ignored", "-", "private", "structMappingBase"} {
+		if mapping.GetField(name) != nil {
+			t.Errorf("Field %s should be excluded", name)
+		}
+	}
+
+	// The exported mapping JSON parses back to the same mapping
+	data, err := mapping.MappingJSON()
+	if err != nil {
+		t.Fatalf("Failed to emit mapping JSON: %v", err)
+	}
+	parsed, err := ParseMapping("leads", data)
+	if err != nil {
+		t.Fatalf("Failed to parse emitted mapping: %v", err)
+	}
+	if drift := DiffMappings(mapping, parsed); drift.HasDrift() {
+		t.Errorf("Exported mapping should round-trip, got drift: %s", drift)
+	}
+}
+
+func TestMappingFromStructErrors(t *testing.T) {
+	type recursive struct {
+		Children []*recursive `json:"children"`
+	}
+	if _, err := MappingFromStruct[recursive]("tree"); err == nil {
+		t.Error("Expected error for recursive type")
+	}
+
+	type unsupported struct {
+		Callback func() `json:"callback"`
+	}
+	if _, err := MappingFromStruct[unsupported]("funcs"); err == nil {
+		t.Error("Expected error for field without derivable type")
+	}
+
+	type unknownType struct {
+		Name string `json:"name" es:"keywrod"`
+	}
+	if _, err := MappingFromStruct[unknownType]("typos"); err == nil {
+		t.Error("Expected error for an unknown es tag type")
+	}
+
+	type unknownMultiFieldType struct {
+		Name string `json:"name" es:"text,raw:keywrod"`
+	}
+	if _, err := MappingFromStruct[unknownMultiFieldType]("typos"); err == nil {
+		t.Error("Expected error for an unknown multi-field type")
+	}
+
+	if _, err := MappingFromStruct[string]("strings"); err == nil {
+		t.Error("Expected error for non-struct type")
+	}
+}
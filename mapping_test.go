@@ -0,0 +1,100 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeMappings(t *testing.T) {
+	leads, err := ParseMapping("leads-2024", []byte(`{
+		"properties": {
+			"id": {"type": "keyword"},
+			"customer": {
+				"properties": {
+					"name": {"type": "text"}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	archived, err := ParseMapping("leads-2023", []byte(`{
+		"properties": {
+			"id": {"type": "long"},
+			"archivedAt": {"type": "date"},
+			"customer": {
+				"properties": {
+					"email": {"type": "keyword"}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse mapping: %v", err)
+	}
+
+	merged := MergeMappings("leads-*", leads, archived)
+
+	if merged.IndexName != "leads-*" {
+		t.Errorf("Expected index name 'leads-*', got %s", merged.IndexName)
+	}
+
+	// First mapping wins on type conflicts
+	if field := merged.GetField("id"); field == nil || field.Type != FieldTypeKeyword {
+		t.Errorf("Expected id to keep keyword type, got %+v", field)
+	}
+
+	// Fields unique to one mapping are kept
+	if merged.GetField("archivedAt") == nil {
+		t.Error("Expected archivedAt to be merged")
+	}
+
+	// Object properties are merged recursively
+	if merged.GetField("customer.name") == nil || merged.GetField("customer.email") == nil {
+		t.Error("Expected customer properties from both mappings")
+	}
+
+	// Inputs are not modified
+	if leads.GetField("customer.email") != nil {
+		t.Error("MergeMappings should not modify its input mappings")
+	}
+}
+
+func TestPrecompiledQueryMultipleIndices(t *testing.T) {
+	leads := IndexMapping{
+		IndexName: "leads-2024",
+		Properties: map[string]*Field{
+			"id":       {Name: "id", Type: FieldTypeKeyword},
+			"leadType": {Name: "leadType", Type: FieldTypeKeyword},
+		},
+	}
+	archived := IndexMapping{
+		IndexName: "leads-2023",
+		Properties: map[string]*Field{
+			"id":         {Name: "id", Type: FieldTypeKeyword},
+			"archivedAt": {Name: "archivedAt", Type: FieldTypeDate},
+		},
+	}
+
+	config := NewConfig(
+		WithPrecompiledQuery("allLeads", &PrecompiledQueryConfig{
+			Indices:   []string{"leads-*"},
+			Mappings:  []IndexMapping{leads, archived},
+			QueryJSON: `{"size": 10}`,
+		}),
+	)
+
+	sdl, err := GenerateSchemaSDL(config)
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	// Document type is named after the index pattern and contains the merged fields
+	for _, expected := range []string{"type LeadsDocument", "leadType: String", "): DateTime", "_index: String"} {
+		if !strings.Contains(sdl, expected) {
+			t.Errorf("SDL should contain %q, got:\n%s", expected, sdl)
+		}
+	}
+}
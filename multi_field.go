@@ -0,0 +1,329 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+// multiFieldAggregationSize is the number of buckets returned for multi-field aggregations
+const multiFieldAggregationSize = 10
+
+// lookupMultiField returns a multi-field (e.g., "name.keyword") and the path of the field it belongs to
+func lookupMultiField(path string, mapping *IndexMapping) (*Field, string, error) {
+	i := strings.LastIndex(path, ".")
+	if i <= 0 {
+		return nil, "", fmt.Errorf("multi-field %q must be a sub-field path such as \"name.keyword\"", path)
+	}
+
+	parentPath, name := path[:i], path[i+1:]
+	parent := mapping.GetField(parentPath)
+	if parent == nil {
+		return nil, "", fmt.Errorf("multi-field %q: field %q not found", path, parentPath)
+	}
+	multiField, ok := parent.Fields[name]
+	if !ok {
+		return nil, "", fmt.Errorf("multi-field %q not found in the mapping of %q", path, parentPath)
+	}
+	return multiField, parentPath, nil
+}
+
+// isTextFieldType reports whether a field type is analysed text
+func isTextFieldType(fieldType FieldType) bool {
+	switch fieldType {
+	case FieldTypeText, FieldTypeMatchOnlyText, FieldTypeSearchAsYouType:
+		return true
+	}
+	return false
+}
+
+// keywordMultiField returns the name of a field's keyword multi-field, preferring "keyword"
+func keywordMultiField(field *Field) (string, bool) {
+	if sub, ok := field.Fields["keyword"]; ok && sub.Type == FieldTypeKeyword {
+		return "keyword", true
+	}
+
+	var names []string
+	for name, sub := range field.Fields {
+		if sub.Type == FieldTypeKeyword {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return names[0], true
+}
+
+// exactFieldPath returns the field to use for exact matches on a path
+// Text fields are routed to their keyword multi-field, other fields are used as-is
+func exactFieldPath(path string, mapping *IndexMapping) string {
+	field := mapping.GetField(path)
+	if field == nil || !isTextFieldType(field.Type) {
+		return path
+	}
+	if name, ok := keywordMultiField(field); ok {
+		return path + "." + name
+	}
+	return path
+}
+
+// applyQueryKeywordRouting routes term and terms queries on text fields to their keyword multi-field
+func applyQueryKeywordRouting(query *ESQueryInput, mapping *IndexMapping) {
+	if query == nil {
+		return
+	}
+
+	if query.Term != nil {
+		query.Term.Field = exactFieldPath(query.Term.Field, mapping)
+	}
+	if query.Terms != nil {
+		query.Terms.Field = exactFieldPath(query.Terms.Field, mapping)
+	}
+
+	if query.Bool != nil {
+		for _, clauses := range [][]*ESQueryInput{query.Bool.Must, query.Bool.Should, query.Bool.Filter, query.Bool.MustNot} {
+			for _, clause := range clauses {
+				applyQueryKeywordRouting(clause, mapping)
+			}
+		}
+	}
+
+	if query.Nested != nil {
+		applyQueryKeywordRouting(query.Nested.Query, mapping)
+	}
+}
+
+// applyAggKeywordRouting routes terms aggregations on text fields (including sub-aggregations
+// and filter queries) to their keyword multi-field
+func applyAggKeywordRouting(agg *ESAggInput, mapping *IndexMapping) {
+	if agg == nil {
+		return
+	}
+
+	if agg.Terms != nil {
+		agg.Terms.Field = exactFieldPath(agg.Terms.Field, mapping)
+	}
+
+	if agg.Filter != nil {
+		applyQueryKeywordRouting(agg.Filter.Query, mapping)
+	}
+
+	for _, subAgg := range agg.Aggs {
+		applyAggKeywordRouting(subAgg, mapping)
+	}
+}
+
+// isSortableMultiField reports whether a multi-field can be sorted and aggregated on
+func isSortableMultiField(field *Field) bool {
+	return !isTextFieldType(field.Type) && field.Type != FieldTypeWildcard
+}
+
+// multiFieldSortOptions returns the sort options of the sortable multi-fields ("name.keyword-asc", ...)
+func multiFieldSortOptions(paths []string, mapping *IndexMapping) []string {
+	var options []string
+	for _, path := range paths {
+		field, _, err := lookupMultiField(path, mapping)
+		if err != nil || !isSortableMultiField(field) {
+			continue
+		}
+		options = append(options, path+"-asc", path+"-desc")
+	}
+	return options
+}
+
+// convertMultiField converts a multi-field to a document field resolving the value of the
+// field it belongs to, since multi-fields index the same source value
+func (sg *SchemaGenerator) convertMultiField(path string, mapping *IndexMapping) (*graphql.Field, error) {
+	field, parentPath, err := lookupMultiField(path, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlType, err := sg.esTypeToGraphQLType(field, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Values below list objects (e.g., nested fields) are collected from every object
+	if crossesList(parentPath, mapping) {
+		gqlType = graphql.NewList(gqlType)
+	}
+
+	return &graphql.Field{
+		Type: gqlType,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return selectJSONPath(p.Source, parentPath), nil
+		},
+	}, nil
+}
+
+// crossesList reports whether any object on a path is a list of objects
+func crossesList(path string, mapping *IndexMapping) bool {
+	parts := splitPath(path)
+	for i := 1; i < len(parts); i++ {
+		if field := mapping.GetField(joinPath(parts[:i])); field != nil && field.isList() {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedPathOf returns the path of the innermost nested object containing a field, if any
+func nestedPathOf(path string, mapping *IndexMapping) string {
+	parts := splitPath(path)
+	nestedPath := ""
+	for i := 1; i < len(parts); i++ {
+		prefix := joinPath(parts[:i])
+		if field := mapping.GetField(prefix); field != nil && field.Type == FieldTypeNested {
+			nestedPath = prefix
+		}
+	}
+	return nestedPath
+}
+
+// multiFieldFeature filters, aggregates and sorts on the multi-fields exposed by QueryConfig.MultiFields
+// Filters use the multi-field path as parameter name, sort values are "<path>-asc" or "<path>-desc"
+type multiFieldFeature struct {
+	fields       map[string]*Field // Multi-field by path
+	nestedPaths  map[string]string // Nested object path by multi-field path
+	aggregations bool
+}
+
+// newMultiFieldFeature creates the feature for the configured multi-fields
+func newMultiFieldFeature(paths []string, mapping *IndexMapping, aggregations bool) (*multiFieldFeature, error) {
+	mff := &multiFieldFeature{
+		fields:       make(map[string]*Field),
+		nestedPaths:  make(map[string]string),
+		aggregations: aggregations,
+	}
+	for _, path := range paths {
+		field, _, err := lookupMultiField(path, mapping)
+		if err != nil {
+			return nil, err
+		}
+		mff.fields[path] = field
+		mff.nestedPaths[path] = nestedPathOf(path, mapping)
+	}
+	return mff, nil
+}
+
+// Process implements reveald.Feature
+func (mff *multiFieldFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	mff.build(builder)
+
+	result, err := next(builder)
+	if err != nil {
+		return nil, err
+	}
+
+	return mff.handle(result), nil
+}
+
+func (mff *multiFieldFeature) build(builder *reveald.QueryBuilder) {
+	request := builder.Request()
+
+	for path, field := range mff.fields {
+		nestedPath := mff.nestedPaths[path]
+
+		if request.Has(path) {
+			if p, err := request.Get(path); err == nil {
+				mff.filter(builder, path, nestedPath, p.Values())
+			}
+		}
+
+		if mff.aggregations && isSortableMultiField(field) {
+			builder.Aggregation(path, termsAggregation(path, nestedPath))
+		}
+	}
+
+	if !request.Has("sort") {
+		return
+	}
+	p, err := request.Get("sort")
+	if err != nil {
+		return
+	}
+	path, order, ok := parseSortValue(p.Value())
+	if _, exists := mff.fields[path]; ok && exists {
+		builder.Selection().Update(reveald.WithSort(path, order))
+	}
+}
+
+// filter matches documents with any of the values
+func (mff *multiFieldFeature) filter(builder *reveald.QueryBuilder, path, nestedPath string, values []string) {
+	fieldValues := make([]types.FieldValue, len(values))
+	for i, v := range values {
+		fieldValues[i] = v
+	}
+
+	query := types.Query{
+		Terms: &types.TermsQuery{
+			TermsQuery: map[string]types.TermsQueryField{path: fieldValues},
+		},
+	}
+	if nestedPath != "" {
+		query = types.Query{Nested: &types.NestedQuery{Path: nestedPath, Query: query}}
+	}
+
+	builder.With(query)
+}
+
+// termsAggregation aggregates on a field, inside a nested aggregation for nested fields
+func termsAggregation(path, nestedPath string) types.Aggregations {
+	field := path
+	size := multiFieldAggregationSize
+	terms := types.Aggregations{
+		Terms: &types.TermsAggregation{Field: &field, Size: &size},
+	}
+	if nestedPath == "" {
+		return terms
+	}
+
+	return types.Aggregations{
+		Nested:       &types.NestedAggregation{Path: &nestedPath},
+		Aggregations: map[string]types.Aggregations{path: terms},
+	}
+}
+
+func (mff *multiFieldFeature) handle(result *reveald.Result) *reveald.Result {
+	if !mff.aggregations {
+		return result
+	}
+
+	for path := range mff.fields {
+		agg, ok := result.RawAggregations()[path]
+		if !ok {
+			continue
+		}
+		if nested, ok := agg.(*types.NestedAggregate); ok {
+			agg = nested.Aggregations[path]
+		}
+		parsed, err := parseAggregations(map[string]types.Aggregate{path: agg})
+		if err != nil {
+			continue
+		}
+		if buckets, ok := parsed[path]; ok {
+			result.Aggregations[path] = buckets
+		}
+	}
+
+	return result
+}
+
+// parseSortValue splits a "<field>-asc" or "<field>-desc" sort value
+func parseSortValue(value string) (string, sortorder.SortOrder, bool) {
+	switch field, direction := splitSortOption(value); direction {
+	case "asc":
+		return field, sortorder.Asc, true
+	case "desc":
+		return field, sortorder.Desc, true
+	}
+	return "", sortorder.SortOrder{}, false
+}
@@ -0,0 +1,156 @@
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+func TestMultiFields(t *testing.T) {
+	mapping := IndexMapping{
+		IndexName: "articles",
+		Properties: map[string]*Field{
+			"title": {Name: "title", Type: FieldTypeText, Fields: map[string]*Field{
+				"keyword":  {Name: "keyword", Type: FieldTypeKeyword},
+				"sortable": {Name: "sortable", Type: FieldTypeKeyword},
+				"english":  {Name: "english", Type: FieldTypeText},
+			}},
+			"body": {Name: "body", Type: FieldTypeText},
+			"comments": {Name: "comments", Type: FieldTypeNested, Properties: map[string]*Field{
+				"author": {Name: "author", Type: FieldTypeText, Fields: map[string]*Field{
+					"raw": {Name: "raw", Type: FieldTypeKeyword},
+				}},
+			}},
+		},
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("articles", &QueryConfig{
+			Mapping:            mapping,
+			MultiFields:        []string{"title.sortable", "title.english", "comments.author.raw"},
+			EnableAggregations: true,
+			EnableSorting:      true,
+		})))
+		assertContains(t, sdl,
+			"title_sortable: String",
+			"title_english: String",
+			"comments_author_raw: [String]",
+			"title_sortable: [String]",
+			"title_sortable_asc",
+			"comments_author_raw_desc",
+			"title_sortable: [Bucket]",
+			"comments_author_raw: [Bucket]",
+		)
+
+		// Text multi-fields are neither filters, sort options nor aggregations
+		assertNotContains(t, sdl, "title_english: [String]", "title_english_asc", "title_english: [Bucket]")
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		config := NewConfig(
+			WithQuery("articles", &QueryConfig{
+				Mapping:     mapping,
+				MultiFields: []string{"body.keyword"},
+			}),
+		)
+
+		if _, err := GenerateSchemaSDL(config); err == nil || !strings.Contains(err.Error(), "body.keyword") {
+			t.Errorf("Expected error for unknown multi-field, got %v", err)
+		}
+	})
+
+	t.Run("resolver", func(t *testing.T) {
+		sg := NewSchemaGenerator(NewConfig(), nil)
+
+		field, err := sg.convertMultiField("title.keyword", &mapping)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		got, err := field.Resolve(graphql.ResolveParams{Source: map[string]any{"title": "Hello"}})
+		if err != nil || got != "Hello" {
+			t.Errorf("Expected multi-field to resolve the title, got %v (%v)", got, err)
+		}
+	})
+
+	t.Run("exact field path", func(t *testing.T) {
+
+		tests := map[string]string{
+			"title":           "title.keyword",
+			"title.keyword":   "title.keyword",
+			"body":            "body",
+			"comments.author": "comments.author.raw",
+			"unknown":         "unknown",
+		}
+		for path, expected := range tests {
+			if got := exactFieldPath(path, &mapping); got != expected {
+				t.Errorf("exactFieldPath(%q) = %q, want %q", path, got, expected)
+			}
+		}
+	})
+
+	t.Run("keyword routing", func(t *testing.T) {
+		query := &ESQueryInput{
+			Bool: &ESBoolQueryInput{
+				Filter: []*ESQueryInput{
+					{Term: &ESTermQueryInput{Field: "title", Value: "Hello"}},
+					{Nested: &ESNestedQueryInput{
+						Path:  "comments",
+						Query: &ESQueryInput{Terms: &ESTermsQueryInput{Field: "comments.author", Values: []string{"Ann"}}},
+					}},
+				},
+				Must: []*ESQueryInput{
+					{Match: &ESMatchQueryInput{Field: "title", Query: "hello"}},
+				},
+			},
+		}
+		agg := &ESAggInput{Name: "titles", Terms: &ESTermsAggInput{Field: "title"}}
+
+		applyQueryKeywordRouting(query, &mapping)
+		applyAggKeywordRouting(agg, &mapping)
+
+		if got := query.Bool.Filter[0].Term.Field; got != "title.keyword" {
+			t.Errorf("Expected term on title.keyword, got %s", got)
+		}
+		if got := query.Bool.Filter[1].Nested.Query.Terms.Field; got != "comments.author.raw" {
+			t.Errorf("Expected terms on comments.author.raw, got %s", got)
+		}
+		if got := query.Bool.Must[0].Match.Field; got != "title" {
+			t.Errorf("Match queries should keep the text field, got %s", got)
+		}
+		if got := agg.Terms.Field; got != "title.keyword" {
+			t.Errorf("Expected terms aggregation on title.keyword, got %s", got)
+		}
+	})
+
+	t.Run("feature build", func(t *testing.T) {
+		feature, err := newMultiFieldFeature([]string{"title.sortable", "comments.author.raw"}, &mapping, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		request := reveald.NewRequest(
+			reveald.NewParameter("title.sortable", "a", "b"),
+			reveald.NewParameter("comments.author.raw", "Ann"),
+			reveald.NewParameter("sort", "title.sortable-desc"),
+		)
+		builder := reveald.NewQueryBuilder(request, "articles")
+		feature.build(builder)
+
+		data, err := json.Marshal(builder.BuildRequest())
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+
+		assertContains(t, string(data),
+			`"terms":{"title.sortable":["a","b"]}`,
+			`"nested":{"path":"comments","query":{"terms":{"comments.author.raw":["Ann"]}}}`,
+			`"title.sortable":{"terms":{"field":"title.sortable","size":10}}`,
+			`"nested":{"path":"comments"}`,
+			`{"title.sortable":{"order":"desc"}}`,
+		)
+	})
+}
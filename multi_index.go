@@ -0,0 +1,134 @@
+package graphql
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// generateMultiIndexHitsType creates the hits type for a query spanning several indices.
+// Each index gets its own document type, and the returned interface holds the fields
+// shared by all of them. The concrete type is resolved from the hit's _index.
+func (sg *SchemaGenerator) generateMultiIndexHitsType(queryName string, queryConfig *QueryConfig, baseName string) (*graphql.Interface, error) {
+	interfaceName := queryConfig.HitsTypeName
+	if interfaceName == "" {
+		interfaceName = fmt.Sprintf("%sDocument", baseName)
+	}
+
+	mappings := queryConfig.indexMappings()
+	docTypes := make([]*graphql.Object, 0, len(mappings))
+	indexNames := make([]string, 0, len(mappings))
+
+	for i := range mappings {
+		mapping := mappings[i]
+		typeName := documentTypeName(&mapping)
+		if typeName == interfaceName {
+			return nil, fmt.Errorf("document type %s for index %s collides with the hits interface name", typeName, mapping.IndexName)
+		}
+
+		docType, err := sg.generateNamedDocumentType(typeName, queryName, queryConfig, &mapping)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate document type for index %s: %w", mapping.IndexName, err)
+		}
+
+		docTypes = append(docTypes, docType)
+		indexNames = append(indexNames, mapping.IndexName)
+	}
+
+	hitsInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name:        interfaceName,
+		Description: fmt.Sprintf("A document from one of the indices: %s", strings.Join(indexNames, ", ")),
+		Fields:      sharedDocumentFields(docTypes),
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			doc, _ := p.Value.(map[string]any)
+			index, _ := doc[indexFieldName].(string)
+			// Hits of unknown indices fail to resolve instead of taking another index's type
+			if i := matchIndex(indexNames, index); i >= 0 {
+				return docTypes[i]
+			}
+			return nil
+		},
+	})
+
+	// Register the interface on each document type and make sure the
+	// concrete types are part of the schema even if no field returns them
+	for _, docType := range docTypes {
+		sg.docInterfaces[docType.Name()] = append(sg.docInterfaces[docType.Name()], hitsInterface)
+		sg.extraTypes = append(sg.extraTypes, docType)
+	}
+
+	return hitsInterface, nil
+}
+
+// sharedDocumentFields returns the fields present with the same type on all document types
+func sharedDocumentFields(docTypes []*graphql.Object) graphql.Fields {
+	fields := graphql.Fields{}
+	if len(docTypes) == 0 {
+		return fields
+	}
+
+	for name, def := range docTypes[0].Fields() {
+		shared := true
+		for _, other := range docTypes[1:] {
+			otherDef, ok := other.Fields()[name]
+			if !ok || exportType(otherDef.Type) != exportType(def.Type) {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			args := graphql.FieldConfigArgument{}
+			for _, arg := range def.Args {
+				args[arg.Name()] = &graphql.ArgumentConfig{
+					Type:         arg.Type,
+					DefaultValue: arg.DefaultValue,
+					Description:  arg.Description(),
+				}
+			}
+			fields[name] = &graphql.Field{
+				Type:              def.Type,
+				Args:              args,
+				Description:       def.Description,
+				DeprecationReason: def.DeprecationReason,
+			}
+		}
+	}
+
+	return fields
+}
+
+// matchIndex returns the position of the configured index name that a hit's _index belongs to.
+// Configured names may be concrete indices, aliases or comma-separated wildcard patterns;
+// aliases are matched by prefix (e.g. "leads" matches "leads-000001").
+// Returns -1 when nothing matches.
+func matchIndex(indexNames []string, index string) int {
+	if index == "" {
+		return -1
+	}
+
+	for i, name := range indexNames {
+		if name == index {
+			return i
+		}
+	}
+
+	for i, name := range indexNames {
+		for _, pattern := range strings.Split(name, ",") {
+			if matched, err := path.Match(strings.TrimSpace(pattern), index); err == nil && matched {
+				return i
+			}
+		}
+	}
+
+	// Prefer the longest matching prefix so "leads-archive" wins over "leads"
+	best, bestLen := -1, 0
+	for i, name := range indexNames {
+		if strings.HasPrefix(index, name+"-") && len(name) > bestLen {
+			best, bestLen = i, len(name)
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,135 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+func TestMultiIndexQuerySchema(t *testing.T) {
+	leads := IndexMapping{
+		IndexName: "leads",
+		Properties: map[string]*Field{
+			"id":       {Name: "id", Type: FieldTypeKeyword},
+			"title":    {Name: "title", Type: FieldTypeText},
+			"leadType": {Name: "leadType", Type: FieldTypeKeyword},
+		},
+	}
+	orders := IndexMapping{
+		IndexName: "orders",
+		Properties: map[string]*Field{
+			"id":     {Name: "id", Type: FieldTypeKeyword},
+			"title":  {Name: "title", Type: FieldTypeText},
+			"status": {Name: "status", Type: FieldTypeKeyword},
+		},
+	}
+
+	config := NewConfig(
+		WithQuery("search", &QueryConfig{
+			Mapping:  leads,
+			Mappings: []IndexMapping{orders},
+		}),
+	)
+
+	sdl, err := GenerateSchemaSDL(config)
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	expected := []string{
+		"interface SearchDocument",
+		"type LeadsDocument implements SearchDocument",
+		"type OrdersDocument implements SearchDocument",
+		"hits: [SearchDocument]",
+		// Filter arguments are generated from the merged mappings
+		"leadType: [String]",
+		"status: [String]",
+	}
+	for _, e := range expected {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+
+	// The interface only holds fields shared by all indices
+	start := strings.Index(sdl, "interface SearchDocument")
+	end := strings.Index(sdl[start:], "}")
+	iface := sdl[start : start+end]
+	if !strings.Contains(iface, "title: String") || !strings.Contains(iface, "_index: String") {
+		t.Errorf("Interface should contain shared fields, got:\n%s", iface)
+	}
+	if strings.Contains(iface, "leadType") || strings.Contains(iface, "status") {
+		t.Errorf("Interface should not contain index-specific fields, got:\n%s", iface)
+	}
+}
+
+func TestMatchIndex(t *testing.T) {
+	indexNames := []string{"leads", "leads-archive", "events-*"}
+
+	tests := []struct {
+		index    string
+		expected int
+	}{
+		{"leads", 0},
+		{"leads-000001", 0},
+		{"leads-archive-2023", 1},
+		{"events-2024.01", 2},
+		{"unknown", -1},
+		{"", -1},
+	}
+
+	for _, tt := range tests {
+		if got := matchIndex(indexNames, tt.index); got != tt.expected {
+			t.Errorf("matchIndex(%q) = %d, expected %d", tt.index, got, tt.expected)
+		}
+	}
+}
+
+func TestMultiIndexHitTypes(t *testing.T) {
+	config := func() *Config {
+		return NewConfig(WithQuery("search", &QueryConfig{
+			Mapping: IndexMapping{IndexName: "leads", Properties: map[string]*Field{
+				"title": {Name: "title", Type: FieldTypeText},
+			}},
+			Mappings: []IndexMapping{{IndexName: "orders", Properties: map[string]*Field{
+				"title": {Name: "title", Type: FieldTypeText},
+			}}},
+			Features: []reveald.Feature{&mockWrapperFeature{}},
+		}))
+	}
+
+	// Hits are read from the raw response, including the first hit without a source
+	es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 3, "relation": "eq"}, "hits": [
+		{"_index": "leads", "_id": "l0"},
+		{"_index": "orders-000001", "_id": "o1", "_source": {"title": "Order"}},
+		{"_index": "leads", "_id": "l1", "_source": {"title": "Lead"}}
+	]}`))
+	api, err := New(es.backend(t), config())
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ search { hits { __typename _index title } } }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+	hits := result.Data.(map[string]any)["search"].(map[string]any)["hits"].([]any)
+	if len(hits) != 3 || hits[0].(map[string]any)["_index"] != "leads" || hits[1].(map[string]any)["__typename"] != "OrdersDocument" || hits[2].(map[string]any)["__typename"] != "LeadsDocument" {
+		t.Errorf("Expected hits typed by their index, got %v", hits)
+	}
+
+	// Hits of other indices are errors
+	es = newFakeES(t, staticResponse(`"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [
+		{"_index": "events", "_id": "e1", "_source": {"title": "Event"}}
+	]}`))
+	api, err = New(es.backend(t), config())
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+	result = graphql.Do(graphql.Params{Schema: api.GetSchema(), RequestString: `{ search { hits { __typename title } } }`})
+	if len(result.Errors) == 0 {
+		t.Errorf("Expected an error for a hit of an unknown index, got %v", result.Data)
+	}
+}
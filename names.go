@@ -0,0 +1,224 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// nameRegistry translates between Elasticsearch names and GraphQL names
+// GraphQL names must match [_A-Za-z][_0-9A-Za-z]*, so ES field paths such as "@timestamp",
+// "user-agent" or "geo.location" are sanitised when the schema is generated. Names are
+// registered per scope (a GraphQL type for fields, a query for arguments) so the resolvers
+// can translate GraphQL names back to the exact ES path instead of guessing.
+type nameRegistry struct {
+	scopes     map[string]*nameScope
+	collisions []error
+}
+
+// nameScope holds the names registered for one GraphQL type or argument list
+type nameScope struct {
+	toGraphQL map[string]string
+	toES      map[string]string
+}
+
+func newNameRegistry() *nameRegistry {
+	return &nameRegistry{scopes: make(map[string]*nameScope)}
+}
+
+// register records the GraphQL name (sanitised) of an ES name in the scope
+// Two ES names that end up with the same GraphQL name (e.g., "user-agent" and "user_agent")
+// are recorded as a collision and reported by err, registering the same ES name again
+// returns the same name
+func (r *nameRegistry) register(scope, esName, name string) string {
+	s := r.scope(scope)
+	if gqlName, ok := s.toGraphQL[esName]; ok {
+		return gqlName
+	}
+
+	gqlName := sanitizeFieldName(name)
+	if existing, ok := s.toES[gqlName]; ok {
+		if existing == "" {
+			r.collisions = append(r.collisions, fmt.Errorf("GraphQL name %q for %q is reserved in %s", gqlName, esName, scope))
+		} else {
+			r.collisions = append(r.collisions, fmt.Errorf("GraphQL name %q for %q collides with %q in %s", gqlName, esName, existing, scope))
+		}
+		return gqlName
+	}
+
+	s.toGraphQL[esName] = gqlName
+	s.toES[gqlName] = esName
+	return gqlName
+}
+
+// reserve marks a GraphQL name in the scope as used by a generated field or argument
+// (e.g., "limit"), so an ES field sanitising to it is reported as a collision
+// Reserved names are recorded with an empty ES name
+func (r *nameRegistry) reserve(scope, gqlName string) {
+	s := r.scope(scope)
+	if existing, ok := s.toES[gqlName]; ok && existing != "" {
+		r.collisions = append(r.collisions, fmt.Errorf("GraphQL name %q is reserved but used by %q in %s", gqlName, existing, scope))
+		return
+	}
+	s.toES[gqlName] = ""
+}
+
+// scope returns the names of a scope, creating it on first use
+func (r *nameRegistry) scope(name string) *nameScope {
+	s, ok := r.scopes[name]
+	if !ok {
+		s = &nameScope{toGraphQL: make(map[string]string), toES: make(map[string]string)}
+		r.scopes[name] = s
+	}
+	return s
+}
+
+// err returns the name collisions found while generating the schema
+func (r *nameRegistry) err() error {
+	return errors.Join(r.collisions...)
+}
+
+// graphQLName returns the GraphQL name registered for an ES name
+func (r *nameRegistry) graphQLName(scope, esName string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	s, ok := r.scopes[scope]
+	if !ok {
+		return "", false
+	}
+	gqlName, ok := s.toGraphQL[esName]
+	return gqlName, ok
+}
+
+// esName returns the ES name registered for a GraphQL name
+// Reserved names are not ES names and return false
+func (r *nameRegistry) esName(scope, gqlName string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	s, ok := r.scopes[scope]
+	if !ok {
+		return "", false
+	}
+	esName, ok := s.toES[gqlName]
+	return esName, ok && esName != ""
+}
+
+// argumentScope is the name scope of a query's arguments
+func argumentScope(queryName string) string {
+	return "Query." + queryName
+}
+
+// sanitizeFieldName converts an ES field or aggregation name to a valid GraphQL name
+// Dots, hyphens and other invalid characters become underscores and names that
+// do not start with a letter or underscore are prefixed with one, e.g.
+//   - "geo.location" → "geo_location"
+//   - "user-agent" → "user_agent"
+//   - "@timestamp" → "_timestamp"
+//   - "2fa" → "_2fa"
+//
+// Names starting with "__" are reserved for introspection, so the prefix is shortened to "_".
+func sanitizeFieldName(name string) string {
+	var result strings.Builder
+	for _, r := range name {
+		if isLetter(r) || r == '_' || (r >= '0' && r <= '9') {
+			result.WriteRune(r)
+		} else {
+			result.WriteRune('_')
+		}
+	}
+
+	sanitized := result.String()
+	if sanitized == "" {
+		return "_"
+	}
+
+	// Ensure it starts with a letter or underscore
+	if sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "_" + sanitized
+	}
+
+	for strings.HasPrefix(sanitized, "__") {
+		sanitized = sanitized[1:]
+	}
+
+	return sanitized
+}
+
+// graphQLName returns the GraphQL name for an ES name: the field's alias (FieldAliases) if set,
+// otherwise the name converted by the naming strategy
+func (sg *SchemaGenerator) graphQLName(esName string, mappingField *Field) string {
+	if mappingField != nil && mappingField.GraphQLName != "" {
+		return mappingField.GraphQLName
+	}
+	if sg.config.NamingStrategy != nil {
+		return sg.config.NamingStrategy(esName)
+	}
+	return esName
+}
+
+// registerField adds a field to a GraphQL type under its registered name
+// mappingField is the mapping field the GraphQL field is generated from, nil for aggregations
+// Renamed fields get a resolver reading the ES name, since hits keep their _source keys
+func (sg *SchemaGenerator) registerField(fields graphql.Fields, typeName, esName string, mappingField *Field, field *graphql.Field) {
+	gqlName := sg.names.register(typeName, esName, sg.graphQLName(esName, mappingField))
+	if gqlName != esName && field.Resolve == nil {
+		field.Resolve = sourceFieldResolver(esName)
+	}
+	if mappingField != nil {
+		if field.Description == "" {
+			field.Description = mappingField.Description
+		}
+		if field.DeprecationReason == "" {
+			field.DeprecationReason = mappingField.DeprecationReason
+		}
+	}
+	fields[gqlName] = field
+}
+
+// NamingStrategy converts ES field and aggregation names to GraphQL names
+// The result is sanitised afterwards, so strategies do not need to handle invalid characters
+type NamingStrategy func(esName string) string
+
+var (
+	// AsIsNaming keeps ES names, only replacing characters that are invalid in GraphQL (the default)
+	AsIsNaming NamingStrategy = func(esName string) string { return esName }
+
+	// CamelCaseNaming converts snake_case, kebab-case and dotted names to camelCase
+	// (e.g., "created_at" → "createdAt", "vehicle.manufacturer" → "vehicleManufacturer")
+	CamelCaseNaming NamingStrategy = toCamelCase
+)
+
+// toCamelCase joins the words of a name, capitalising all but the first
+func toCamelCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !isLetter(r) && (r < '0' || r > '9')
+	})
+	if len(words) == 0 {
+		return name
+	}
+
+	var result strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			result.WriteString(strings.ToLower(word[:1]) + word[1:])
+			continue
+		}
+		result.WriteString(capitalize(word))
+	}
+	return result.String()
+}
+
+// sourceFieldResolver resolves a field from the source map by its ES name
+func sourceFieldResolver(esName string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		return source[esName], nil
+	}
+}
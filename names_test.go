@@ -0,0 +1,119 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestSanitizeFieldName(t *testing.T) {
+	tests := map[string]string{
+		"name":         "name",
+		"geo.location": "geo_location",
+		"user-agent":   "user_agent",
+		"@timestamp":   "_timestamp",
+		"2fa":          "_2fa",
+		"__meta":       "_meta",
+		"a/b c":        "a_b_c",
+	}
+
+	for name, expected := range tests {
+		if got := sanitizeFieldName(name); got != expected {
+			t.Errorf("sanitizeFieldName(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func namesTestConfig(properties map[string]*Field) *Config {
+	return NewConfig(
+		WithQuery("logs", &QueryConfig{
+			Mapping:          IndexMapping{IndexName: "logs", Properties: properties},
+			EnablePagination: true,
+		}),
+	)
+}
+
+func TestNameRegistrySchema(t *testing.T) {
+	config := namesTestConfig(map[string]*Field{
+		"@timestamp":    {Name: "@timestamp", Type: FieldTypeLong},
+		"user-agent":    {Name: "user-agent", Type: FieldTypeKeyword},
+		"order_line_id": {Name: "order_line_id", Type: FieldTypeKeyword},
+	})
+
+	schema, err := GenerateSchema(config)
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	docType, ok := schema.Type("LogsDocument").(*graphql.Object)
+	if !ok {
+		t.Fatal("Expected LogsDocument type")
+	}
+	fields := docType.Fields()
+	for _, name := range []string{"_timestamp", "user_agent", "order_line_id"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("Expected field %q, got %v", name, fields)
+		}
+	}
+
+	// Renamed fields read the original _source key
+	value, err := fields["user_agent"].Resolve(graphql.ResolveParams{Source: map[string]any{"user-agent": "curl"}})
+	if err != nil || value != "curl" {
+		t.Errorf("Expected user_agent to resolve from user-agent, got %v (%v)", value, err)
+	}
+}
+
+func TestNameRegistryCollisions(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]*Field
+	}{
+		{"sanitised names", map[string]*Field{
+			"user-agent": {Name: "user-agent", Type: FieldTypeKeyword},
+			"user_agent": {Name: "user_agent", Type: FieldTypeKeyword},
+		}},
+		{"reserved argument", map[string]*Field{
+			"limit": {Name: "limit", Type: FieldTypeKeyword},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateSchema(namesTestConfig(tt.properties))
+			if err == nil || !strings.Contains(err.Error(), "invalid GraphQL names") {
+				t.Errorf("Expected name collision error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestArgumentReaderNameRegistry(t *testing.T) {
+	config := namesTestConfig(map[string]*Field{
+		"user-agent":    {Name: "user-agent", Type: FieldTypeKeyword},
+		"order_line_id": {Name: "order_line_id", Type: FieldTypeKeyword},
+	})
+
+	generator := NewSchemaGenerator(config, &ResolverBuilder{})
+	if _, err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	mapping := config.Queries["logs"].GetMapping()
+	reader := NewArgumentReader(&mapping).withNames(generator.names, "logs")
+
+	request, err := reader.Read(graphql.ResolveParams{Args: map[string]any{
+		"user_agent":    []any{"curl"},
+		"order_line_id": []any{"42"},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to read arguments: %v", err)
+	}
+
+	// Underscores that are part of the ES name are kept
+	for _, name := range []string{"user-agent", "order_line_id"} {
+		if !request.Has(name) {
+			t.Errorf("Expected parameter %q", name)
+		}
+	}
+}
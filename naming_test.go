@@ -0,0 +1,230 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+	"github.com/reveald/reveald/v2/featureset"
+)
+
+func TestToCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"created_at":           "createdAt",
+		"vehicle.manufacturer": "vehicleManufacturer",
+		"user-agent":           "userAgent",
+		"@timestamp":           "timestamp",
+		"alreadyCamel":         "alreadyCamel",
+		"Name":                 "name",
+	}
+
+	for name, expected := range tests {
+		if got := toCamelCase(name); got != expected {
+			t.Errorf("toCamelCase(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func namingTestConfig(opts ...ConfigOption) *Config {
+	queryConfig := &QueryConfig{
+		Mapping: IndexMapping{
+			IndexName: "customers",
+			Properties: map[string]*Field{
+				"customer_id": {Name: "customer_id", Type: FieldTypeKeyword},
+				"created_at":  {Name: "created_at", Type: FieldTypeLong},
+				"cust_nm":     {Name: "cust_nm", Type: FieldTypeKeyword},
+				"home_address": {Name: "home_address", Type: FieldTypeObject, Properties: map[string]*Field{
+					"street_name": {Name: "street_name", Type: FieldTypeKeyword},
+				}},
+			},
+		},
+		Features: []reveald.Feature{
+			featureset.NewSortingFeature("sort", featureset.WithSortOption("created_at-desc", "created_at", false)),
+		},
+		FieldAliases:       map[string]string{"cust_nm": "customerName"},
+		EnableAggregations: true,
+		AggregationFields:  []string{"customer_id"},
+		EnableSorting:      true,
+	}
+
+	return NewConfig(append(opts, WithQuery("customers", queryConfig))...)
+}
+
+func TestCamelCaseNamingSchema(t *testing.T) {
+	sdl, err := GenerateSchemaSDL(namingTestConfig(WithNamingStrategy(CamelCaseNaming)))
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	expected := []string{
+		"customerId: String",
+		"createdAt: Long",
+		"customerName: String",
+		"homeAddress: HomeAddressObject",
+		"streetName: String",
+		"createdAt_desc",
+	}
+	for _, e := range expected {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+
+	// Filter arguments and aggregation fields are renamed too
+	queryField := sdl[strings.Index(sdl, "customers("):]
+	queryField = queryField[:strings.Index(queryField, "\n")]
+	for _, e := range []string{"customerId: [String]", "customerName: [String]"} {
+		if !strings.Contains(queryField, e) {
+			t.Errorf("Query arguments should contain %q, got: %s", e, queryField)
+		}
+	}
+	aggType := sdl[strings.Index(sdl, "type CustomersAggregations"):]
+	if !strings.Contains(aggType[:strings.Index(aggType, "}")], "customerId: [Bucket]") {
+		t.Errorf("Aggregations should contain customerId, got:\n%s", aggType)
+	}
+
+	for _, e := range []string{"customer_id", "cust_nm", "created_at_desc"} {
+		if strings.Contains(sdl, e) {
+			t.Errorf("SDL should not contain ES name %q, got:\n%s", e, sdl)
+		}
+	}
+}
+
+func TestFieldAliasesWithoutStrategy(t *testing.T) {
+	sdl, err := GenerateSchemaSDL(namingTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	for _, e := range []string{"customer_id: String", "customerName: String", "created_at_desc"} {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+}
+
+func TestFieldAliasesObjects(t *testing.T) {
+	queryConfig := func(aliases map[string]string) *QueryConfig {
+		return &QueryConfig{
+			Mapping: IndexMapping{
+				IndexName: "orders",
+				Properties: map[string]*Field{
+					"customer": {Name: "customer", Type: FieldTypeObject, Properties: map[string]*Field{
+						"address": {Name: "address", Type: FieldTypeObject, Properties: map[string]*Field{
+							"street": {Name: "street", Type: FieldTypeKeyword},
+						}},
+					}},
+				},
+			},
+			FieldAliases: aliases,
+		}
+	}
+
+	t.Run("nested object types", func(t *testing.T) {
+		// Types of nested objects are named after the aliased parent
+		sdl := generateSDL(t, NewConfig(WithQuery("orders", queryConfig(map[string]string{"customer": "client"}))))
+		assertContains(t, sdl, "client: ClientObject", "address: ClientAddressObject", "type ClientAddressObject")
+		assertNotContains(t, sdl, "CustomerAddressObject")
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		_, err := GenerateSchemaSDL(NewConfig(WithQuery("orders", queryConfig(map[string]string{"customer.name": "clientName"}))))
+		if err == nil || !strings.Contains(err.Error(), "field customer.name not found") {
+			t.Errorf("Expected an error for the unknown alias path, got %v", err)
+		}
+	})
+}
+
+func TestCamelCaseNamingResolvers(t *testing.T) {
+	config := namingTestConfig(WithNamingStrategy(CamelCaseNaming))
+	generator := NewSchemaGenerator(config, &ResolverBuilder{})
+	schema, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	// Document fields read the ES names from the hit
+	source := map[string]any{"cust_nm": "Alice", "home_address": map[string]any{"street_name": "Main"}}
+	docFields := schema.Type("CustomersDocument").(*graphql.Object).Fields()
+	if value, _ := docFields["customerName"].Resolve(graphql.ResolveParams{Source: source}); value != "Alice" {
+		t.Errorf("Expected customerName to resolve from cust_nm, got %v", value)
+	}
+	objFields := schema.Type("HomeAddressObject").(*graphql.Object).Fields()
+	if value, _ := objFields["streetName"].Resolve(graphql.ResolveParams{Source: source["home_address"]}); value != "Main" {
+		t.Errorf("Expected streetName to resolve from street_name, got %v", value)
+	}
+
+	// Arguments map back to the ES names
+	mapping := config.Queries["customers"].GetMapping()
+	reader := NewArgumentReader(&mapping).withNames(generator.names, "customers")
+	request, err := reader.Read(graphql.ResolveParams{Args: map[string]any{
+		"customerId":   []any{"c1"},
+		"customerName": []any{"Alice"},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to read arguments: %v", err)
+	}
+	for _, name := range []string{"customer_id", "cust_nm"} {
+		if !request.Has(name) {
+			t.Errorf("Expected parameter %q", name)
+		}
+	}
+}
+
+func TestFieldAliasesSharedDocumentType(t *testing.T) {
+	aliased := func(aliases map[string]string, hitsTypeName string) *QueryConfig {
+		queryConfig := namingTestConfig().Queries["customers"]
+		queryConfig.FieldAliases = aliases
+		queryConfig.HitsTypeName = hitsTypeName
+		return queryConfig
+	}
+
+	tests := map[string]struct {
+		other   *QueryConfig
+		wantErr bool
+	}{
+		"same aliases":      {other: aliased(map[string]string{"cust_nm": "customerName"}, "")},
+		"different aliases": {other: aliased(map[string]string{"cust_nm": "name"}, ""), wantErr: true},
+		"own type":          {other: aliased(map[string]string{"cust_nm": "name"}, "CustomerSearchHit")},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := GenerateSchemaSDL(namingTestConfig(WithQuery("customerSearch", tt.other)))
+			if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "HitsTypeName")) {
+				t.Errorf("Expected an error about the shared document type, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEntityQueryAliasedKey(t *testing.T) {
+	config := namingTestConfig(WithEnableFederation(), WithNamingStrategy(CamelCaseNaming))
+	config.Queries["customers"].EntityKeyFields = []string{"customerName"}
+	generator := NewSchemaGenerator(config, &ResolverBuilder{})
+	if _, err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	// Representations use the GraphQL names, the entity query the ES names
+	resolver := generator.entityResolver
+	fields := resolver.esFields("CustomersDocument", map[string]any{"customerName": "Alice", "createdAt": 1})
+	if fields["cust_nm"] != "Alice" || fields["created_at"] != 1 {
+		t.Errorf("Expected representation fields by ES name, got %v", fields)
+	}
+	query, err := resolver.buildEntityQuery("CustomersDocument", resolver.typeMappings["CustomersDocument"], fields)
+	if err != nil {
+		t.Fatalf("Failed to build entity query: %v", err)
+	}
+	if len(query.Term) != 1 {
+		t.Fatalf("Expected a term query, got %v", query)
+	}
+	for field := range query.Term {
+		if !strings.HasPrefix(field, "cust_nm") {
+			t.Errorf("Expected a term query on cust_nm, got %s", field)
+		}
+	}
+}
@@ -56,11 +56,17 @@ type PrecompiledQueryConfig struct {
 	Index string
 
 	// Indices are multiple Elasticsearch indices to query
+	// Aliases and wildcard patterns (e.g., "leads-*") are supported
 	Indices []string
 
 	// Mapping is the Elasticsearch index mapping for this query
 	Mapping IndexMapping
 
+	// Mappings are the mappings of the individual indices when Indices (or an
+	// alias/pattern) cover indices whose mappings differ
+	// They are merged with Mapping to build the document type
+	Mappings []IndexMapping
+
 	// QueryBuilder builds the Elasticsearch search request from GraphQL arguments
 	// Mutually exclusive with QueryJSON - specify only one
 	QueryBuilder QueryBuilderFunc
@@ -86,6 +92,10 @@ type PrecompiledQueryConfig struct {
 	// Useful for tenant filtering, permissions, etc.
 	RootQueryBuilder RootQueryBuilder
 
+	// IndexResolver picks the indices to search per request
+	// When not set, GetIndices() is searched
+	IndexResolver IndexResolver
+
 	// EntityKeyFields specifies the fields to use as entity keys for Apollo Federation
 	// Overrides the IndexMapping.EntityKeyFields for this specific query
 	// Each element represents one @key directive with space-separated field names
@@ -105,6 +115,19 @@ type PrecompiledQueryConfig struct {
 	// - Custom scalar types
 	FieldTypeOverrides map[string]graphql.Output
 
+	// ObjectCardinality overrides whether object fields are single objects or lists, keyed by field path
+	// See QueryConfig.ObjectCardinality
+	ObjectCardinality map[string]Cardinality
+
+	// RuntimeFields are fields computed at query time, added to the document type
+	// They are sent as runtime_mappings when selected or referenced by the query (e.g., in a
+	// filter, sort or aggregation of QueryJSON). See QueryConfig.RuntimeFields
+	RuntimeFields map[string]RuntimeField
+
+	// ScriptFields are values computed for each hit, added to the document type and sent as
+	// script_fields when selected. See QueryConfig.ScriptFields
+	ScriptFields map[string]ScriptField
+
 	// HitsTypeName is an optional custom name for the document type returned in the hits field
 	// If not provided, defaults to "{IndexName}Document" (e.g., "ProductsDocument")
 	// Example: "Lead" instead of "TestLeadsDocument"
@@ -122,6 +145,22 @@ func (pc *PrecompiledQueryConfig) GetIndices() []string {
 	return []string{}
 }
 
+// GetMapping returns the mapping used for the document type, merging
+// Mapping with any per-index Mappings
+func (pc *PrecompiledQueryConfig) GetMapping() IndexMapping {
+	if len(pc.Mappings) == 0 {
+		return pc.Mapping.withCardinality(pc.ObjectCardinality)
+	}
+
+	indexName := pc.Mapping.IndexName
+	if indexName == "" {
+		indexName = pc.Mappings[0].IndexName
+	}
+
+	merged := MergeMappings(indexName, append([]IndexMapping{pc.Mapping}, pc.Mappings...)...)
+	return merged.withCardinality(pc.ObjectCardinality)
+}
+
 // Validate checks if the configuration is valid
 func (pc *PrecompiledQueryConfig) Validate() error {
 	// Exactly one of QueryBuilder or QueryJSON must be specified
@@ -139,7 +178,8 @@ func (pc *PrecompiledQueryConfig) Validate() error {
 		return fmt.Errorf("at least one index must be specified")
 	}
 
-	return nil
+	mapping := pc.GetMapping()
+	return validateCardinality(pc.ObjectCardinality, &mapping)
 }
 
 // LoadQuery loads the query from JSON string or builder
@@ -10,7 +10,10 @@ import (
 
 // ArgumentReader converts GraphQL arguments to reveald Parameters
 type ArgumentReader struct {
-	mapping *IndexMapping
+	mapping  *IndexMapping
+	names    *nameRegistry // Argument names registered by the schema generator
+	scope    string
+	collapse bool // Whether the query has the collapse and after arguments (QueryConfig.CollapseFields)
 }
 
 // NewArgumentReader creates a new argument reader
@@ -20,6 +23,19 @@ func NewArgumentReader(mapping *IndexMapping) *ArgumentReader {
 	}
 }
 
+// withNames makes the reader translate argument names with the registry of the generated schema
+func (ar *ArgumentReader) withNames(names *nameRegistry, queryName string) *ArgumentReader {
+	ar.names = names
+	ar.scope = argumentScope(queryName)
+	return ar
+}
+
+// withCollapse makes the reader leave the collapse and after arguments to the resolver
+func (ar *ArgumentReader) withCollapse(collapse bool) *ArgumentReader {
+	ar.collapse = collapse
+	return ar
+}
+
 // Read converts GraphQL resolver params to reveald Request
 func (ar *ArgumentReader) Read(params graphql.ResolveParams) (*reveald.Request, error) {
 	request := reveald.NewRequest()
@@ -58,10 +74,30 @@ func (ar *ArgumentReader) convertArgument(name string, value any) (reveald.Param
 		if v, ok := value.(string); ok {
 			return reveald.NewParameter("sort", v), true, nil
 		}
+	case "collapse", "after":
+		// Collapsing is applied by the resolver, otherwise these are field filters
+		if ar.collapse {
+			return reveald.Parameter{}, false, nil
+		}
+	}
+
+	// Input objects (e.g., hasChild filters) are applied as queries by the resolver
+	if _, ok := value.(map[string]any); ok {
+		return reveald.Parameter{}, false, nil
+	}
+
+	// Arguments generated from the mapping or features map back to their exact ES name
+	if esFieldName, ok := ar.names.esName(ar.scope, name); ok {
+		param, err := ar.convertFieldArgument(esFieldName, value, ar.mapping.GetField(esFieldName))
+		if err != nil {
+			return reveald.Parameter{}, false, err
+		}
+		return param, true, nil
 	}
 
 	// Handle field filters
-	// Convert underscores back to dots for nested fields (GraphQL doesn't allow dots)
+	// Without a registered name (e.g., readers created outside the schema generator),
+	// convert underscores back to dots for nested fields (GraphQL doesn't allow dots)
 	// But keep prefixes intact (e.g., processes_tasks_process → processes_tasks.process)
 	esFieldName := name
 
@@ -55,6 +55,34 @@ func (rb *ResolverBuilder) convertToESQueryInput(arg any) (*ESQueryInput, error)
 		input.Range = rangeInput
 	}
 
+	if dateRange, ok := argMap["dateRange"].(map[string]any); ok {
+		dateRangeInput := &ESDateRangeQueryInput{
+			Field: dateRange["field"].(string),
+		}
+		if gte, ok := dateRange["gte"].(string); ok {
+			dateRangeInput.Gte = &gte
+		}
+		if gt, ok := dateRange["gt"].(string); ok {
+			dateRangeInput.Gt = &gt
+		}
+		if lte, ok := dateRange["lte"].(string); ok {
+			dateRangeInput.Lte = &lte
+		}
+		if lt, ok := dateRange["lt"].(string); ok {
+			dateRangeInput.Lt = &lt
+		}
+		if format, ok := dateRange["format"].(string); ok {
+			dateRangeInput.Format = &format
+		}
+		if tz, ok := dateRange["timeZone"].(string); ok {
+			dateRangeInput.TimeZone = &tz
+		}
+		if err := validateDateRangeBounds(dateRangeInput); err != nil {
+			return nil, err
+		}
+		input.DateRange = dateRangeInput
+	}
+
 	if boolQ, ok := argMap["bool"].(map[string]any); ok {
 		boolInput := &ESBoolQueryInput{}
 
@@ -132,6 +160,30 @@ func (rb *ResolverBuilder) convertToESQueryInput(arg any) (*ESQueryInput, error)
 		}
 	}
 
+	if geoDistance, ok := argMap["geoDistance"].(map[string]any); ok {
+		distanceInput, err := readGeoDistanceInput(geoDistance)
+		if err != nil {
+			return nil, err
+		}
+		input.GeoDistance = distanceInput
+	}
+
+	if geoBoundingBox, ok := argMap["geoBoundingBox"].(map[string]any); ok {
+		boxInput, err := readGeoBoundingBoxInput(geoBoundingBox)
+		if err != nil {
+			return nil, err
+		}
+		input.GeoBoundingBox = boxInput
+	}
+
+	if geoPolygon, ok := argMap["geoPolygon"].(map[string]any); ok {
+		polygonInput, err := readGeoPolygonInput(geoPolygon)
+		if err != nil {
+			return nil, err
+		}
+		input.GeoPolygon = polygonInput
+	}
+
 	return input, nil
 }
 
@@ -180,6 +232,9 @@ func (rb *ResolverBuilder) convertToESAggInputs(arg any) ([]*ESAggInput, error)
 			if mdc, ok := dateHist["minDocCount"].(int); ok {
 				dhInput.MinDocCount = &mdc
 			}
+			if tz, ok := dateHist["timeZone"].(string); ok {
+				dhInput.TimeZone = &tz
+			}
 			input.DateHistogram = dhInput
 		}
 
@@ -226,6 +281,20 @@ func (rb *ResolverBuilder) convertToESAggInputs(arg any) ([]*ESAggInput, error)
 			}
 		}
 
+		if geohashGrid, ok := aggMap["geohashGrid"].(map[string]any); ok {
+			input.GeohashGrid = readGeoGridAggInput(geohashGrid)
+		}
+
+		if geotileGrid, ok := aggMap["geotileGrid"].(map[string]any); ok {
+			input.GeotileGrid = readGeoGridAggInput(geotileGrid)
+		}
+
+		if centroid, ok := aggMap["geoCentroid"].(map[string]any); ok {
+			input.GeoCentroid = &ESGeoCentroidAggInput{
+				Field: centroid["field"].(string),
+			}
+		}
+
 		// Sub-aggregations
 		if subAggs, ok := aggMap["aggs"].([]any); ok {
 			subInputs, err := rb.convertToESAggInputs(subAggs)
@@ -240,3 +309,17 @@ func (rb *ResolverBuilder) convertToESAggInputs(arg any) ([]*ESAggInput, error)
 
 	return inputs, nil
 }
+
+// readGeoGridAggInput reads a geohash_grid or geotile_grid aggregation input
+func readGeoGridAggInput(grid map[string]any) *ESGeoGridAggInput {
+	gridInput := &ESGeoGridAggInput{
+		Field: grid["field"].(string),
+	}
+	if precision, ok := grid["precision"].(int); ok {
+		gridInput.Precision = &precision
+	}
+	if size, ok := grid["size"].(int); ok {
+		gridInput.Size = &size
+	}
+	return gridInput
+}
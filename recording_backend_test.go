@@ -0,0 +1,50 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/reveald/reveald/v2"
+)
+
+// recordingBackend returns fixed hits and records the last request and the ES request built from it
+type recordingBackend struct {
+	hits    []map[string]any
+	request *reveald.Request
+	search  *search.Request
+}
+
+func (b *recordingBackend) Execute(_ context.Context, builder *reveald.QueryBuilder) (*reveald.Result, error) {
+	b.request = builder.Request()
+	b.search = builder.BuildRequest()
+	return &reveald.Result{
+		Hits:          b.hits,
+		TotalHitCount: int64(len(b.hits)),
+		Aggregations:  map[string][]*reveald.ResultBucket{},
+	}, nil
+}
+
+func (b *recordingBackend) ExecuteMultiple(ctx context.Context, builders []*reveald.QueryBuilder) ([]*reveald.Result, error) {
+	var results []*reveald.Result
+	for _, builder := range builders {
+		result, err := b.Execute(ctx, builder)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// searchBody returns the last ES request as JSON
+func (b *recordingBackend) searchBody(t *testing.T) string {
+	t.Helper()
+
+	data, err := json.Marshal(b.search)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,478 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+)
+
+// Defaults for related documents
+const (
+	defaultRelationLimit = 10    // Related documents per document for one-to-many relations
+	maxRelationHits      = 10000 // Keys looked up by one search, larger batches are split (the ES default max_result_window)
+)
+
+// Relation adds a field to a query's document type resolving documents of another query's
+// index, e.g. the iterations of a lead or the customer of an order
+// Related documents of all hits are fetched with one terms query per relation and level of
+// the GraphQL result, collapsed on the target field so each key gets up to Limit documents,
+// applying the target query's RootQuery, RootQueryBuilder and IndexResolver
+type Relation struct {
+	// FieldName is the name of the field added to the document type (e.g., "iterations")
+	FieldName string
+
+	// SourceField is the field of the document holding the key (e.g., "customerId" or "id")
+	// Lists of keys (e.g., "tagIds") relate the document to the documents of each key
+	SourceField string
+
+	// TargetQuery is the query (in Config.Queries) whose index, mapping and document type
+	// are used for the related documents
+	TargetQuery string
+
+	// TargetField is the field of the related documents matched against the key (e.g., "leadId")
+	// It must be a single-valued keyword or integer field; text fields are matched on their
+	// keyword multi-field
+	TargetField string
+
+	// Cardinality is CardinalityOne (default) for a single related document or
+	// CardinalityMany for a list
+	Cardinality Cardinality
+
+	// Filter is an optional query the related documents must match
+	Filter *types.Query
+
+	// Sort orders the related documents, as "<field>-asc" or "<field>-desc" (e.g., "createdAt-desc")
+	// With CardinalityOne, the first document is returned
+	Sort []string
+
+	// Limit is the maximum number of related documents per document (default 10)
+	// Only used with CardinalityMany
+	Limit int
+
+	// Description is an optional description of the field
+	Description string
+}
+
+// limit returns the maximum number of related documents per document
+func (r Relation) limit() int {
+	if r.Cardinality != CardinalityMany {
+		return 1
+	}
+	if r.Limit > 0 {
+		return r.Limit
+	}
+	return defaultRelationLimit
+}
+
+// validate checks a relation against the mappings of its source and target queries
+func (r Relation) validate(source, target *IndexMapping) error {
+	if r.FieldName == "" {
+		return fmt.Errorf("relation has no field name")
+	}
+	if source.GetField(r.SourceField) == nil {
+		return fmt.Errorf("relation %s: source field %q not found", r.FieldName, r.SourceField)
+	}
+	targetField := target.GetField(r.TargetField)
+	if targetField == nil {
+		return fmt.Errorf("relation %s: target field %q not found in query %s", r.FieldName, r.TargetField, r.TargetQuery)
+	}
+	switch targetField.Type {
+	case FieldTypeLong, FieldTypeUnsignedLong, FieldTypeInteger, FieldTypeShort, FieldTypeByte:
+	default:
+		if !isCollapsibleField(targetField) {
+			return fmt.Errorf("relation %s: target field %q must be a keyword or integer field, got %s", r.FieldName, r.TargetField, targetField.Type)
+		}
+	}
+	if r.Cardinality != "" && r.Cardinality != CardinalityOne && r.Cardinality != CardinalityMany {
+		return fmt.Errorf("relation %s: unknown cardinality %q", r.FieldName, r.Cardinality)
+	}
+	for _, option := range r.Sort {
+		if _, _, ok := parseSortValue(option); !ok {
+			return fmt.Errorf("relation %s: invalid sort %q, expected \"<field>-asc\" or \"<field>-desc\"", r.FieldName, option)
+		}
+	}
+	return nil
+}
+
+// addRelationFields adds the relation fields of all queries to their document types
+// Fields are added once every document type exists, so relations may point both ways
+func (sg *SchemaGenerator) addRelationFields() error {
+	for _, queryName := range sortedKeys(sg.config.Queries) {
+		queryConfig := sg.config.Queries[queryName]
+		if len(queryConfig.Relations) == 0 {
+			continue
+		}
+		if queryConfig.HitsType != nil || queryConfig.isMultiIndex() {
+			return fmt.Errorf("query %s: relations need a generated document type for a single index", queryName)
+		}
+
+		mapping := queryConfig.GetMapping()
+		docType, ok := sg.typeCache[queryDocumentTypeName(queryConfig, &mapping)]
+		if !ok {
+			return fmt.Errorf("query %s: document type not found", queryName)
+		}
+
+		for _, relation := range queryConfig.Relations {
+			targetConfig, ok := sg.config.Queries[relation.TargetQuery]
+			if !ok {
+				return fmt.Errorf("query %s: relation %s: target query %q not found", queryName, relation.FieldName, relation.TargetQuery)
+			}
+			if targetConfig.HitsType != nil || targetConfig.isMultiIndex() {
+				return fmt.Errorf("query %s: relation %s: target query %s must return a generated document type for a single index", queryName, relation.FieldName, relation.TargetQuery)
+			}
+
+			targetMapping := targetConfig.GetMapping()
+			if err := relation.validate(&mapping, &targetMapping); err != nil {
+				return fmt.Errorf("query %s: %w", queryName, err)
+			}
+			targetType, ok := sg.typeCache[queryDocumentTypeName(targetConfig, &targetMapping)]
+			if !ok {
+				return fmt.Errorf("query %s: relation %s: document type of query %s not found", queryName, relation.FieldName, relation.TargetQuery)
+			}
+
+			var fieldType graphql.Output = targetType
+			if relation.Cardinality == CardinalityMany {
+				fieldType = graphql.NewList(targetType)
+			}
+
+			// Reserve the name so mapping fields sanitising to it are reported
+			sg.names.reserve(docType.Name(), relation.FieldName)
+			docType.AddFieldConfig(relation.FieldName, &graphql.Field{
+				Type:        fieldType,
+				Description: relation.Description,
+				Resolve:     sg.resolverBuilder.BuildRelationResolver(relation, targetConfig),
+			})
+		}
+	}
+	return nil
+}
+
+// queryDocumentTypeName returns the name of the document type of a single-index query
+func queryDocumentTypeName(queryConfig *QueryConfig, mapping *IndexMapping) string {
+	if queryConfig.HitsTypeName != "" {
+		return queryConfig.HitsTypeName
+	}
+	return documentTypeName(mapping)
+}
+
+// relationLoadersKey is the context key of the relation loaders of a request
+const relationLoadersKey = contextKey("relationLoaders")
+
+// NewRequestContext prepares the context of one GraphQL request, so relation and join lookups
+// are batched per request level. The API's handler does this for every request; callers
+// executing the schema with graphql.Do should wrap each request's context. Without it, every
+// document is looked up on its own.
+func NewRequestContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, relationLoadersKey, &relationLoaders{loaders: make(map[*loaderID]*relationLoader)})
+}
+
+// relationLoaders holds the loaders of one request by field
+type relationLoaders struct {
+	mu      sync.Mutex
+	loaders map[*loaderID]*relationLoader
+}
+
+// loaderID identifies the loader of a field resolver within a request
+type loaderID struct {
+	field string
+}
+
+// requestLoader returns the loader of a field for the request of the params, creating it on first use
+// Without loaders in the context a new loader is returned, so lookups are never shared between requests
+func requestLoader(params graphql.ResolveParams, id *loaderID) *relationLoader {
+	loaders, ok := requestContext(params).Value(relationLoadersKey).(*relationLoaders)
+	if !ok {
+		return newRelationLoader()
+	}
+
+	loaders.mu.Lock()
+	defer loaders.mu.Unlock()
+	loader, ok := loaders.loaders[id]
+	if !ok {
+		loader = newRelationLoader()
+		loaders.loaders[id] = loader
+	}
+	return loader
+}
+
+// relationLoader batches the lookups of one field within one request
+// Resolvers add their keys to the pending batch and return a thunk; graphql-go resolves thunks
+// breadth first, so the first thunk of a level runs one query for the keys of all documents on
+// that level. Keys added later (e.g., by relations of the related documents) start a new batch.
+type relationLoader struct {
+	mu      sync.Mutex
+	pending map[string]*relationBatch // Pending batch by variant
+}
+
+// relationBatch holds the keys of one lookup and, once run, the related documents
+type relationBatch struct {
+	keys    []string
+	seen    map[string]bool
+	once    sync.Once
+	related *relatedHits
+	err     error
+}
+
+// relatedHits holds the hits of a lookup in sort order and their positions by key
+type relatedHits struct {
+	hits  []map[string]any
+	byKey map[string][]int
+}
+
+// newRelationLoader creates a loader without pending batches
+func newRelationLoader() *relationLoader {
+	return &relationLoader{pending: make(map[string]*relationBatch)}
+}
+
+// add adds keys to the pending batch of a variant, creating it if needed
+// The variant describes the field's arguments, so batches only share keys looked up the same way
+func (l *relationLoader) add(variant string, keys []string) *relationBatch {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	batch, ok := l.pending[variant]
+	if !ok {
+		batch = &relationBatch{seen: make(map[string]bool)}
+		l.pending[variant] = batch
+	}
+	for _, key := range keys {
+		if !batch.seen[key] {
+			batch.seen[key] = true
+			batch.keys = append(batch.keys, key)
+		}
+	}
+	return batch
+}
+
+// run runs the lookup of a batch once, closing it for new keys first
+func (l *relationLoader) run(variant string, batch *relationBatch, lookup func(keys []string) (*relatedHits, error)) {
+	batch.once.Do(func() {
+		l.mu.Lock()
+		if l.pending[variant] == batch {
+			delete(l.pending, variant)
+		}
+		l.mu.Unlock()
+
+		batch.related, batch.err = lookupInChunks(batch.keys, lookup)
+	})
+}
+
+// lookupInChunks runs a lookup per maxRelationHits keys, as one search returns at most that
+// many hits or buckets, and merges the related documents
+func lookupInChunks(keys []string, lookup func(keys []string) (*relatedHits, error)) (*relatedHits, error) {
+	if len(keys) <= maxRelationHits {
+		return lookup(keys)
+	}
+
+	merged := &relatedHits{byKey: make(map[string][]int)}
+	for chunk := range slices.Chunk(keys, maxRelationHits) {
+		related, err := lookup(chunk)
+		if err != nil {
+			return nil, err
+		}
+		for key, positions := range related.byKey {
+			for _, i := range positions {
+				merged.byKey[key] = append(merged.byKey[key], len(merged.hits)+i)
+			}
+		}
+		merged.hits = append(merged.hits, related.hits...)
+	}
+	return merged, nil
+}
+
+// BuildRelationResolver creates the resolver of a relation field
+func (rb *ResolverBuilder) BuildRelationResolver(relation Relation, target *QueryConfig) graphql.FieldResolveFn {
+	targetMapping := target.GetMapping()
+	id := &loaderID{field: relation.FieldName}
+
+	return func(params graphql.ResolveParams) (any, error) {
+		keys := relationKeys(selectJSONPath(params.Source, relation.SourceField))
+		if len(keys) == 0 {
+			return nil, nil
+		}
+
+		httpReq, _ := getHTTPRequest(params)
+		loader := requestLoader(params, id)
+		batch := loader.add("", keys)
+
+		return func() (any, error) {
+			loader.run("", batch, func(keys []string) (*relatedHits, error) {
+				return rb.lookupRelated(params.Context, httpReq, relation, target, &targetMapping, keys)
+			})
+			if batch.err != nil {
+				return nil, fmt.Errorf("failed to resolve %s: %w", relation.FieldName, batch.err)
+			}
+
+			related := batch.related.documents(keys, relation.limit())
+			if relation.Cardinality != CardinalityMany {
+				if len(related) == 0 {
+					return nil, nil
+				}
+				return related[0], nil
+			}
+			return related, nil
+		}, nil
+	}
+}
+
+// lookupRelated fetches the documents related to the keys with one terms query, grouped by key
+// The hits are collapsed on the target field, so every key gets its own documents however many
+// documents other keys have
+func (rb *ResolverBuilder) lookupRelated(ctx context.Context, httpReq *http.Request, relation Relation, target *QueryConfig, targetMapping *IndexMapping, keys []string) (*relatedHits, error) {
+	field := exactFieldPath(relation.TargetField, targetMapping)
+	keyQuery := &types.Query{
+		Terms: &types.TermsQuery{
+			TermsQuery: map[string]types.TermsQueryField{field: fieldValues(keys)},
+		},
+	}
+
+	collapse := &collapseOptions{field: field}
+	if limit := relation.limit(); limit > 1 {
+		collapse.innerHits = &innerHitsOptions{size: limit, sort: relation.Sort}
+	}
+
+	size := min(len(keys), maxRelationHits)
+	hits, err := rb.searchRelated(ctx, httpReq, target, targetMapping, mergeQueries(relation.Filter, keyQuery), size, relation.Sort, collapse)
+	if err != nil {
+		return nil, err
+	}
+	return groupHits(hits, func(hit map[string]any) []string {
+		return relationKeys(selectJSONPath(hit, relation.TargetField))
+	}), nil
+}
+
+// searchRelated searches the index of a target query for related documents, filtered like the
+// target query's own results (its RootQuery, RootQueryBuilder and IndexResolver apply)
+// Collapsed searches return the documents of each group (the top hit, or its collapsed hits
+// when requested) in group order
+func (rb *ResolverBuilder) searchRelated(ctx context.Context, httpReq *http.Request, target *QueryConfig, targetMapping *IndexMapping, query *types.Query, size int, sort []string, collapse *collapseOptions) ([]map[string]any, error) {
+	if rb.esClient == nil {
+		return nil, fmt.Errorf("ES client not configured - relations require typed ES client")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	indices, req, err := relatedRequest(httpReq, target, query, size)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range sort {
+		field, order, _ := parseSortValue(option)
+		req.Sort = append(req.Sort, types.SortOptions{
+			SortOptions: map[string]types.FieldSort{field: {Order: &order}},
+		})
+	}
+	if collapse != nil {
+		collapse.apply(req)
+	}
+
+	result, err := executeTypedRequest(ctx, rb.esClient, indices, req)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := result.Hits
+	if collapse != nil && collapse.innerHits != nil {
+		hits = nil
+		for _, hit := range result.Hits {
+			innerHits, _ := hit[innerHitsKey].(map[string][]map[string]any)
+			hits = append(hits, innerHits[collapsedHitsName]...)
+		}
+	}
+	for _, hit := range hits {
+		normalizeObjectCardinality(hit, targetMapping)
+	}
+	return hits, nil
+}
+
+// relatedRequest returns the indices and search request of a lookup in a target query's index,
+// applying the target query's RootQuery, RootQueryBuilder and IndexResolver
+func relatedRequest(httpReq *http.Request, target *QueryConfig, query *types.Query, size int) ([]string, *search.Request, error) {
+	indices, err := target.IndexResolver.resolve(httpReq, nil, target.GetIndices())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dynamicRootQuery *types.Query
+	if target.RootQueryBuilder != nil {
+		if httpReq == nil {
+			return nil, nil, fmt.Errorf("HTTP request not available in context")
+		}
+		dynamicRootQuery, err = target.RootQueryBuilder(httpReq)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build root query: %w", err)
+		}
+	}
+	return indices, newTypedRequest(mergeQueries(target.RootQuery, dynamicRootQuery, query), nil, &size, nil), nil
+}
+
+// groupHits records the positions of hits by the keys returned for each hit
+func groupHits(hits []map[string]any, keys func(hit map[string]any) []string) *relatedHits {
+	related := &relatedHits{hits: hits, byKey: make(map[string][]int)}
+	for i, hit := range hits {
+		for _, key := range keys(hit) {
+			related.byKey[key] = append(related.byKey[key], i)
+		}
+	}
+	return related
+}
+
+// documents returns the related documents of a document's keys in the lookup's sort order,
+// without duplicates and up to limit
+func (r *relatedHits) documents(keys []string, limit int) []map[string]any {
+	var positions []int
+	seen := make(map[int]bool)
+	for _, key := range keys {
+		for _, i := range r.byKey[key] {
+			if !seen[i] {
+				seen[i] = true
+				positions = append(positions, i)
+			}
+		}
+	}
+	sort.Ints(positions)
+
+	docs := make([]map[string]any, 0, min(len(positions), limit))
+	for _, i := range positions[:min(len(positions), limit)] {
+		docs = append(docs, r.hits[i])
+	}
+	return docs
+}
+
+// fieldValues converts keys to the values of a terms query
+func fieldValues(keys []string) []types.FieldValue {
+	values := make([]types.FieldValue, len(keys))
+	for i, key := range keys {
+		values[i] = key
+	}
+	return values
+}
+
+// relationKeys returns the keys held by a field value (a scalar or a list of scalars)
+func relationKeys(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		var keys []string
+		for _, item := range v {
+			keys = append(keys, relationKeys(item)...)
+		}
+		return keys
+	case map[string]any:
+		return nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
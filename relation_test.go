@@ -0,0 +1,277 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+func relationTestConfig() *Config {
+	return NewConfig(
+		WithQuery("leads", &QueryConfig{
+			Mapping: IndexMapping{
+				IndexName: "leads",
+				Properties: map[string]*Field{
+					"id":         {Name: "id", Type: FieldTypeKeyword},
+					"customerId": {Name: "customerId", Type: FieldTypeKeyword},
+				},
+			},
+			Features: []reveald.Feature{&mockWrapperFeature{}},
+			Relations: []Relation{
+				{
+					FieldName:   "customer",
+					SourceField: "customerId",
+					TargetQuery: "customers",
+					TargetField: "id",
+					Description: "Customer of the lead",
+				},
+				{
+					FieldName:   "iterations",
+					SourceField: "id",
+					TargetQuery: "iterations",
+					TargetField: "leadId",
+					Cardinality: CardinalityMany,
+					Sort:        []string{"createdAt-desc"},
+					Limit:       2,
+				},
+			},
+		}),
+		WithQuery("customers", &QueryConfig{
+			Mapping: IndexMapping{
+				IndexName: "customers",
+				Properties: map[string]*Field{
+					"id":   {Name: "id", Type: FieldTypeKeyword},
+					"name": {Name: "name", Type: FieldTypeText},
+				},
+			},
+			Features:  []reveald.Feature{&mockWrapperFeature{}},
+			RootQuery: &types.Query{Term: map[string]types.TermQuery{"active": {Value: true}}},
+		}),
+		WithQuery("iterations", &QueryConfig{
+			Mapping: IndexMapping{
+				IndexName: "iterations",
+				Properties: map[string]*Field{
+					"id":        {Name: "id", Type: FieldTypeKeyword},
+					"leadId":    {Name: "leadId", Type: FieldTypeKeyword},
+					"createdAt": {Name: "createdAt", Type: FieldTypeDate},
+				},
+			},
+			Features: []reveald.Feature{&mockWrapperFeature{}},
+			Relations: []Relation{
+				{FieldName: "lead", SourceField: "leadId", TargetQuery: "leads", TargetField: "id"},
+			},
+		}),
+	)
+}
+
+func TestRelationsSchema(t *testing.T) {
+	sdl, err := GenerateSchemaSDL(relationTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	expected := []string{
+		"\"\"\"Customer of the lead\"\"\"\n  customer: CustomersDocument",
+		"iterations: [IterationsDocument]",
+		"lead: LeadsDocument",
+	}
+	for _, e := range expected {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+}
+
+func TestRelationsInvalid(t *testing.T) {
+	tests := map[string]Relation{
+		"unknown target query": {FieldName: "a", SourceField: "customerId", TargetQuery: "missing", TargetField: "id"},
+		"missing source field": {FieldName: "a", SourceField: "missing", TargetQuery: "customers", TargetField: "id"},
+		"missing target field": {FieldName: "a", SourceField: "customerId", TargetQuery: "customers", TargetField: "missing"},
+		"invalid sort":         {FieldName: "a", SourceField: "customerId", TargetQuery: "customers", TargetField: "id", Sort: []string{"name"}},
+		"text target field":    {FieldName: "a", SourceField: "customerId", TargetQuery: "customers", TargetField: "name"},
+	}
+
+	for name, relation := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := relationTestConfig()
+			config.Queries["leads"].Relations = []Relation{relation}
+			if _, err := GenerateSchemaSDL(config); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestRelationsBatched(t *testing.T) {
+	es := newFakeES(t, docsResponse(map[string][]map[string]any{
+		"customers": {
+			{"id": "c1", "name": "Acme"},
+			{"id": "c2", "name": "Globex"},
+		},
+		"iterations": {
+			{"id": "i1", "leadId": "l1"},
+			{"id": "i2", "leadId": "l1"},
+			{"id": "i3", "leadId": "l1"},
+			{"id": "i4", "leadId": "l2"},
+		},
+		"leads": {
+			{"id": "l1", "customerId": "c1"},
+			{"id": "l2", "customerId": "c2"},
+		},
+	}, nil))
+
+	backend := &recordingBackend{hits: []map[string]any{
+		{"id": "l1", "customerId": "c1"},
+		{"id": "l2", "customerId": "c2"},
+		{"id": "l3", "customerId": "c1"},
+	}}
+	api, err := New(backend, relationTestConfig(), WithESClient(es.client))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		Context:       NewRequestContext(context.Background()),
+		RequestString: `{ leads { hits { id customer { name } iterations { id lead { id } } } } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+
+	// One lookup per relation and level: customers and iterations, then the leads of the iterations
+	bodies := es.requests()
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 lookups, got %d: %v", len(bodies), bodies)
+	}
+	joined := strings.Join(bodies, "\n")
+	for _, e := range []string{
+		`"terms":{"id":["c1","c2"]}`,
+		`"term":{"active":{"value":true}}`,
+		`"terms":{"leadId":["l1","l2","l3"]}`,
+		`{"createdAt":{"order":"desc"}}`,
+	} {
+		if !strings.Contains(joined, e) {
+			t.Errorf("Lookups should contain %s, got %s", e, joined)
+		}
+	}
+
+	hits := result.Data.(map[string]any)["leads"].(map[string]any)["hits"].([]any)
+	lead := hits[0].(map[string]any)
+	if name := lead["customer"].(map[string]any)["name"]; name != "Acme" {
+		t.Errorf("Expected customer Acme, got %v", name)
+	}
+	if iterations := lead["iterations"].([]any); len(iterations) != 2 {
+		t.Errorf("Expected iterations limited to 2, got %v", iterations)
+	} else if id := iterations[0].(map[string]any)["lead"].(map[string]any)["id"]; id != "l1" {
+		t.Errorf("Expected the iteration's lead, got %v", id)
+	}
+	if iterations := hits[2].(map[string]any)["iterations"].([]any); len(iterations) != 0 {
+		t.Errorf("Expected no iterations for l3, got %v", iterations)
+	}
+}
+
+func TestRelationLoadersPerRequest(t *testing.T) {
+	id := &loaderID{field: "customer"}
+	first := graphql.ResolveParams{Context: NewRequestContext(context.Background())}
+	second := graphql.ResolveParams{Context: NewRequestContext(context.Background())}
+
+	loader := requestLoader(first, id)
+	if requestLoader(first, id) != loader {
+		t.Error("Expected a request to reuse its loader")
+	}
+	if requestLoader(second, id) == loader {
+		t.Error("Expected requests sharing a parent context to get loaders of their own")
+	}
+
+	// Without request loaders every lookup gets a loader of its own
+	bare := graphql.ResolveParams{Context: context.Background()}
+	if requestLoader(bare, id) == requestLoader(bare, id) {
+		t.Error("Expected no loader to be shared without a request context")
+	}
+
+	// Batches of different requests never share keys
+	batch := requestLoader(first, id).add("", []string{"c1"})
+	if other := requestLoader(second, id).add("", []string{"c2"}); other == batch || len(batch.keys) != 1 {
+		t.Errorf("Expected separate batches, got %v and %v", batch.keys, other.keys)
+	}
+}
+
+func TestRelationsLimitPerKey(t *testing.T) {
+	// l1 has more iterations than the limit, listed before the iteration of l2
+	es := newFakeES(t, docsResponse(map[string][]map[string]any{
+		"iterations": {
+			{"id": "i1", "leadId": "l1"},
+			{"id": "i2", "leadId": "l1"},
+			{"id": "i3", "leadId": "l1"},
+			{"id": "i4", "leadId": "l1"},
+			{"id": "i5", "leadId": "l1"},
+			{"id": "i6", "leadId": "l2"},
+		},
+	}, nil))
+	backend := &recordingBackend{hits: []map[string]any{{"id": "l1"}, {"id": "l2"}}}
+	api, err := New(backend, relationTestConfig(), WithESClient(es.client))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{Schema: api.GetSchema(), Context: NewRequestContext(context.Background()), RequestString: `{ leads { hits { id iterations { id } } } }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+
+	body := es.requests()[0]
+	if !strings.Contains(body, `"collapse":{"field":"leadId","inner_hits":[{"name":"_collapsed","size":2,"sort":[{"createdAt":{"order":"desc"}}]}]}`) || !strings.Contains(body, `"size":2`) {
+		t.Errorf("Expected the lookup collapsed on the target field, got %s", body)
+	}
+
+	hits := result.Data.(map[string]any)["leads"].(map[string]any)["hits"].([]any)
+	if iterations := hits[0].(map[string]any)["iterations"].([]any); len(iterations) != 2 {
+		t.Errorf("Expected iterations of l1 limited to 2, got %v", iterations)
+	}
+	if iterations := hits[1].(map[string]any)["iterations"].([]any); len(iterations) != 1 || iterations[0].(map[string]any)["id"] != "i6" {
+		t.Errorf("Expected the iteration of l2, got %v", iterations)
+	}
+}
+
+func TestLookupInChunks(t *testing.T) {
+	keys := make([]string, maxRelationHits+2)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	// Batches above maxRelationHits keys are split, not truncated
+	var chunks []int
+	related, err := lookupInChunks(keys, func(keys []string) (*relatedHits, error) {
+		chunks = append(chunks, len(keys))
+		hits := make([]map[string]any, len(keys))
+		for i, key := range keys {
+			hits[i] = map[string]any{"key": key}
+		}
+		return groupHits(hits, func(hit map[string]any) []string { return []string{hit["key"].(string)} }), nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fmt.Sprint(chunks) != fmt.Sprintf("[%d 2]", maxRelationHits) {
+		t.Errorf("Expected two lookups, got %v", chunks)
+	}
+	for _, key := range []string{"0", keys[len(keys)-1]} {
+		if docs := related.documents([]string{key}, 1); len(docs) != 1 || docs[0]["key"] != key {
+			t.Errorf("Expected the document of key %s, got %v", key, docs)
+		}
+	}
+}
+
+func TestRelationKeys(t *testing.T) {
+	keys := relationKeys([]any{"a", float64(12345678), 1.5, true})
+	if strings.Join(keys, ",") != "a,12345678,1.5,true" {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+}
@@ -2,10 +2,10 @@ package graphql
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	elasticsearch "github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
@@ -18,6 +18,7 @@ import (
 type ResolverBuilder struct {
 	backend  reveald.Backend
 	esClient *elasticsearch.TypedClient
+	names    *nameRegistry // Set by the SchemaGenerator using this builder
 }
 
 // NewResolverBuilder creates a new resolver builder
@@ -30,21 +31,34 @@ func NewResolverBuilder(backend reveald.Backend, esClient *elasticsearch.TypedCl
 
 // BuildResolver creates a resolver function for a query
 func (rb *ResolverBuilder) BuildResolver(queryName string, config *QueryConfig) graphql.FieldResolveFn {
-	// Create argument reader from query's mapping
-	reader := NewArgumentReader(&config.Mapping)
+	// Merge per-index mappings once, the result is shared by all requests
+	mapping := config.GetMapping()
 
-	// Create the endpoint with configured indices and features
-	endpoint := reveald.NewEndpoint(rb.backend, reveald.WithIndices(config.Mapping.IndexName))
+	// Create argument reader from query's mapping
+	reader := NewArgumentReader(&mapping).withNames(rb.names, queryName).withCollapse(len(config.CollapseFields) > 0)
 
-	if err := endpoint.Register(config.Features...); err != nil {
+	// Create the endpoint with configured indices and features, shared by requests that use them
+	features, err := config.features(&mapping)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create features for query %s: %v", queryName, err))
+	}
+	endpoints, err := newQueryEndpoints(rb.backend, features, config.GetIndices())
+	if err != nil {
 		panic(fmt.Sprintf("failed to register features for query %s: %v", queryName, err))
 	}
 
 	return func(params graphql.ResolveParams) (any, error) {
+		// Pick the indices for this request (the configured ones unless an IndexResolver is set)
+		httpReq, _ := getHTTPRequest(params)
+		indices, err := config.IndexResolver.resolve(httpReq, params.Args, config.GetIndices())
+		if err != nil {
+			return nil, err
+		}
+
 		// Check if this is an ES typed query
 		if config.EnableElasticQuerying && rb.esClient != nil {
 			if queryArg, hasQuery := params.Args["query"]; hasQuery && queryArg != nil {
-				return rb.executeTypedESQuery(params, config, &config.Mapping)
+				return rb.executeTypedESQuery(params, queryName, config, &mapping, indices)
 			}
 		}
 
@@ -66,19 +80,112 @@ func (rb *ResolverBuilder) BuildResolver(queryName string, config *QueryConfig)
 			}
 		}
 
+		// Route to the resolved indices, request the selected computed fields and inner hits,
+		// and apply join and geo filters, kNN search, spelling suggestions, collapsing and distance sorting
+		// with a request-scoped endpoint
+		var requestFeatures []reveald.Feature
+		if config.hasComputedFields() {
+			ranges := rb.runtimeRanges(params.Args, queryName, config.RuntimeFields)
+			computed := config.computedFieldFeature(rb.selectedFields(params.Info, "hits"), rb.selectedFields(params.Info, "aggregations"), ranges)
+			if computed.usedBy(request) {
+				requestFeatures = append(requestFeatures, computed)
+			}
+		}
+		geoQuery, err := rb.geoQuery(params.Args, config, &mapping)
+		if err != nil {
+			return nil, err
+		}
+		if filterQuery := mergeQueries(rb.joinQuery(params.Args, &mapping), geoQuery); filterQuery != nil {
+			requestFeatures = append(requestFeatures, &filterQueryFeature{query: filterQuery})
+		}
+		if len(config.InnerHits) > 0 {
+			innerHits, err := rb.selectedInnerHits(params.Info, &mapping)
+			if err != nil {
+				return nil, err
+			}
+			if len(innerHits) > 0 {
+				requestFeatures = append(requestFeatures, &innerHitsFeature{selected: innerHits})
+			}
+		}
+		knn, err := knnRequest(params.Args, config.KNN, &mapping)
+		if err != nil {
+			return nil, err
+		}
+		if knn != nil {
+			requestFeatures = append(requestFeatures, &knnFeature{ctx: requestContext(params), options: knn, embedder: config.KNN.Embedder})
+		}
+		var spellCheck *SpellCheck
+		if config.SpellCheck != nil && selectsField(params.Info, "suggestions") {
+			if rb.esClient == nil {
+				return nil, fmt.Errorf("spelling suggestions require an Elasticsearch client (WithESClient)")
+			}
+			spellCheck = config.SpellCheck
+		}
+		collapse, err := rb.collapseRequest(params, &mapping)
+		if err != nil {
+			return nil, err
+		}
+		if collapse != nil && rb.esClient == nil {
+			return nil, fmt.Errorf("collapse requires an Elasticsearch client (WithESClient)")
+		}
+		byDistance, err := distanceSortRequest(params.Args, config, &mapping)
+		if err != nil {
+			return nil, err
+		}
+		if byDistance != nil && rb.esClient == nil {
+			return nil, fmt.Errorf("sortByDistance requires an Elasticsearch client (WithESClient)")
+		}
+		var typedHits *typedHitsFeature
+		if collapse != nil || byDistance != nil || spellCheck != nil {
+			typedHits = &typedHitsFeature{ctx: requestContext(params), client: rb.esClient, collapse: collapse, distanceSort: byDistance, spellCheck: spellCheck}
+			requestFeatures = append(requestFeatures, typedHits)
+		}
+
+		// Request features hold the state of one request, so they get an endpoint of their own,
+		// as do index sets other than the configured one
+		requestEndpoint, err := endpoints.get(indices, requestFeatures...)
+		if err != nil {
+			return nil, err
+		}
+
 		// Execute the query
-		result, err := endpoint.Execute(context.Background(), request)
+		result, err := requestEndpoint.Execute(requestContext(params), request)
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute query: %w", err)
 		}
 
+		// The typed hits feature reads its own hits, other hits are read from the raw response
+		if typedHits == nil {
+			if err := setRawHits(result); err != nil {
+				return nil, fmt.Errorf("failed to read hits: %w", err)
+			}
+		}
+
 		// Convert reveald Result to GraphQL response
-		return rb.convertResult(result, config, &config.Mapping), nil
+		response := rb.convertResult(result, queryName, config, &mapping)
+		if typedHits != nil && typedHits.groups != nil {
+			response["totalGroups"] = *typedHits.groups
+		}
+		if typedHits != nil && typedHits.cursor != nil {
+			response["cursor"] = *typedHits.cursor
+		}
+		if spellCheck != nil {
+			response["suggestions"] = typedHits.suggestions
+		}
+		return response, nil
 	}
 }
 
 // executeTypedESQuery handles typed Elasticsearch queries
-func (rb *ResolverBuilder) executeTypedESQuery(params graphql.ResolveParams, config *QueryConfig, mapping *IndexMapping) (any, error) {
+func (rb *ResolverBuilder) executeTypedESQuery(params graphql.ResolveParams, queryName string, config *QueryConfig, mapping *IndexMapping, indices []string) (any, error) {
+	// Default time zone for date ranges and date histograms that do not set their own
+	timeZone, _ := params.Args["timeZone"].(string)
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("invalid timeZone %q: %w", timeZone, err)
+		}
+	}
+
 	// Convert GraphQL query argument to ES Query
 	var userQuery *types.Query
 	if queryArg, ok := params.Args["query"]; ok && queryArg != nil {
@@ -86,6 +193,10 @@ func (rb *ResolverBuilder) executeTypedESQuery(params graphql.ResolveParams, con
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert query input: %w", err)
 		}
+		if timeZone != "" {
+			applyQueryTimeZone(queryInput, timeZone)
+		}
+		applyQueryKeywordRouting(queryInput, mapping)
 		userQuery, err = convertQueryInput(queryInput)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert query: %w", err)
@@ -106,8 +217,25 @@ func (rb *ResolverBuilder) executeTypedESQuery(params graphql.ResolveParams, con
 		}
 	}
 
-	// Merge static root query, dynamic root query, and user query
-	finalQuery := mergeQueries(config.RootQuery, dynamicRootQuery, userQuery)
+	// Merge static root query, dynamic root query, user query and join and geo filters
+	geoQuery, err := rb.geoQuery(params.Args, config, mapping)
+	if err != nil {
+		return nil, err
+	}
+	finalQuery := mergeQueries(config.RootQuery, dynamicRootQuery, userQuery, rb.joinQuery(params.Args, mapping), geoQuery)
+
+	// Rank by vector similarity, combined with the text query and filtered by the other clauses
+	knn, err := knnRequest(params.Args, config.KNN, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if knn != nil {
+		knnQuery, err := knn.query(requestContext(params), config.KNN.Embedder, finalQuery)
+		if err != nil {
+			return nil, err
+		}
+		finalQuery = hybridQuery(knnQuery, finalQuery)
+	}
 
 	// Convert GraphQL aggs argument to ES Aggregations
 	var aggs map[string]types.Aggregations
@@ -116,6 +244,12 @@ func (rb *ResolverBuilder) executeTypedESQuery(params graphql.ResolveParams, con
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert aggs input: %w", err)
 		}
+		for _, aggInput := range aggsInputs {
+			if timeZone != "" {
+				applyAggTimeZone(aggInput, timeZone)
+			}
+			applyAggKeywordRouting(aggInput, mapping)
+		}
 		aggs, err = convertAggsInput(aggsInputs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert aggregations: %w", err)
@@ -131,29 +265,82 @@ func (rb *ResolverBuilder) executeTypedESQuery(params graphql.ResolveParams, con
 		offset = &offsetArg
 	}
 
+	// Add the runtime and script fields selected or used by the query
+	req := newTypedRequest(finalQuery, aggs, limit, offset)
+	applyComputedFields(req, config.RuntimeFields, config.ScriptFields, rb.selectedFields(params.Info, "hits"))
+
+	// Request inner hits for the selected matched fields
+	if len(config.InnerHits) > 0 {
+		innerHits, err := rb.selectedInnerHits(params.Info, mapping)
+		if err != nil {
+			return nil, err
+		}
+		applyInnerHits(req.Query, innerHits)
+	}
+
+	// Request spelling suggestions for the search text in the same request
+	spellCheck := config.SpellCheck != nil && selectsField(params.Info, "suggestions")
+	if spellCheck {
+		if text := searchText(req.Query); text != "" {
+			req.Suggest = config.SpellCheck.suggester(text)
+		}
+	}
+
+	// Sort the hits by distance to an origin
+	byDistance, err := distanceSortRequest(params.Args, config, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if byDistance != nil {
+		byDistance.apply(req)
+	}
+
+	// Collapse the hits on the requested field
+	collapse, err := rb.collapseRequest(params, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if collapse != nil {
+		if err := collapse.apply(req); err != nil {
+			return nil, err
+		}
+	}
+
 	// Execute typed query
-	result, err := executeTypedQuery(
-		context.Background(),
-		rb.esClient,
-		[]string{mapping.IndexName},
-		finalQuery,
-		aggs,
-		limit,
-		offset,
-	)
+	resp, err := searchTyped(requestContext(params), rb.esClient, indices, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute typed query: %w", err)
+	}
+	groups, countedGroups := takeGroupCount(resp)
+	result, err := typedResult(resp, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute typed query: %w", err)
 	}
+	if byDistance != nil {
+		byDistance.setDistances(resp, result.Hits)
+	}
 
 	// Convert to GraphQL response
-	return rb.convertResult(result, config, mapping), nil
+	response := rb.convertResult(result, queryName, config, mapping)
+	if countedGroups {
+		response["totalGroups"] = groups
+	}
+	if collapse != nil {
+		if cursor := collapse.cursor(resp); cursor != nil {
+			response["cursor"] = *cursor
+		}
+	}
+	if spellCheck {
+		response["suggestions"] = config.SpellCheck.spellingSuggestions(resp)
+	}
+	return response, nil
 }
 
 // convertResult converts a reveald Result to a GraphQL response
-func (rb *ResolverBuilder) convertResult(result *reveald.Result, config *QueryConfig, mapping *IndexMapping) map[string]any {
-	// Normalize hits to ensure object fields with properties are arrays
+func (rb *ResolverBuilder) convertResult(result *reveald.Result, queryName string, config *QueryConfig, mapping *IndexMapping) map[string]any {
+	// Normalize hits so object fields match their single/list cardinality in the schema
 	for _, hit := range result.Hits {
-		normalizeObjectsToArrays(hit, mapping)
+		normalizeObjectCardinality(hit, mapping)
 	}
 
 	response := map[string]any{
@@ -163,10 +350,14 @@ func (rb *ResolverBuilder) convertResult(result *reveald.Result, config *QueryCo
 
 	// Add aggregations if enabled
 	if config.EnableAggregations && len(result.Aggregations) > 0 {
+		aggScope := aggregationsTypeName(strings.TrimSuffix(resultTypeName(queryName, config), "Result"))
 		aggResponse := make(map[string]any)
 		for aggName, buckets := range result.Aggregations {
-			// Convert dots to underscores for GraphQL field names
-			gqlAggName := replaceDotsWithUnderscores(aggName)
+			// Use the GraphQL name registered for the aggregation field
+			gqlAggName, ok := rb.names.graphQLName(aggScope, aggName)
+			if !ok {
+				gqlAggName = sanitizeFieldName(aggName)
+			}
 
 			bucketsResponse := convertBucketsWithPath(buckets, "")
 			aggResponse[gqlAggName] = bucketsResponse
@@ -186,37 +377,63 @@ func (rb *ResolverBuilder) convertResult(result *reveald.Result, config *QueryCo
 	return response
 }
 
+// setRawHits reads the hits of a backend result from the raw ES response, the same way as typed
+// hits, so each hit carries its _index, _id and inner hits
+func setRawHits(result *reveald.Result) error {
+	raw := result.RawResult()
+	if raw == nil {
+		return nil
+	}
+
+	hits := make([]map[string]any, 0, len(raw.Hits.Hits))
+	for _, hit := range raw.Hits.Hits {
+		doc, err := hitDocument(hit)
+		if err != nil {
+			return err
+		}
+		hits = append(hits, doc)
+	}
+	result.Hits = hits
+	return nil
+}
+
 // BuildPrecompiledResolver creates a resolver function for a precompiled query
 func (rb *ResolverBuilder) BuildPrecompiledResolver(queryName string, config *PrecompiledQueryConfig) graphql.FieldResolveFn {
+	// Merge per-index mappings once, the result is shared by all requests
+	mapping := config.GetMapping()
+	indices := config.GetIndices()
+
 	return func(params graphql.ResolveParams) (any, error) {
 		// Extract HTTP request from context for RootQueryBuilder
 		httpReq, _ := getHTTPRequest(params)
 
+		// Pick the indices for this request (the configured ones unless an IndexResolver is set)
+		requestIndices, err := config.IndexResolver.resolve(httpReq, params.Args, indices)
+		if err != nil {
+			return nil, err
+		}
+
 		// Load the query (from file and/or builder) and merge with root queries
 		searchReq, err := config.LoadQuery(params.Args, httpReq)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load query: %w", err)
 		}
+		applyComputedFields(searchReq, config.RuntimeFields, config.ScriptFields, rb.selectedFields(params.Info, "hits"))
 
 		// Execute the search request
-		ctx := context.Background()
-		if params.Context != nil {
-			ctx = params.Context
-		}
-
-		// Build ES search
-		searchBuilder := rb.esClient.Search()
-		for _, idx := range []string{config.Mapping.IndexName} {
-			searchBuilder = searchBuilder.Index(idx)
-		}
+		ctx := requestContext(params)
 
-		resp, err := searchBuilder.Request(searchReq).Do(ctx)
+		// Build ES search across all configured indices, aliases and patterns
+		resp, err := rb.esClient.Search().
+			Index(strings.Join(requestIndices, ",")).
+			Request(searchReq).
+			Do(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("search failed: %w", err)
 		}
 
 		// Convert ES response with typed aggregations
-		return rb.convertPrecompiledESResponseTyped(resp, &config.Mapping), nil
+		return rb.convertPrecompiledESResponseTyped(resp, &mapping), nil
 	}
 }
 
@@ -237,11 +454,12 @@ func (rb *ResolverBuilder) convertPrecompiledESResponseTyped(resp *search.Respon
 	for _, hit := range resp.Hits.Hits {
 		doc := make(map[string]any)
 		doc["id"] = hit.Id_
+		doc[indexFieldName] = hit.Index_
 		if hit.Source_ != nil {
 			var source map[string]any
-			if err := json.Unmarshal(hit.Source_, &source); err == nil {
-				// Normalize object fields to arrays for GraphQL schema compatibility
-				normalizeObjectsToArrays(source, mapping)
+			if err := decodeSource(hit.Source_, &source); err == nil {
+				// Normalize object fields to their cardinality for GraphQL schema compatibility
+				normalizeObjectCardinality(source, mapping)
 				for k, v := range source {
 					doc[k] = v
 				}
@@ -250,6 +468,12 @@ func (rb *ResolverBuilder) convertPrecompiledESResponseTyped(resp *search.Respon
 				}
 			}
 		}
+		// Runtime and script field values
+		for k, v := range hitFieldValues(hit.Fields) {
+			if _, exists := doc[k]; !exists {
+				doc[k] = v
+			}
+		}
 		hits = append(hits, doc)
 	}
 	response["hits"] = hits
@@ -293,6 +517,16 @@ func (rb *ResolverBuilder) convertAggregateValue(agg types.Aggregate) any {
 		return map[string]any{
 			"buckets": rb.convertHistogramBucketsToTyped(v.Buckets),
 		}
+	case *types.GeoHashGridAggregate:
+		return map[string]any{
+			"buckets": rb.convertGeoHashGridBucketsToTyped(v.Buckets),
+		}
+	case *types.GeoTileGridAggregate:
+		return map[string]any{
+			"buckets": rb.convertGeoTileGridBucketsToTyped(v.Buckets),
+		}
+	case *types.GeoCentroidAggregate:
+		return geoCentroidValue(v)
 	case *types.FilterAggregate:
 		result := map[string]any{
 			"doc_count": int64(v.DocCount),
@@ -457,6 +691,50 @@ func (rb *ResolverBuilder) convertHistogramBucketsToTyped(esBuckets types.Bucket
 	return buckets
 }
 
+// convertGeoHashGridBucketsToTyped converts geohash grid buckets to typed format
+func (rb *ResolverBuilder) convertGeoHashGridBucketsToTyped(esBuckets types.BucketsGeoHashGridBucket) []map[string]any {
+	buckets := make([]map[string]any, 0)
+
+	switch v := esBuckets.(type) {
+	case []types.GeoHashGridBucket:
+		for _, b := range v {
+			bucket := map[string]any{
+				"key":       b.Key,
+				"doc_count": b.DocCount,
+			}
+			// Add nested aggregations as direct properties
+			for nestedName, nestedAgg := range b.Aggregations {
+				bucket[nestedName] = rb.convertAggregateValue(nestedAgg)
+			}
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	return buckets
+}
+
+// convertGeoTileGridBucketsToTyped converts geotile grid buckets to typed format
+func (rb *ResolverBuilder) convertGeoTileGridBucketsToTyped(esBuckets types.BucketsGeoTileGridBucket) []map[string]any {
+	buckets := make([]map[string]any, 0)
+
+	switch v := esBuckets.(type) {
+	case []types.GeoTileGridBucket:
+		for _, b := range v {
+			bucket := map[string]any{
+				"key":       b.Key,
+				"doc_count": b.DocCount,
+			}
+			// Add nested aggregations as direct properties
+			for nestedName, nestedAgg := range b.Aggregations {
+				bucket[nestedName] = rb.convertAggregateValue(nestedAgg)
+			}
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	return buckets
+}
+
 // convertFiltersBucketsToObject converts filters buckets (named) to object
 func (rb *ResolverBuilder) convertFiltersBucketsToObject(esBuckets types.BucketsFiltersBucket) map[string]any {
 	result := make(map[string]any)
@@ -480,6 +758,14 @@ func (rb *ResolverBuilder) convertFiltersBucketsToObject(esBuckets types.Buckets
 	return result
 }
 
+// requestContext returns the context of a GraphQL request, for the searches it makes
+func requestContext(params graphql.ResolveParams) context.Context {
+	if params.Context == nil {
+		return context.Background()
+	}
+	return params.Context
+}
+
 // getHTTPRequest extracts the HTTP request from GraphQL resolve params context
 func getHTTPRequest(params graphql.ResolveParams) (*http.Request, bool) {
 	if params.Context == nil {
@@ -489,11 +775,6 @@ func getHTTPRequest(params graphql.ResolveParams) (*http.Request, bool) {
 	return httpReq, ok
 }
 
-// replaceDotsWithUnderscores converts ES field names to GraphQL field names
-func replaceDotsWithUnderscores(s string) string {
-	return strings.ReplaceAll(s, ".", "_")
-}
-
 // convertBucketsWithPath recursively converts reveald buckets to GraphQL response format
 // parentPath tracks the hierarchical path for nested buckets
 func convertBucketsWithPath(buckets []*reveald.ResultBucket, parentPath string) []map[string]any {
@@ -535,68 +816,87 @@ func convertBucketsWithPath(buckets []*reveald.ResultBucket, parentPath string)
 	return bucketsResponse
 }
 
-// normalizeObjectsToArrays converts object fields with properties to arrays
-// to match the GraphQL schema expectation. This handles cases where ES returns
-// a single object but the schema expects an array.
-func normalizeObjectsToArrays(doc map[string]any, mapping *IndexMapping) {
+// normalizeObjectCardinality shapes object fields to match the GraphQL schema
+// ES returns whatever was indexed, so a list field may hold a single object and
+// a single-object field may hold an array. List fields (nested, or CardinalityMany)
+// wrap single objects in an array; single-object fields with properties take the
+// first element of an array.
+func normalizeObjectCardinality(doc map[string]any, mapping *IndexMapping) {
 	for fieldName, value := range doc {
 		if value == nil {
 			continue
 		}
 
-		// Check if this field is an object with properties in the mapping
 		field := mapping.GetField(fieldName)
 		if field == nil {
 			continue
 		}
 
-		// Only normalize object/nested types that have properties
-		if (field.Type == FieldTypeObject || field.Type == FieldTypeNested) && len(field.Properties) > 0 {
-			// Check if value is already an array
-			if _, isArray := value.([]any); isArray {
-				// Already an array, just recurse into each element
-				for _, item := range value.([]any) {
-					if itemMap, ok := item.(map[string]any); ok {
-						normalizeNestedObject(itemMap, field)
-					}
-				}
-			} else if objMap, isMap := value.(map[string]any); isMap {
-				// Single object - recurse first, then wrap in array
-				normalizeNestedObject(objMap, field)
-				doc[fieldName] = []any{objMap}
+		doc[fieldName] = normalizeObjectValue(value, field)
+	}
+}
+
+// normalizeObjectValue normalizes the value of a single field, recursing into object properties
+func normalizeObjectValue(value any, field *Field) any {
+	if field.Type != FieldTypeObject && field.Type != FieldTypeNested {
+		return value
+	}
+
+	if items, isArray := value.([]any); isArray {
+		for _, item := range items {
+			if itemMap, ok := item.(map[string]any); ok {
+				normalizeNestedObject(itemMap, field)
 			}
 		}
+		if !field.isList() && len(field.Properties) > 0 {
+			// Several objects are kept, singleObjectResolver reports them
+			switch len(items) {
+			case 0:
+				return nil
+			case 1:
+				return items[0]
+			}
+		}
+		return items
+	}
+
+	if objMap, isMap := value.(map[string]any); isMap {
+		normalizeNestedObject(objMap, field)
+		if field.isList() {
+			return []any{objMap}
+		}
+	}
+
+	return value
+}
+
+// singleObjectResolver resolves an object field exposed as a single object, failing when the
+// document holds several objects instead of dropping all but the first
+func singleObjectResolver(field *Field) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		source, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		if items, ok := source[field.Name].([]any); ok && len(items) > 1 {
+			return nil, fmt.Errorf("field %s holds %d objects, set its cardinality to %s", field.Name, len(items), CardinalityMany)
+		}
+		return normalizeObjectValue(source[field.Name], field), nil
 	}
 }
 
-// normalizeNestedObject recursively normalizes nested objects
+// normalizeNestedObject recursively normalizes the properties of an object
 func normalizeNestedObject(obj map[string]any, field *Field) {
 	for propName, value := range obj {
 		if value == nil {
 			continue
 		}
 
-		// Check if this property exists in the field definition
 		propField, exists := field.Properties[propName]
 		if !exists {
 			continue
 		}
 
-		// Only normalize object/nested types that have properties
-		if (propField.Type == FieldTypeObject || propField.Type == FieldTypeNested) && len(propField.Properties) > 0 {
-			// Check if value is already an array
-			if _, isArray := value.([]any); isArray {
-				// Already an array, recurse into each element
-				for _, item := range value.([]any) {
-					if itemMap, ok := item.(map[string]any); ok {
-						normalizeNestedObject(itemMap, propField)
-					}
-				}
-			} else if objMap, isMap := value.(map[string]any); isMap {
-				// Single object - recurse first, then wrap in array
-				normalizeNestedObject(objMap, propField)
-				obj[propName] = []any{objMap}
-			}
-		}
+		obj[propName] = normalizeObjectValue(value, propField)
 	}
 }
@@ -0,0 +1,520 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/runtimefieldtype"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/graphql-go/graphql"
+	"github.com/reveald/reveald/v2"
+)
+
+// RuntimeField is a field computed at query time by a Painless script (an ES runtime field)
+// Runtime fields are added to the document type and exposed as filter arguments, sort options
+// and aggregation fields like mapping fields. They are sent to ES as runtime_mappings only
+// when a query selects, filters, sorts or aggregates on them.
+type RuntimeField struct {
+	// Type is the runtime field type: keyword, long, double, date, boolean or ip
+	Type FieldType
+
+	// Script is the Painless source emitting the values
+	// Example: "emit(doc['price'].value * 1.25)"
+	Script string
+
+	// Params are passed to the script as `params`
+	Params map[string]any
+
+	// Description is an optional description of the document field
+	Description string
+}
+
+// ScriptField is a value computed by a Painless script for each hit (an ES script field)
+// Script fields are only added to the document type and sent to ES as script_fields when selected
+type ScriptField struct {
+	// Type is the type of the returned value as a field type (e.g., FieldTypeDouble for a Float)
+	Type FieldType
+
+	// Script is the Painless source returning the value
+	// Example: "ChronoUnit.DAYS.between(doc['createdAt'].value, ZonedDateTime.now())"
+	Script string
+
+	// Params are passed to the script as `params`
+	Params map[string]any
+
+	// Description is an optional description of the document field
+	Description string
+}
+
+// field returns the mapping field describing a runtime field
+func (rf RuntimeField) field(name string) *Field {
+	return &Field{Name: name, Type: rf.Type, Description: rf.Description}
+}
+
+// field returns the mapping field describing a script field
+func (sf ScriptField) field(name string) *Field {
+	return &Field{Name: name, Type: sf.Type, Description: sf.Description}
+}
+
+// isRuntimeFieldType reports whether ES supports a field type for runtime fields
+func isRuntimeFieldType(fieldType FieldType) bool {
+	switch fieldType {
+	case FieldTypeKeyword, FieldTypeLong, FieldTypeDouble, FieldTypeDate, FieldTypeBoolean, FieldTypeIP:
+		return true
+	}
+	return false
+}
+
+// validateComputedFields checks the runtime and script fields of a query
+func validateComputedFields(runtimeFields map[string]RuntimeField, scriptFields map[string]ScriptField, mapping *IndexMapping) error {
+	for name, rf := range runtimeFields {
+		if !isRuntimeFieldType(rf.Type) {
+			return fmt.Errorf("runtime field %s: unsupported type %q", name, rf.Type)
+		}
+		if err := validateScript(name, rf.Script, rf.Params, mapping); err != nil {
+			return fmt.Errorf("runtime field %s: %w", name, err)
+		}
+	}
+
+	for name, sf := range scriptFields {
+		if sf.Type == "" || sf.field(name).hasProperties() {
+			return fmt.Errorf("script field %s: unsupported type %q", name, sf.Type)
+		}
+		if _, ok := runtimeFields[name]; ok {
+			return fmt.Errorf("script field %s: also defined as a runtime field", name)
+		}
+		if err := validateScript(name, sf.Script, sf.Params, mapping); err != nil {
+			return fmt.Errorf("script field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateScript checks a computed field's script and that its name is free in the mapping
+func validateScript(name, source string, params map[string]any, mapping *IndexMapping) error {
+	if mapping.GetField(name) != nil {
+		return fmt.Errorf("name is already used by a mapping field")
+	}
+	if strings.TrimSpace(source) == "" {
+		return fmt.Errorf("script is empty")
+	}
+	if _, err := painlessScript(source, params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// painlessScript creates an inline script, encoding its params
+func painlessScript(source string, params map[string]any) (*types.Script, error) {
+	script := &types.Script{Source: &source}
+	if len(params) == 0 {
+		return script, nil
+	}
+
+	script.Params = make(map[string]json.RawMessage, len(params))
+	for key, value := range params {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode script param %s: %w", key, err)
+		}
+		script.Params[key] = data
+	}
+	return script, nil
+}
+
+// runtimeMapping converts a runtime field to its runtime_mappings entry
+// Scripts are validated when the schema is generated
+func (rf RuntimeField) runtimeMapping() types.RuntimeField {
+	script, _ := painlessScript(rf.Script, rf.Params)
+	return types.RuntimeField{
+		Type:   runtimefieldtype.RuntimeFieldType{Name: string(rf.Type)},
+		Script: script,
+	}
+}
+
+// runtimeFieldSortOptions returns the sort options of the runtime fields ("name-asc", ...)
+func runtimeFieldSortOptions(runtimeFields map[string]RuntimeField) []string {
+	var options []string
+	for _, name := range sortedKeys(runtimeFields) {
+		options = append(options, name+"-asc", name+"-desc")
+	}
+	return options
+}
+
+// sortedKeys returns the keys of a map in order, so generated arguments and enums are stable
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// addComputedFields adds the runtime and script fields to a document type
+func (sg *SchemaGenerator) addComputedFields(fields graphql.Fields, typeName string, runtimeFields map[string]RuntimeField, scriptFields map[string]ScriptField) error {
+	computed := make(map[string]*Field)
+	for name, rf := range runtimeFields {
+		computed[name] = rf.field(name)
+	}
+	for name, sf := range scriptFields {
+		computed[name] = sf.field(name)
+	}
+
+	for _, name := range sortedKeys(computed) {
+		gqlField, err := sg.convertFieldToGraphQL(computed[name])
+		if err != nil {
+			return fmt.Errorf("failed to convert computed field %s: %w", name, err)
+		}
+		sg.registerField(fields, typeName, name, computed[name], gqlField)
+	}
+	return nil
+}
+
+// applyComputedFields adds the runtime and script fields used by a typed search request
+// Runtime fields are added to runtime_mappings when selected (their values are read with
+// `fields`) or referenced by the request (e.g., in a query, sort or aggregation); script
+// fields are added to script_fields when selected
+func applyComputedFields(req *search.Request, runtimeFields map[string]RuntimeField, scriptFields map[string]ScriptField, selected map[string]bool) {
+	referenced := referencedFields(req, runtimeFields)
+
+	for _, name := range sortedKeys(runtimeFields) {
+		if !selected[name] && !referenced[name] {
+			continue
+		}
+		if req.RuntimeMappings == nil {
+			req.RuntimeMappings = make(types.RuntimeFields)
+		}
+		req.RuntimeMappings[name] = runtimeFields[name].runtimeMapping()
+		if selected[name] {
+			req.Fields = append(req.Fields, types.FieldAndFormat{Field: name})
+		}
+	}
+
+	for _, name := range sortedKeys(scriptFields) {
+		if !selected[name] {
+			continue
+		}
+		sf := scriptFields[name]
+		script, _ := painlessScript(sf.Script, sf.Params)
+		if req.ScriptFields == nil {
+			req.ScriptFields = make(map[string]types.ScriptField)
+		}
+		req.ScriptFields[name] = types.ScriptField{Script: *script}
+
+		// Script fields replace _source unless it is requested explicitly
+		if req.Source_ == nil {
+			req.Source_ = true
+		}
+	}
+}
+
+// referencedFields returns the runtime fields referenced by the query, post filter, sort,
+// aggregations or collapse of a request, as object keys (e.g., in a term query or a sort)
+// or as "field" and "fields" values (e.g., in an aggregation or a multi_match query)
+func referencedFields(req *search.Request, runtimeFields map[string]RuntimeField) map[string]bool {
+	referenced := make(map[string]bool)
+	for _, part := range []any{req.Query, req.PostFilter, req.Sort, req.Aggregations, req.Collapse} {
+		data, err := json.Marshal(part)
+		if err != nil {
+			continue
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			continue
+		}
+		collectReferencedFields(decoded, runtimeFields, referenced)
+	}
+	return referenced
+}
+
+// collectReferencedFields walks a decoded request part for the runtime fields it references
+func collectReferencedFields(value any, runtimeFields map[string]RuntimeField, referenced map[string]bool) {
+	reference := func(name string) {
+		// Fields of multi_match and query_string queries may carry a boost ("name^2")
+		name, _, _ = strings.Cut(name, "^")
+		if _, ok := runtimeFields[name]; ok {
+			referenced[name] = true
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			reference(key)
+			switch key {
+			case "field":
+				if name, ok := item.(string); ok {
+					reference(name)
+				}
+			case "fields":
+				if names, ok := item.([]any); ok {
+					for _, name := range names {
+						if name, ok := name.(string); ok {
+							reference(name)
+						}
+					}
+				}
+			}
+			collectReferencedFields(item, runtimeFields, referenced)
+		}
+	case []any:
+		for _, item := range v {
+			collectReferencedFields(item, runtimeFields, referenced)
+		}
+	}
+}
+
+// hitFieldValues converts the `fields` of a hit (runtime and script field values, always arrays)
+// to single values, keeping arrays with several values
+func hitFieldValues(fields map[string]json.RawMessage) map[string]any {
+	values := make(map[string]any, len(fields))
+	for name, raw := range fields {
+		var items []any
+		if err := json.Unmarshal(raw, &items); err != nil {
+			continue
+		}
+		switch len(items) {
+		case 0:
+			values[name] = nil
+		case 1:
+			values[name] = items[0]
+		default:
+			values[name] = items
+		}
+	}
+	return values
+}
+
+// computedFieldFeature filters, sorts and aggregates on the runtime fields of QueryConfig.RuntimeFields
+// and requests the computed fields selected by the GraphQL query
+// It is created per request with the selected hit fields and aggregations and the range filters (ES names)
+// Filters use the field name as parameter name, sort values are "<name>-asc" or "<name>-desc"
+type computedFieldFeature struct {
+	runtimeFields map[string]RuntimeField
+	scriptFields  map[string]ScriptField
+	hits          map[string]bool        // Selected hit fields
+	aggregations  map[string]bool        // Selected aggregation fields
+	ranges        map[string]types.Query // Range filters on double and date runtime fields
+}
+
+// Process implements reveald.Feature
+func (cff *computedFieldFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	cff.build(builder)
+
+	result, err := next(builder)
+	if err != nil {
+		return nil, err
+	}
+
+	return cff.handle(result), nil
+}
+
+func (cff *computedFieldFeature) build(builder *reveald.QueryBuilder) {
+	request := builder.Request()
+	used := make(map[string]types.RuntimeField)
+
+	for name, rf := range cff.runtimeFields {
+		if request.Has(name) {
+			if p, err := request.Get(name); err == nil {
+				fieldValues := make([]types.FieldValue, len(p.Values()))
+				for i, v := range p.Values() {
+					fieldValues[i] = v
+				}
+				builder.With(types.Query{
+					Terms: &types.TermsQuery{
+						TermsQuery: map[string]types.TermsQueryField{name: fieldValues},
+					},
+				})
+				used[name] = rf.runtimeMapping()
+			}
+		}
+
+		if query, ok := cff.ranges[name]; ok {
+			builder.With(query)
+			used[name] = rf.runtimeMapping()
+		}
+
+		if cff.aggregations[name] {
+			builder.Aggregation(name, termsAggregation(name, ""))
+			used[name] = rf.runtimeMapping()
+		}
+
+		if cff.hits[name] {
+			used[name] = rf.runtimeMapping()
+		}
+	}
+
+	if name, order, ok := cff.sortField(request); ok {
+		builder.Selection().Update(reveald.WithSort(name, order))
+		used[name] = cff.runtimeFields[name].runtimeMapping()
+	}
+
+	// Runtime fields are returned as docvalue fields, which the backend adds to the hits
+	if len(used) > 0 {
+		builder.WithRuntimeMappings(used)
+	}
+
+	for name, sf := range cff.scriptFields {
+		if cff.hits[name] {
+			script, _ := painlessScript(sf.Script, sf.Params)
+			builder.WithScriptedField(name, script)
+		}
+	}
+}
+
+// sortField returns the runtime field the request sorts on, if any
+func (cff *computedFieldFeature) sortField(request *reveald.Request) (string, sortorder.SortOrder, bool) {
+	if !request.Has("sort") {
+		return "", sortorder.SortOrder{}, false
+	}
+	p, err := request.Get("sort")
+	if err != nil {
+		return "", sortorder.SortOrder{}, false
+	}
+	name, order, ok := parseSortValue(p.Value())
+	if _, exists := cff.runtimeFields[name]; !ok || !exists {
+		return "", sortorder.SortOrder{}, false
+	}
+	return name, order, true
+}
+
+// usedBy reports whether a request selects, filters, sorts or aggregates on a computed field,
+// so requests that don't can share the query's endpoint
+func (cff *computedFieldFeature) usedBy(request *reveald.Request) bool {
+	if len(cff.ranges) > 0 {
+		return true
+	}
+	if _, _, ok := cff.sortField(request); ok {
+		return true
+	}
+	for name := range cff.runtimeFields {
+		if cff.hits[name] || cff.aggregations[name] || request.Has(name) {
+			return true
+		}
+	}
+	for name := range cff.scriptFields {
+		if cff.hits[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (cff *computedFieldFeature) handle(result *reveald.Result) *reveald.Result {
+	if result.RawResult() == nil {
+		return result
+	}
+
+	for name := range cff.runtimeFields {
+		if !cff.aggregations[name] {
+			continue
+		}
+		agg, ok := result.RawAggregations()[name]
+		if !ok {
+			continue
+		}
+		parsed, err := parseAggregations(map[string]types.Aggregate{name: agg})
+		if err != nil {
+			continue
+		}
+		if buckets, ok := parsed[name]; ok {
+			result.Aggregations[name] = buckets
+		}
+	}
+
+	return result
+}
+
+// hasComputedFields reports whether the query declares runtime or script fields
+func (qc *QueryConfig) hasComputedFields() bool {
+	return len(qc.RuntimeFields) > 0 || len(qc.ScriptFields) > 0
+}
+
+// computedFieldFeature returns the feature requesting the query's computed fields for the
+// selected hit fields and aggregations and the range filters
+func (qc *QueryConfig) computedFieldFeature(hits, aggregations map[string]bool, ranges map[string]types.Query) *computedFieldFeature {
+	return &computedFieldFeature{
+		runtimeFields: qc.RuntimeFields,
+		scriptFields:  qc.ScriptFields,
+		hits:          hits,
+		aggregations:  aggregations,
+		ranges:        ranges,
+	}
+}
+
+// isRangeFilterField reports whether a runtime field is filtered on a range rather than on values
+func isRangeFilterField(field *Field) bool {
+	return field.Type == FieldTypeDouble || field.Type == FieldTypeDate
+}
+
+// rangeFilterInputType returns the input type filtering a double or date runtime field on a range
+func (sg *SchemaGenerator) rangeFilterInputType(field *Field) *graphql.InputObject {
+	typeName, boundType := "FloatRangeFilterInput", graphql.Input(graphql.Float)
+	if field.Type == FieldTypeDate {
+		typeName, boundType = "DateTimeRangeFilterInput", DateTime
+	}
+	if cached, ok := sg.inputCache[typeName]; ok {
+		return cached
+	}
+
+	fields := make(graphql.InputObjectConfigFieldMap)
+	for _, bound := range []string{"gte", "gt", "lte", "lt"} {
+		fields[bound] = &graphql.InputObjectFieldConfig{Type: boundType}
+	}
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        typeName,
+		Description: "Range the values of a computed field must be in",
+		Fields:      fields,
+	})
+	sg.inputCache[typeName] = inputType
+	return inputType
+}
+
+// runtimeRanges returns the range filters of the arguments on double and date runtime fields, keyed by ES name
+func (rb *ResolverBuilder) runtimeRanges(args map[string]any, queryName string, runtimeFields map[string]RuntimeField) map[string]types.Query {
+	ranges := make(map[string]types.Query)
+	for name, rf := range runtimeFields {
+		if !isRangeFilterField(rf.field(name)) {
+			continue
+		}
+		argName, ok := rb.names.graphQLName(argumentScope(queryName), name)
+		if !ok {
+			argName = name
+		}
+		if bounds, ok := args[argName].(map[string]any); ok && len(bounds) > 0 {
+			ranges[name] = runtimeRangeQuery(name, rf.Type, bounds)
+		}
+	}
+	return ranges
+}
+
+// runtimeRangeQuery creates the range query of a double or date runtime field
+func runtimeRangeQuery(name string, fieldType FieldType, bounds map[string]any) types.Query {
+	if fieldType == FieldTypeDate {
+		bound := func(key string) *string {
+			if v, ok := bounds[key].(string); ok {
+				return &v
+			}
+			return nil
+		}
+		return types.Query{Range: map[string]types.RangeQuery{name: types.DateRangeQuery{
+			Gte: bound("gte"), Gt: bound("gt"), Lte: bound("lte"), Lt: bound("lt"),
+		}}}
+	}
+
+	bound := func(key string) *types.Float64 {
+		if v, ok := bounds[key].(float64); ok {
+			f := types.Float64(v)
+			return &f
+		}
+		return nil
+	}
+	return types.Query{Range: map[string]types.RangeQuery{name: types.NumberRangeQuery{
+		Gte: bound("gte"), Gt: bound("gt"), Lte: bound("lte"), Lt: bound("lt"),
+	}}}
+}
@@ -0,0 +1,223 @@
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/reveald/reveald/v2"
+)
+
+func TestRuntimeFields(t *testing.T) {
+	mapping := IndexMapping{
+		IndexName: "products",
+		Properties: map[string]*Field{
+			"name":  {Name: "name", Type: FieldTypeKeyword},
+			"price": {Name: "price", Type: FieldTypeDouble},
+		},
+	}
+	queryConfig := &QueryConfig{
+		Mapping:  mapping,
+		Features: []reveald.Feature{&mockWrapperFeature{}},
+		RuntimeFields: map[string]RuntimeField{
+			"priceInclVat": {
+				Type:   FieldTypeDouble,
+				Script: "emit(doc['price'].value * params.vat)",
+				Params: map[string]any{"vat": 1.25},
+			},
+			"priceBand": {
+				Type:        FieldTypeKeyword,
+				Script:      "emit(doc['price'].value > 100 ? 'high' : 'low')",
+				Description: "Price band of the product",
+			},
+		},
+		ScriptFields: map[string]ScriptField{
+			"nameLength": {Type: FieldTypeLong, Script: "doc['name'].value.length()"},
+		},
+		EnableAggregations: true,
+		EnableSorting:      true,
+	}
+
+	backend := &recordingBackend{hits: []map[string]any{{"name": "Chair", "priceInclVat": 125.0}}}
+	api, err := New(backend, NewConfig(WithQuery("products", queryConfig)))
+	if err != nil {
+		t.Fatalf("Failed to create API: %v", err)
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("products", queryConfig)))
+		assertContains(t, sdl,
+			"priceInclVat: Float",
+			"\"\"\"Price band of the product\"\"\"\n  priceBand: String",
+			"nameLength: Long",
+			"priceBand: [String]",
+			"priceInclVat_asc",
+			"priceBand_desc",
+			"priceBand: [Bucket]",
+		)
+
+		// Script fields are only document fields
+		assertNotContains(t, sdl, "nameLength_asc", "nameLength: [Bucket]", "nameLength: Long, ")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := map[string]*QueryConfig{
+			"unsupported type": {RuntimeFields: map[string]RuntimeField{"a": {Type: FieldTypeText, Script: "emit('a')"}}},
+			"mapping field":    {RuntimeFields: map[string]RuntimeField{"price": {Type: FieldTypeDouble, Script: "emit(1.0)"}}},
+			"empty script":     {ScriptFields: map[string]ScriptField{"a": {Type: FieldTypeLong}}},
+			"both":             {RuntimeFields: map[string]RuntimeField{"a": {Type: FieldTypeLong, Script: "emit(1)"}}, ScriptFields: map[string]ScriptField{"a": {Type: FieldTypeLong, Script: "1"}}},
+		}
+
+		for name, queryConfig := range tests {
+			t.Run(name, func(t *testing.T) {
+				queryConfig.Mapping = mapping
+				if _, err := GenerateSchemaSDL(NewConfig(WithQuery("products", queryConfig))); err == nil {
+					t.Error("Expected an error")
+				}
+			})
+		}
+	})
+
+	t.Run("sent when selected", func(t *testing.T) {
+		data := runQuery(t, api, `{ products { hits { name priceInclVat } } }`)
+		body := backend.searchBody(t)
+		assertContains(t, body, `"runtime_mappings":{"priceInclVat":{"script":{"params":{"vat":1.25},"source":"emit(doc['price'].value * params.vat)"},"type":"double"}}`)
+
+		// Unselected computed fields are not sent
+		assertNotContains(t, body, "priceBand", "script_fields")
+
+		hits := data["products"].(map[string]any)["hits"].([]any)
+		if price := hits[0].(map[string]any)["priceInclVat"]; price != 125.0 {
+			t.Errorf("Expected runtime field value, got %v", price)
+		}
+	})
+
+	t.Run("sent when used", func(t *testing.T) {
+		runQuery(t, api, `{
+			products(priceBand: ["high"], sort: priceInclVat_desc) {
+				hits { name nameLength }
+				aggregations { priceBand { value count } }
+			}
+		}`)
+		assertContains(t, backend.searchBody(t),
+			`"terms":{"priceBand":["high"]}`,
+			`"priceBand":{"terms":{"field":"priceBand","size":10}}`,
+			`{"priceInclVat":{"order":"desc"}}`,
+			`"runtime_mappings":{"priceBand":`,
+			`"priceInclVat":{"script"`,
+			`"script_fields":{"nameLength":{"script":{"source":"doc['name'].value.length()"}}}`,
+		)
+	})
+
+	t.Run("apply computed fields", func(t *testing.T) {
+
+		var req search.Request
+		if err := json.Unmarshal([]byte(`{"sort": [{"priceBand": "asc"}]}`), &req); err != nil {
+			t.Fatalf("Failed to parse request: %v", err)
+		}
+		applyComputedFields(&req, queryConfig.RuntimeFields, queryConfig.ScriptFields, map[string]bool{"nameLength": true})
+
+		if _, ok := req.RuntimeMappings["priceBand"]; !ok || len(req.RuntimeMappings) != 1 {
+			t.Errorf("Expected the referenced runtime field only, got %v", req.RuntimeMappings)
+		}
+		if len(req.Fields) != 0 {
+			t.Errorf("Referenced runtime fields should not be fetched, got %v", req.Fields)
+		}
+		if _, ok := req.ScriptFields["nameLength"]; !ok || req.Source_ != true {
+			t.Errorf("Expected the selected script field with _source, got %v, %v", req.ScriptFields, req.Source_)
+		}
+	})
+
+	t.Run("referenced fields", func(t *testing.T) {
+
+		var req search.Request
+		body := `{
+			"query": {"bool": {"must": [
+				{"term": {"name": {"value": "priceBand"}}},
+				{"multi_match": {"query": "chair", "fields": ["name", "priceBand^2"]}}
+			]}},
+			"aggregations": {"prices": {"histogram": {"field": "priceInclVat", "interval": 10}}}
+		}`
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("Failed to parse request: %v", err)
+		}
+
+		referenced := referencedFields(&req, queryConfig.RuntimeFields)
+		if !referenced["priceBand"] || !referenced["priceInclVat"] || len(referenced) != 2 {
+			t.Errorf("Expected priceBand and priceInclVat, got %v", referenced)
+		}
+
+		// A value equal to a field name is not a reference
+		var termReq search.Request
+		if err := json.Unmarshal([]byte(`{"query": {"term": {"name": {"value": "priceBand"}}}}`), &termReq); err != nil {
+			t.Fatalf("Failed to parse request: %v", err)
+		}
+		if referenced := referencedFields(&termReq, queryConfig.RuntimeFields); len(referenced) != 0 {
+			t.Errorf("Expected no references, got %v", referenced)
+		}
+	})
+
+	t.Run("range filter", func(t *testing.T) {
+		assertContains(t, generateSDL(t, NewConfig(WithQuery("products", queryConfig))), "priceInclVat: FloatRangeFilterInput")
+
+		runQuery(t, api, `{ products(priceInclVat: {gte: 100, lt: 200}) { hits { name } } }`)
+		assertContains(t, backend.searchBody(t), `"range":{"priceInclVat":{"gte":100,"lt":200}}`, `"runtime_mappings":{"priceInclVat":`)
+	})
+
+	t.Run("feature used by", func(t *testing.T) {
+		request := reveald.NewRequest()
+
+		// Requests not using computed fields share the query's endpoint
+		if queryConfig.computedFieldFeature(map[string]bool{"name": true}, nil, nil).usedBy(request) {
+			t.Error("Expected the feature to be unused")
+		}
+		if !queryConfig.computedFieldFeature(map[string]bool{"nameLength": true}, nil, nil).usedBy(request) {
+			t.Error("Expected a selected script field to use the feature")
+		}
+		request.Append(reveald.NewParameter("sort", "priceInclVat-desc"))
+		if !queryConfig.computedFieldFeature(nil, nil, nil).usedBy(request) {
+			t.Error("Expected a sort on a runtime field to use the feature")
+		}
+	})
+
+	t.Run("shared document type", func(t *testing.T) {
+		other := *queryConfig
+		other.RuntimeFields = map[string]RuntimeField{"priceBand": {Type: FieldTypeLong, Script: "emit(1)"}}
+
+		_, err := GenerateSchemaSDL(NewConfig(WithQuery("products", queryConfig), WithQuery("cheapProducts", &other)))
+		if err == nil || !strings.Contains(err.Error(), "RuntimeFields") {
+			t.Errorf("Expected an error about the shared document type, got %v", err)
+		}
+
+		other.HitsTypeName = "CheapProduct"
+		if _, err := GenerateSchemaSDL(NewConfig(WithQuery("products", queryConfig), WithQuery("cheapProducts", &other))); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("precompiled schema", func(t *testing.T) {
+		config := NewConfig(WithPrecompiledQuery("productStats", &PrecompiledQueryConfig{
+			Index:         "products",
+			Mapping:       mapping,
+			QueryJSON:     `{"size": 10, "sort": [{"priceInclVat": "desc"}]}`,
+			RuntimeFields: queryConfig.RuntimeFields,
+			ScriptFields:  queryConfig.ScriptFields,
+		}))
+
+		assertContains(t, generateSDL(t, config), "priceInclVat: Float", "priceBand: String", "nameLength: Long")
+	})
+}
+
+func TestHitFieldValues(t *testing.T) {
+	values := hitFieldValues(map[string]json.RawMessage{
+		"single": json.RawMessage(`[1.5]`),
+		"many":   json.RawMessage(`["a","b"]`),
+	})
+	if values["single"] != 1.5 {
+		t.Errorf("Expected single value, got %v", values["single"])
+	}
+	if many, ok := values["many"].([]any); !ok || len(many) != 2 {
+		t.Errorf("Expected list of values, got %v", values["many"])
+	}
+}
@@ -0,0 +1,189 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Long is a 64-bit integer scalar used for ES long/unsigned_long fields and document counts
+// GraphQL Int is 32-bit, so epoch millis timestamps, large IDs and counts overflow it
+var Long = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Long",
+	Description: "The `Long` scalar type represents a 64-bit integer",
+	Serialize:   coerceLong,
+	ParseValue:  coerceLong,
+	ParseLiteral: func(valueAST ast.Value) any {
+		switch v := valueAST.(type) {
+		case *ast.IntValue:
+			return coerceLong(v.Value)
+		case *ast.StringValue:
+			return coerceLong(v.Value)
+		}
+		return nil
+	},
+})
+
+// DateTime is the scalar used for ES date/date_nanos fields
+// Output values are normalised to RFC 3339, inputs accept dates, epoch millis and ES date math ("now-7d/d")
+var DateTime = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "DateTime",
+	Description: "The `DateTime` scalar type represents a date and time in RFC 3339 format. Inputs also accept epoch millis and Elasticsearch date math (e.g., \"now-7d/d\")",
+	Serialize:   serializeDateTime,
+	ParseValue:  parseDateTimeInput,
+	ParseLiteral: func(valueAST ast.Value) any {
+		switch v := valueAST.(type) {
+		case *ast.StringValue:
+			return parseDateTimeInput(v.Value)
+		case *ast.IntValue:
+			return parseDateTimeInput(v.Value)
+		}
+		return nil
+	},
+})
+
+// DateRangeBound is the scalar of the bounds of date range queries
+// Bounds are DateTime inputs, or dates in the range's own format (e.g., "15/01/2024" with
+// format "dd/MM/yyyy") which are passed to Elasticsearch unparsed
+var DateRangeBound = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "DateRangeBound",
+	Description: "The `DateRangeBound` scalar type represents a bound of a date range: a DateTime, or a date in the format set on the range",
+	Serialize:   serializeDateTime,
+	ParseValue:  parseDateRangeBound,
+	ParseLiteral: func(valueAST ast.Value) any {
+		switch v := valueAST.(type) {
+		case *ast.StringValue:
+			return parseDateRangeBound(v.Value)
+		case *ast.IntValue:
+			return parseDateRangeBound(v.Value)
+		}
+		return nil
+	},
+})
+
+// JSON is the scalar used for objects without mapped properties (e.g., "enabled": false
+// or dynamic metadata), flattened fields and field types without a dedicated GraphQL type
+// Values are returned as-is, so objects and arrays serialize as JSON rather than Go map strings
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "The `JSON` scalar type represents arbitrary JSON values",
+	Serialize: func(value any) any {
+		return value
+	},
+	ParseValue: func(value any) any {
+		return value
+	},
+	ParseLiteral: parseJSONLiteral,
+})
+
+// maxExactInteger is the largest integer a float64 holds exactly (2^53)
+const maxExactInteger = 1 << 53
+
+// decodeSource decodes a document source, keeping integers a float64 cannot hold exactly
+// (e.g., long IDs above 2^53) as json.Number so Long fields return them unrounded
+// Other numbers are float64, as with json.Unmarshal
+func decodeSource(data []byte, doc *map[string]any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(doc); err != nil {
+		return err
+	}
+	exactNumbers(*doc)
+	return nil
+}
+
+// exactNumbers converts the json.Number values of a decoded value to float64, except for
+// integers outside the exact float64 range
+func exactNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = exactNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = exactNumbers(item)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			if i > maxExactInteger || i < -maxExactInteger {
+				return v
+			}
+		} else if _, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return v
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
+
+// coerceLong converts a value to int64 (or uint64 for unsigned_long values above MaxInt64)
+// Returns nil when the value is not a whole number
+func coerceLong(value any) any {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case uint:
+		return coerceLong(uint64(v))
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			return v
+		}
+		return int64(v)
+	case float32:
+		return coerceLong(float64(v))
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return nil
+		}
+		return int64(v)
+	case json.Number:
+		return coerceLong(string(v))
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return u
+		}
+		return nil
+	case *int64:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *int:
+		if v == nil {
+			return nil
+		}
+		return int64(*v)
+	}
+	return nil
+}
+
+// longType returns the scalar used for 64-bit values, Int when UseIntForLong is set
+func (sg *SchemaGenerator) longType() *graphql.Scalar {
+	if sg.config.UseIntForLong {
+		return graphql.Int
+	}
+	return Long
+}
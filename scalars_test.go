@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/reveald/reveald/v2"
+)
+
+func TestLongScalar(t *testing.T) {
+	mapping := IndexMapping{
+		IndexName: "events",
+		Properties: map[string]*Field{
+			"id":        {Name: "id", Type: FieldTypeKeyword},
+			"timestamp": {Name: "timestamp", Type: FieldTypeLong},
+			"bytes":     {Name: "bytes", Type: FieldTypeUnsignedLong},
+			"retries":   {Name: "retries", Type: FieldTypeInteger},
+		},
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithQuery("events", &QueryConfig{
+			Mapping:            mapping,
+			EnableAggregations: true,
+			AggregationFields:  []string{"id"},
+			EnablePagination:   true,
+		})))
+		assertContains(t, sdl, "scalar Long", "timestamp: Long", "bytes: Long", "retries: Int", "totalCount: Long", "count: Long")
+		assertNotContains(t, sdl, "totalCount: Int")
+
+		// Filter arguments on long fields use Long too
+		queryField := sdl[strings.Index(sdl, "events("):]
+		assertContains(t, queryField[:strings.Index(queryField, "\n")], "timestamp: Long", "bytes: Long", "retries: Int")
+	})
+
+	t.Run("int compatibility", func(t *testing.T) {
+		sdl := generateSDL(t, NewConfig(WithIntForLong(), WithQuery("events", &QueryConfig{Mapping: mapping, EnablePagination: true})))
+		assertNotContains(t, sdl, "Long")
+		assertContains(t, sdl, "timestamp: Int", "totalCount: Int")
+	})
+
+	t.Run("values above 2^53", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [
+			{"_index": "events", "_id": "e1", "_source": {"id": "e1", "timestamp": 9007199254740993, "bytes": 18446744073709551615, "retries": 3}}
+		]}`))
+		api := es.newAPI(t, es.backend(t), WithQuery("events", &QueryConfig{Mapping: mapping, Features: []reveald.Feature{&mockWrapperFeature{}}}))
+
+		data := runQuery(t, api, `{ events { hits { timestamp bytes retries } } }`)
+		hit := data["events"].(map[string]any)["hits"].([]any)[0].(map[string]any)
+		if hit["timestamp"] != int64(9007199254740993) {
+			t.Errorf("Expected the long unrounded, got %v", hit["timestamp"])
+		}
+		if hit["bytes"] != uint64(math.MaxUint64) {
+			t.Errorf("Expected the unsigned long unrounded, got %v", hit["bytes"])
+		}
+		if hit["retries"] != 3 {
+			t.Errorf("Expected integer fields unchanged, got %v", hit["retries"])
+		}
+	})
+}
+
+func TestCoerceLong(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected any
+	}{
+		{"int", 42, int64(42)},
+		{"int64 above Int range", int64(1700000000000), int64(1700000000000)},
+		{"whole float64", float64(1700000000000), int64(1700000000000)},
+		{"fractional float64", 1.5, nil},
+		{"json.Number", json.Number("9007199254740993"), int64(9007199254740993)},
+		{"string", "123", int64(123)},
+		{"unsigned above int64", uint64(math.MaxUint64), uint64(math.MaxUint64)},
+		{"invalid string", "abc", nil},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coerceLong(tt.value); got != tt.expected {
+				t.Errorf("coerceLong(%v) = %v (%T), expected %v (%T)", tt.value, got, got, tt.expected, tt.expected)
+			}
+		})
+	}
+}
@@ -16,16 +16,28 @@ type schemaRef struct {
 
 // SchemaGenerator generates GraphQL schemas from Elasticsearch mappings
 type SchemaGenerator struct {
-	config           *Config
-	typeCache        map[string]*graphql.Object
-	resolverBuilder  *ResolverBuilder
-	bucketType       *graphql.Object
-	paginationType   *graphql.Object
-	entityKeys       map[string][]string                       // Maps type name to entity key fields for RESOLVABLE entities (included in _Entity union)
-	sdlEntityKeys    map[string][]string                       // Maps type name to entity key fields for SDL @key directives (all entities, resolvable or not)
-	fieldDirectives  map[string]map[string]map[string]string   // Maps type name -> field name -> directive name -> directive args (empty string for directives without args like @external)
-	entityResolver   *EntityResolver                           // Resolver for _entities query
-	schemaRef        *schemaRef                                // Reference to the generated schema (for _service query)
+	config          *Config
+	typeCache       map[string]*graphql.Object
+	enumCache       map[string]*graphql.Enum        // Field enum types by name (see FieldEnum)
+	inputCache      map[string]*graphql.InputObject // Filter input types by name (e.g., MessageFilterInput)
+	resolverBuilder *ResolverBuilder
+	bucketType      *graphql.Object
+	paginationType  *graphql.Object
+	entityKeys      map[string][]string                     // Maps type name to entity key fields for RESOLVABLE entities (included in _Entity union)
+	sdlEntityKeys   map[string][]string                     // Maps type name to entity key fields for SDL @key directives (all entities, resolvable or not)
+	fieldDirectives map[string]map[string]map[string]string // Maps type name -> field name -> directive name -> directive args (empty string for directives without args like @external)
+	entityResolver  *EntityResolver                         // Resolver for _entities query
+	schemaRef       *schemaRef                              // Reference to the generated schema (for _service query)
+	docInterfaces   map[string][]*graphql.Interface         // Maps document type name to the interfaces it implements (multi-index queries)
+	extraTypes      []graphql.Type                          // Types not reachable from Query that must be added to the schema (e.g., interface implementations)
+	names           *nameRegistry                           // GraphQL names of ES fields, arguments and aggregations (shared with the resolvers)
+	docTypeOwners   map[string]docTypeOwner                 // Maps document type name to the query that generated it
+}
+
+// docTypeOwner is the query that generated a document type and the options it was generated with
+type docTypeOwner struct {
+	query   string
+	options string
 }
 
 // NewSchemaGenerator creates a new schema generator
@@ -33,11 +45,21 @@ func NewSchemaGenerator(config *Config, resolverBuilder *ResolverBuilder) *Schem
 	sg := &SchemaGenerator{
 		config:          config,
 		typeCache:       make(map[string]*graphql.Object),
+		enumCache:       make(map[string]*graphql.Enum),
+		inputCache:      make(map[string]*graphql.InputObject),
 		resolverBuilder: resolverBuilder,
 		entityKeys:      make(map[string][]string),
 		sdlEntityKeys:   make(map[string][]string),
 		fieldDirectives: make(map[string]map[string]map[string]string),
 		schemaRef:       &schemaRef{},
+		docInterfaces:   make(map[string][]*graphql.Interface),
+		names:           newNameRegistry(),
+		docTypeOwners:   make(map[string]docTypeOwner),
+	}
+
+	// Resolvers translate GraphQL names back to ES names with the generator's registry
+	if resolverBuilder != nil {
+		resolverBuilder.names = sg.names
 	}
 
 	// Initialize shared types
@@ -82,6 +104,7 @@ func NewSchemaGenerator(config *Config, resolverBuilder *ResolverBuilder) *Schem
 	// Initialize entity resolver if federation is enabled
 	if config.EnableFederation {
 		sg.entityResolver = NewEntityResolver(resolverBuilder.esClient, resolverBuilder.backend)
+		sg.entityResolver.names = sg.names
 	}
 
 	return sg
@@ -110,6 +133,25 @@ func (sg *SchemaGenerator) Generate() (graphql.Schema, error) {
 		queryFields[queryName] = field
 	}
 
+	// Add suggest queries
+	for queryName, queryConfig := range sg.config.SuggestQueries {
+		field, err := sg.generateSuggestQueryField(queryName, queryConfig)
+		if err != nil {
+			return graphql.Schema{}, fmt.Errorf("failed to generate suggest query %s: %w", queryName, err)
+		}
+		queryFields[queryName] = field
+	}
+
+	// Add relation, join, collapse and distance fields now that the document types of all queries exist
+	if err := sg.addRelationFields(); err != nil {
+		return graphql.Schema{}, fmt.Errorf("failed to generate relations: %w", err)
+	}
+	if err := sg.addJoinFields(); err != nil {
+		return graphql.Schema{}, fmt.Errorf("failed to generate join fields: %w", err)
+	}
+	sg.addCollapseFields()
+	sg.addDistanceFields()
+
 	// Add raw queries (backend-agnostic, custom resolver)
 	for queryName, queryConfig := range sg.config.RawQueries {
 		queryFields[queryName] = &graphql.Field{
@@ -131,9 +173,9 @@ func (sg *SchemaGenerator) Generate() (graphql.Schema, error) {
 		// Capture references for closures
 		schemaRef := sg.schemaRef
 		config := sg.config
-		sdlEntityKeys := sg.sdlEntityKeys       // All entities with @key directives
-		resolvableEntityKeys := sg.entityKeys   // Only resolvable entities
-		fieldDirectives := sg.fieldDirectives   // Field-level directives (e.g., @requires, @external)
+		sdlEntityKeys := sg.sdlEntityKeys     // All entities with @key directives
+		resolvableEntityKeys := sg.entityKeys // Only resolvable entities
+		fieldDirectives := sg.fieldDirectives // Field-level directives (e.g., @requires, @external)
 
 		// Add _service query
 		queryFields["_service"] = &graphql.Field{
@@ -225,6 +267,7 @@ func (sg *SchemaGenerator) Generate() (graphql.Schema, error) {
 	for _, customTypeWithKeys := range sg.config.CustomTypesWithKeys {
 		customTypes = append(customTypes, customTypeWithKeys.Type)
 	}
+	customTypes = append(customTypes, sg.extraTypes...)
 	if len(customTypes) > 0 {
 		schemaConfig.Types = customTypes
 	}
@@ -242,6 +285,11 @@ func (sg *SchemaGenerator) Generate() (graphql.Schema, error) {
 		)
 	}
 
+	// Report ES names that cannot be told apart once sanitised
+	if err := sg.names.err(); err != nil {
+		return graphql.Schema{}, fmt.Errorf("invalid GraphQL names: %w", err)
+	}
+
 	schema, err := graphql.NewSchema(schemaConfig)
 	if err != nil {
 		return schema, err
@@ -255,29 +303,88 @@ func (sg *SchemaGenerator) Generate() (graphql.Schema, error) {
 
 // generateQueryField generates a GraphQL field for a search query
 func (sg *SchemaGenerator) generateQueryField(queryName string, queryConfig *QueryConfig) (*graphql.Field, error) {
+	// Merge the mappings of all searched indices (a no-op for single-index queries)
+	mapping := queryConfig.GetMapping()
+
+	for _, path := range queryConfig.MultiFields {
+		if _, _, err := lookupMultiField(path, &mapping); err != nil {
+			return nil, fmt.Errorf("query %s: %w", queryName, err)
+		}
+	}
+	if err := validateCardinality(queryConfig.ObjectCardinality, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := validateFieldAliases(queryConfig.FieldAliases, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := sg.validateFieldEnums(queryConfig, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := validateComputedFields(queryConfig.RuntimeFields, queryConfig.ScriptFields, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := validateInnerHits(queryConfig.InnerHits, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := validateCollapseFields(queryConfig.CollapseFields, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := validateSpellCheck(queryConfig.SpellCheck, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := validateKNN(queryConfig.KNN, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := validateGeoFields(queryConfig.GeoFields, &mapping); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+
 	// Generate the result type for this query
-	resultType, err := sg.generateResultType(queryName, queryConfig, &queryConfig.Mapping)
+	resultType, err := sg.generateResultType(queryName, queryConfig, &mapping)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate arguments for the query
-	args := sg.generateQueryArguments(queryName, queryConfig, &queryConfig.Mapping)
+	args := sg.generateQueryArguments(queryName, queryConfig, &mapping)
+	if err := sg.addCollapseArgument(args, queryName, queryConfig); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := sg.addKNNArgument(args, queryName, queryConfig); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
+	if err := sg.addGeoArguments(args, queryName, queryConfig); err != nil {
+		return nil, fmt.Errorf("query %s: %w", queryName, err)
+	}
 
 	return &graphql.Field{
-		Type:        resultType,
-		Description: queryConfig.Description,
-		Args:        args,
-		Resolve:     sg.resolverBuilder.BuildResolver(queryName, queryConfig),
+		Type:              resultType,
+		Description:       queryConfig.Description,
+		DeprecationReason: mapping.DeprecationReason,
+		Args:              args,
+		Resolve:           sg.resolverBuilder.BuildResolver(queryName, queryConfig),
 	}, nil
 }
 
 // generateResultType creates the result type for a query
 func (sg *SchemaGenerator) generateResultType(queryName string, queryConfig *QueryConfig, mapping *IndexMapping) (*graphql.Object, error) {
-	// Determine the hits type - use custom HitsType if provided, otherwise generate document type
+	// Use custom result type name if provided, otherwise generate from query name
+	resultTypeName := resultTypeName(queryName, queryConfig)
+
+	// Get base name by removing "Result" suffix for related types
+	baseName := strings.TrimSuffix(resultTypeName, "Result")
+
+	// Determine the hits type - use custom HitsType if provided, an interface over one
+	// document type per index for multi-index queries, otherwise generate document type
 	var hitsType graphql.Output
 	if queryConfig.HitsType != nil {
 		hitsType = queryConfig.HitsType
+	} else if queryConfig.isMultiIndex() {
+		hitsInterface, err := sg.generateMultiIndexHitsType(queryName, queryConfig, baseName)
+		if err != nil {
+			return nil, err
+		}
+		hitsType = hitsInterface
 	} else {
 		docType, err := sg.generateDocumentType(queryName, queryConfig, mapping)
 		if err != nil {
@@ -286,26 +393,37 @@ func (sg *SchemaGenerator) generateResultType(queryName string, queryConfig *Que
 		hitsType = docType
 	}
 
-	// Use custom result type name if provided, otherwise generate from query name
-	resultTypeName := queryConfig.ResultTypeName
-	if resultTypeName == "" {
-		resultTypeName = fmt.Sprintf("%sResult", capitalize(queryName))
-	}
-
-	// Get base name by removing "Result" suffix for related types
-	baseName := strings.TrimSuffix(resultTypeName, "Result")
-
 	fields := graphql.Fields{
 		"hits": &graphql.Field{
 			Type:        graphql.NewList(hitsType),
 			Description: "The search results",
 		},
 		"totalCount": &graphql.Field{
-			Type:        graphql.Int,
+			Type:        sg.longType(),
 			Description: "Total number of hits",
 		},
 	}
 
+	// Add the group count of collapsed queries
+	if len(queryConfig.CollapseFields) > 0 {
+		fields["totalGroups"] = &graphql.Field{
+			Type:        sg.longType(),
+			Description: "Approximate number of collapse groups (only set when the query is collapsed)",
+		}
+		fields["cursor"] = &graphql.Field{
+			Type:        graphql.String,
+			Description: "Cursor after the last group, for the after argument of the next page (only set when the query is collapsed)",
+		}
+	}
+
+	// Add spelling suggestions for the search text
+	if queryConfig.SpellCheck != nil {
+		fields["suggestions"] = &graphql.Field{
+			Type:        graphql.NewList(SpellingSuggestion),
+			Description: "Corrections of the search text (\"did you mean\")",
+		}
+	}
+
 	// Add aggregations if enabled
 	if queryConfig.EnableAggregations {
 		aggType := sg.generateAggregationsType(baseName, queryConfig, mapping)
@@ -338,15 +456,15 @@ func (sg *SchemaGenerator) generateDocumentType(queryName string, queryConfig *Q
 	if queryConfig.HitsTypeName != "" {
 		typeName = queryConfig.HitsTypeName
 	} else {
-		indexName := mapping.IndexName
-		typeName = fmt.Sprintf("%sDocument", sanitizeTypeName(indexName))
+		typeName = documentTypeName(mapping)
 	}
 
-	// Check cache - multiple queries on same index share the same document type
-	if cachedType, ok := sg.typeCache[typeName]; ok {
-		return cachedType, nil
-	}
+	return sg.generateNamedDocumentType(typeName, queryName, queryConfig, mapping)
+}
 
+// generateDocumentFields converts the mapping properties to GraphQL fields,
+// applying the query's field filter and type overrides
+func (sg *SchemaGenerator) generateDocumentFields(typeName string, queryConfig *QueryConfig, mapping *IndexMapping) (graphql.Fields, error) {
 	fields := graphql.Fields{}
 
 	for fieldName, field := range mapping.Properties {
@@ -369,13 +487,57 @@ func (sg *SchemaGenerator) generateDocumentType(queryName string, queryConfig *Q
 		// If no override, convert from ES type
 		if gqlField == nil {
 			var err error
-			gqlField, err = sg.convertFieldToGraphQL(field)
+			gqlField, err = sg.convertDocumentField(field, mapping)
 			if err != nil {
 				return nil, fmt.Errorf("failed to convert field %s: %w", fieldName, err)
 			}
 		}
 
-		fields[fieldName] = gqlField
+		sg.registerField(fields, typeName, fieldName, field, gqlField)
+	}
+
+	// Multi-fields are exposed under their own name (e.g., name_keyword), skipping
+	// indices of a multi-index query that do not have them
+	for _, path := range queryConfig.MultiFields {
+		if _, _, err := lookupMultiField(path, mapping); err != nil {
+			continue
+		}
+		gqlField, err := sg.convertMultiField(path, mapping)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert multi-field %s: %w", path, err)
+		}
+		sg.registerField(fields, typeName, path, mapping.GetField(path), gqlField)
+	}
+
+	if err := sg.addComputedFields(fields, typeName, queryConfig.RuntimeFields, queryConfig.ScriptFields); err != nil {
+		return nil, err
+	}
+	if err := sg.addInnerHitsFields(fields, typeName, queryConfig.InnerHits, mapping); err != nil {
+		return nil, err
+	}
+
+	// Expose the source index so clients can tell hits from different indices apart
+	sg.names.reserve(typeName, indexFieldName)
+	fields[indexFieldName] = newIndexField()
+
+	return fields, nil
+}
+
+// generateNamedDocumentType creates (or returns the cached) document type with the given name
+func (sg *SchemaGenerator) generateNamedDocumentType(typeName string, queryName string, queryConfig *QueryConfig, mapping *IndexMapping) (*graphql.Object, error) {
+	// Check cache - multiple queries on same index share the same document type
+	// Queries configuring it differently would silently get the type of the first one
+	options := queryConfig.documentTypeOptions()
+	if cachedType, ok := sg.typeCache[typeName]; ok {
+		if owner, ok := sg.docTypeOwners[typeName]; ok && owner.options != options {
+			return nil, fmt.Errorf("query %s shares document type %s with query %s but has different FieldAliases, RuntimeFields, ScriptFields or InnerHits; set HitsTypeName to give it its own type", queryName, typeName, owner.query)
+		}
+		return cachedType, nil
+	}
+
+	fields, err := sg.generateDocumentFields(typeName, queryConfig, mapping)
+	if err != nil {
+		return nil, err
 	}
 
 	// Apply type extensions (custom fields)
@@ -395,9 +557,14 @@ func (sg *SchemaGenerator) generateDocumentType(queryName string, queryConfig *Q
 		}
 	}
 
+	// Interfaces are resolved lazily since multi-index queries register them after creating the type
 	docType := graphql.NewObject(graphql.ObjectConfig{
-		Name:   typeName,
-		Fields: fields,
+		Name:        typeName,
+		Description: mapping.Description,
+		Fields:      fields,
+		Interfaces: (graphql.InterfacesThunk)(func() []*graphql.Interface {
+			return sg.docInterfaces[typeName]
+		}),
 	})
 
 	// Register entity key fields if configured at query level
@@ -410,8 +577,12 @@ func (sg *SchemaGenerator) generateDocumentType(queryName string, queryConfig *Q
 		if sg.config.EnableFederation && sg.entityResolver != nil {
 			// Create reveald endpoint for this query
 			endpoint := reveald.NewEndpoint(sg.resolverBuilder.backend, reveald.WithIndices(mapping.IndexName))
-			if err := endpoint.Register(queryConfig.Features...); err == nil {
-				reader := NewArgumentReader(mapping)
+			features, err := queryConfig.features(mapping)
+			if err == nil {
+				err = endpoint.Register(features...)
+			}
+			if err == nil {
+				reader := NewArgumentReader(mapping).withNames(sg.names, queryName)
 				sg.entityResolver.RegisterEntityType(typeName, &EntityTypeMapping{
 					QueryName:       queryName,
 					QueryConfig:     queryConfig,
@@ -426,68 +597,125 @@ func (sg *SchemaGenerator) generateDocumentType(queryName string, queryConfig *Q
 	}
 
 	sg.typeCache[typeName] = docType
+	sg.docTypeOwners[typeName] = docTypeOwner{query: queryName, options: options}
 	return docType, nil
 }
 
 // convertFieldToGraphQL converts an ES field to a GraphQL field
 func (sg *SchemaGenerator) convertFieldToGraphQL(field *Field) (*graphql.Field, error) {
-	gqlType, err := sg.esTypeToGraphQLType(field, "")
+	return sg.convertFieldToGraphQLAt(field, "")
+}
+
+// convertFieldToGraphQLAt converts an ES field below parentPath to a GraphQL field
+// parentPath names the parent objects by their aliases (see objectTypePath)
+func (sg *SchemaGenerator) convertFieldToGraphQLAt(field *Field, parentPath string) (*graphql.Field, error) {
+	gqlType, err := sg.esTypeToGraphQLType(field, parentPath)
 	if err != nil {
 		return nil, err
 	}
 
-	return &graphql.Field{
+	gqlField := &graphql.Field{
 		Type: gqlType,
-	}, nil
+	}
+
+	// Single objects fail on documents holding several objects
+	if (field.Type == FieldTypeObject || field.Type == FieldTypeNested) && len(field.Properties) > 0 && !field.isList() {
+		gqlField.Resolve = singleObjectResolver(field)
+	}
+
+	// Date fields are parsed with the mapping's format and accept format/timeZone arguments
+	if gqlType == DateTime {
+		gqlField.Args = dateFieldArgs()
+		gqlField.Resolve = dateFieldResolver(field)
+	}
+
+	// geo_point values are normalised from any of the ES point formats
+	if gqlType == GeoPoint {
+		gqlField.Resolve = geoPointResolver(field)
+	}
+
+	// Join values are a relation name for parents and an object for children
+	if gqlType == JoinField {
+		gqlField.Resolve = joinFieldResolver(field)
+	}
+
+	// JSON fields accept a path argument to select a value inside the object
+	// (applied to every element for nested fields without properties)
+	if list, ok := gqlType.(*graphql.List); gqlType == JSON || ok && list.OfType == JSON {
+		gqlField.Args = jsonFieldArgs()
+		gqlField.Resolve = jsonFieldResolver(field)
+	}
+
+	return gqlField, nil
 }
 
 // esTypeToGraphQLType maps Elasticsearch types to GraphQL types
 func (sg *SchemaGenerator) esTypeToGraphQLType(field *Field, parentPath string) (graphql.Output, error) {
+	if len(field.EnumValues) > 0 {
+		return sg.enumType(field)
+	}
+
 	switch field.Type {
-	case FieldTypeText, FieldTypeKeyword:
+	case FieldTypeText, FieldTypeKeyword, FieldTypeMatchOnlyText, FieldTypeSearchAsYouType,
+		FieldTypeConstantKeyword, FieldTypeWildcard, FieldTypeIP, FieldTypeVersion:
+		return graphql.String, nil
+	case FieldTypeBinary:
+		// Binary values are Base64 strings
 		return graphql.String, nil
-	case FieldTypeLong, FieldTypeInteger, FieldTypeShort, FieldTypeByte:
+	case FieldTypeLong, FieldTypeUnsignedLong:
+		return sg.longType(), nil
+	case FieldTypeInteger, FieldTypeShort, FieldTypeByte:
 		return graphql.Int, nil
-	case FieldTypeDouble, FieldTypeFloat:
+	case FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
 		return graphql.Float, nil
 	case FieldTypeBoolean:
 		return graphql.Boolean, nil
-	case FieldTypeDate:
-		return graphql.String, nil // Dates as ISO8601 strings
+	case FieldTypeDate, FieldTypeDateNanos:
+		return DateTime, nil
+	case FieldTypeGeoPoint:
+		return GeoPoint, nil
+	case FieldTypeJoin:
+		return JoinField, nil
+	case FieldTypeDenseVector:
+		return graphql.NewList(graphql.Float), nil
+	case FieldTypeIntegerRange, FieldTypeLongRange, FieldTypeFloatRange, FieldTypeDoubleRange,
+		FieldTypeDateRange, FieldTypeIPRange:
+		return sg.rangeType(field.Type), nil
+	case FieldTypeFlattened, FieldTypeGeoShape:
+		// geo_shape values are GeoJSON (or WKT strings)
+		return JSON, nil
 	case FieldTypeObject, FieldTypeNested:
-		// Create nested object type
+		// Objects without mapped properties (dynamic or "enabled": false) have no known structure
 		if len(field.Properties) == 0 {
-			return graphql.String, nil // Generic object as JSON string
+			return objectFieldType(field, JSON), nil
 		}
 
 		// Create unique type name using parent path
-		typeName := capitalize(field.Name) + "Object"
+		// Named like the field, so naming strategies and aliases apply to the type too
+		fieldTypeName := capitalize(sanitizeFieldName(sg.graphQLName(field.Name, field)))
+		typeName := fieldTypeName + "Object"
 		if parentPath != "" {
 			// Sanitize parent path for GraphQL type name
-			sanitizedPath := strings.ReplaceAll(parentPath, ".", "_")
-			sanitizedPath = strings.ReplaceAll(sanitizedPath, "/", "_")
-			sanitizedPath = strings.ReplaceAll(sanitizedPath, "-", "_")
-			typeName = capitalize(sanitizedPath) + capitalize(field.Name) + "Object"
+			typeName = capitalize(sanitizeFieldName(sg.graphQLName(parentPath, nil))) + fieldTypeName + "Object"
 		}
 
 		// Check if we already created this type
 		if cachedType, ok := sg.typeCache[typeName]; ok {
-			// Always return as list for objects with properties
-			return graphql.NewList(cachedType), nil
+			return objectFieldType(field, cachedType), nil
 		}
 
 		objFields := graphql.Fields{}
-		childPath := field.Name
+		childPath := objectPathSegment(field)
 		if parentPath != "" {
-			childPath = parentPath + "." + field.Name
+			childPath = parentPath + "." + childPath
 		}
 
 		for propName, prop := range field.Properties {
-			gqlField, err := sg.esTypeToGraphQLType(prop, childPath)
+			gqlField, err := sg.convertFieldToGraphQLAt(prop, childPath)
 			if err != nil {
 				return nil, err
 			}
-			objFields[propName] = &graphql.Field{Type: gqlField}
+			sg.registerField(objFields, typeName, propName, prop, gqlField)
 		}
 
 		objType := graphql.NewObject(graphql.ObjectConfig{
@@ -497,19 +725,56 @@ func (sg *SchemaGenerator) esTypeToGraphQLType(field *Field, parentPath string)
 
 		sg.typeCache[typeName] = objType
 
-		// Always return as list for objects with properties
-		return graphql.NewList(objType), nil
+		return objectFieldType(field, objType), nil
 	default:
-		return graphql.String, nil // Default to string
+		// Types without a dedicated GraphQL type are passed through as JSON
+		return JSON, nil
 	}
 }
 
+// objectTypePath returns the path of an object field for naming types, with each object aliased
+// (e.g., "customer.address" is "client.address" when customer is aliased as client)
+func objectTypePath(mapping *IndexMapping, path string) string {
+	segments := strings.Split(path, ".")
+	esPath := ""
+	for i, segment := range segments {
+		if esPath != "" {
+			esPath += "."
+		}
+		esPath += segment
+		if field := mapping.GetField(esPath); field != nil {
+			segments[i] = objectPathSegment(field)
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// objectPathSegment returns the alias of a field, or its name when not aliased
+func objectPathSegment(field *Field) string {
+	if field.GraphQLName != "" {
+		return field.GraphQLName
+	}
+	return field.Name
+}
+
+// objectFieldType wraps the type of an object field in a list when the field holds several objects
+func objectFieldType(field *Field, objType graphql.Output) graphql.Output {
+	if field.isList() {
+		return graphql.NewList(objType)
+	}
+	return objType
+}
+
 // generateQueryArguments creates the arguments for a search query
 func (sg *SchemaGenerator) generateQueryArguments(queryName string, queryConfig *QueryConfig, mapping *IndexMapping) graphql.FieldConfigArgument {
 	args := graphql.FieldConfigArgument{}
+	scope := argumentScope(queryName)
 
 	// Add ES query/aggs arguments if EnableElasticQuerying is true
 	if queryConfig.EnableElasticQuerying {
+		for _, name := range []string{"query", "aggs", "timeZone"} {
+			sg.names.reserve(scope, name)
+		}
 		args["query"] = &graphql.ArgumentConfig{
 			Type:        createESQueryInputType(),
 			Description: "Elasticsearch query DSL",
@@ -518,6 +783,10 @@ func (sg *SchemaGenerator) generateQueryArguments(queryName string, queryConfig
 			Type:        graphql.NewList(createESAggInputType()),
 			Description: "Elasticsearch aggregations",
 		}
+		args["timeZone"] = &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Default time zone for date ranges and date histograms (e.g., \"Europe/Stockholm\")",
+		}
 	}
 
 	// Add common search arguments from mapping
@@ -527,32 +796,74 @@ func (sg *SchemaGenerator) generateQueryArguments(queryName string, queryConfig
 		}
 
 		// Only add filterable fields as arguments
-		if sg.isFilterableField(field) {
-			argType := sg.getFilterArgumentType(field)
-			// Convert dots to underscores for GraphQL field names
-			gqlFieldName := strings.ReplaceAll(fieldName, ".", "_")
+		// Aliases filter like their target, ES resolves the alias name in queries
+		target := resolveAliasField(field, mapping)
+		if sg.isFilterableField(target) {
+			argType := sg.getFilterArgumentType(target)
+			// Register the sanitised name so the argument reader can map it back to the ES field
+			gqlFieldName := sg.names.register(scope, fieldName, sg.graphQLName(fieldName, field))
 			args[gqlFieldName] = &graphql.ArgumentConfig{
-				Type: argType,
+				Type:        argType,
+				Description: describeArgument(target),
 			}
 		}
 	}
 
+	// Add arguments for the exposed multi-fields (e.g., name_keyword filters on name.keyword)
+	for _, path := range queryConfig.MultiFields {
+		field := mapping.GetField(path)
+		if field == nil || !sg.isFilterableField(field) {
+			continue
+		}
+		gqlFieldName := sg.names.register(scope, path, sg.graphQLName(path, field))
+		args[gqlFieldName] = &graphql.ArgumentConfig{
+			Type:        sg.getFilterArgumentType(field),
+			Description: describeArgument(field),
+		}
+	}
+
+	// Add arguments for the runtime fields that can be filtered on
+	// Double and date runtime fields are filtered on a range
+	for _, name := range sortedKeys(queryConfig.RuntimeFields) {
+		field := queryConfig.RuntimeFields[name].field(name)
+		var argType graphql.Input
+		switch {
+		case isRangeFilterField(field):
+			argType = sg.rangeFilterInputType(field)
+		case sg.isFilterableField(field):
+			argType = sg.getFilterArgumentType(field)
+		default:
+			continue
+		}
+		gqlFieldName := sg.names.register(scope, name, sg.graphQLName(name, field))
+		args[gqlFieldName] = &graphql.ArgumentConfig{
+			Type:        argType,
+			Description: describeArgument(field),
+		}
+	}
+
+	// Add hasChild/hasParent arguments for indices with a join field
+	sg.addJoinArguments(args, scope, queryConfig, mapping)
+
 	// Add arguments for auto-detected aggregation fields (like nested task filters)
 	// These may not exist in the mapping but should still be filterable
 	autoDetectedFields := extractAggregationFields(queryConfig.Features)
 	for _, fieldName := range autoDetectedFields {
-		gqlFieldName := strings.ReplaceAll(fieldName, ".", "_")
+		gqlFieldName := sg.names.register(scope, fieldName, sg.graphQLName(fieldName, mapping.GetField(fieldName)))
 		// Skip if already added from mapping
 		if _, exists := args[gqlFieldName]; !exists {
 			// Default to list of strings for virtual fields
 			args[gqlFieldName] = &graphql.ArgumentConfig{
-				Type: graphql.NewList(graphql.String),
+				Type:        graphql.NewList(graphql.String),
+				Description: describeArgument(mapping.GetField(fieldName)),
 			}
 		}
 	}
 
 	// Add pagination arguments
 	if queryConfig.EnablePagination {
+		sg.names.reserve(scope, "limit")
+		sg.names.reserve(scope, "offset")
 		args["limit"] = &graphql.ArgumentConfig{
 			Type: graphql.Int,
 		}
@@ -563,17 +874,16 @@ func (sg *SchemaGenerator) generateQueryArguments(queryName string, queryConfig
 
 	// Add sorting argument
 	if queryConfig.EnableSorting {
+		sg.names.reserve(scope, "sort")
 		// Try to extract sort options from features and create enum
 		sortOptions := extractSortOptions(queryConfig.Features)
+		sortOptions = append(sortOptions, multiFieldSortOptions(queryConfig.MultiFields, mapping)...)
+		sortOptions = append(sortOptions, runtimeFieldSortOptions(queryConfig.RuntimeFields)...)
 		if len(sortOptions) > 0 {
 			// Calculate base name for sort enum (same logic as result type)
-			resultTypeName := queryConfig.ResultTypeName
-			if resultTypeName == "" {
-				resultTypeName = fmt.Sprintf("%sResult", capitalize(queryName))
-			}
-			baseName := strings.TrimSuffix(resultTypeName, "Result")
+			baseName := strings.TrimSuffix(resultTypeName(queryName, queryConfig), "Result")
 
-			sortEnum := sg.createSortEnum(baseName, sortOptions)
+			sortEnum := sg.createSortEnum(baseName, sortOptions, mapping)
 			args["sort"] = &graphql.ArgumentConfig{
 				Type:        sortEnum,
 				Description: "Sort option",
@@ -790,18 +1100,18 @@ func extractAggregationFieldsFromValue(val reflect.Value, seenFields map[string]
 
 // createSortEnum creates a GraphQL enum type from sort options
 // baseName is the base name for the enum (e.g., "OrderSearch" for "OrderSearchSortOption")
-func (sg *SchemaGenerator) createSortEnum(baseName string, sortOptions []string) *graphql.Enum {
+func (sg *SchemaGenerator) createSortEnum(baseName string, sortOptions []string, mapping *IndexMapping) *graphql.Enum {
 	if len(sortOptions) == 0 {
 		return nil
 	}
 
 	enumValues := graphql.EnumValueConfigMap{}
 	for _, option := range sortOptions {
-		// Convert "lastCustomerUpdate-desc" to "lastCustomerUpdate_desc" for GraphQL enum
-		enumKey := strings.ReplaceAll(option, "-", "_")
+		enumKey := sg.sortEnumKey(option, mapping)
 		enumValues[enumKey] = &graphql.EnumValueConfig{
-			Value:       option, // The actual value passed to reveald
-			Description: option,
+			Value:             option, // The actual value passed to reveald
+			Description:       option,
+			DeprecationReason: sortOptionDeprecation(option, mapping),
 		}
 	}
 
@@ -825,7 +1135,7 @@ func (sg *SchemaGenerator) createBucketType() *graphql.Object {
 					Description: "The bucket value",
 				},
 				"count": &graphql.Field{
-					Type:        graphql.Int,
+					Type:        sg.longType(),
 					Description: "Number of documents in this bucket",
 				},
 				"filterValue": &graphql.Field{
@@ -855,12 +1165,43 @@ func (sg *SchemaGenerator) createPaginationType() *graphql.Object {
 				Type: graphql.Int,
 			},
 			"totalCount": &graphql.Field{
-				Type: graphql.Int,
+				Type: sg.longType(),
 			},
 		},
 	})
 }
 
+// sortOptionDeprecation returns the deprecation reason of the field a sort option sorts on
+func sortOptionDeprecation(option string, mapping *IndexMapping) string {
+	name, _ := splitSortOption(option)
+	if field := mapping.GetField(name); field != nil {
+		return field.DeprecationReason
+	}
+	return ""
+}
+
+// sortEnumKey converts a sort option to its enum value name
+// The field part is named like the field itself, e.g. "created_at-desc" becomes
+// "created_at_desc" or, with CamelCaseNaming, "createdAt_desc"
+func (sg *SchemaGenerator) sortEnumKey(option string, mapping *IndexMapping) string {
+	name, direction := splitSortOption(option)
+	if direction != "" {
+		direction = "_" + direction
+	}
+
+	return sanitizeFieldName(sg.graphQLName(name, mapping.GetField(name))) + direction
+}
+
+// splitSortOption splits a trailing "-asc" or "-desc" off a sort option
+func splitSortOption(option string) (string, string) {
+	if i := strings.LastIndex(option, "-"); i > 0 {
+		if suffix := option[i+1:]; suffix == "asc" || suffix == "desc" {
+			return option[:i], suffix
+		}
+	}
+	return option, ""
+}
+
 // generateAggregationsType creates the aggregations type
 // baseName is the base name for the type (e.g., "OrderSearch" for "OrderSearchAggregations")
 func (sg *SchemaGenerator) generateAggregationsType(baseName string, queryConfig *QueryConfig, mapping *IndexMapping) *graphql.Object {
@@ -882,6 +1223,18 @@ func (sg *SchemaGenerator) generateAggregationsType(baseName string, queryConfig
 		fieldsMap[field] = true
 	}
 
+	// Add exposed multi-fields that can be aggregated on
+	for _, path := range queryConfig.MultiFields {
+		if field := mapping.GetField(path); field != nil && isSortableMultiField(field) {
+			fieldsMap[path] = true
+		}
+	}
+
+	// Add runtime fields, aggregated with a terms aggregation when selected
+	for name := range queryConfig.RuntimeFields {
+		fieldsMap[name] = true
+	}
+
 	// Convert map to slice
 	var fieldsToUse []string
 	for field := range fieldsMap {
@@ -897,16 +1250,24 @@ func (sg *SchemaGenerator) generateAggregationsType(baseName string, queryConfig
 	// Add aggregation fields
 	for _, fieldName := range fieldsToUse {
 		isAutoDetected := autoDetectedSet[fieldName]
-		fieldExists := mapping.GetField(fieldName) != nil
+		field := mapping.GetField(fieldName)
+		if rf, ok := queryConfig.RuntimeFields[fieldName]; ok {
+			field = rf.field(fieldName)
+		}
 
 		// Trust auto-detected fields (features know what they create)
-		// OR fields that exist in mapping (for manual additions)
-		if isAutoDetected || fieldExists {
-			// Convert dots to underscores for GraphQL field names
-			gqlFieldName := strings.ReplaceAll(fieldName, ".", "_")
-			aggFields[gqlFieldName] = &graphql.Field{
+		// OR fields that exist in mapping or are runtime fields (for manual additions)
+		if isAutoDetected || field != nil {
+			// Register the sanitised name so results can be keyed by it
+			gqlFieldName := sg.names.register(aggregationsTypeName(baseName), fieldName, sg.graphQLName(fieldName, field))
+			aggField := &graphql.Field{
 				Type: graphql.NewList(sg.bucketType),
 			}
+			if field != nil {
+				aggField.Description = field.Description
+				aggField.DeprecationReason = field.DeprecationReason
+			}
+			aggFields[gqlFieldName] = aggField
 		}
 	}
 
@@ -915,7 +1276,7 @@ func (sg *SchemaGenerator) generateAggregationsType(baseName string, queryConfig
 	}
 
 	return graphql.NewObject(graphql.ObjectConfig{
-		Name:   fmt.Sprintf("%sAggregations", baseName),
+		Name:   aggregationsTypeName(baseName),
 		Fields: aggFields,
 	})
 }
@@ -928,9 +1289,17 @@ func (sg *SchemaGenerator) generatePaginationType() *graphql.Object {
 // isFilterableField determines if a field can be used for filtering
 func (sg *SchemaGenerator) isFilterableField(field *Field) bool {
 	switch field.Type {
-	case FieldTypeKeyword, FieldTypeBoolean, FieldTypeLong, FieldTypeInteger:
+	case FieldTypeKeyword, FieldTypeBoolean, FieldTypeConstantKeyword, FieldTypeWildcard, FieldTypeIP,
+		FieldTypeVersion:
+		return true
+	case FieldTypeLong, FieldTypeUnsignedLong, FieldTypeInteger, FieldTypeShort, FieldTypeByte,
+		FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
+		return true
+	case FieldTypeIntegerRange, FieldTypeLongRange, FieldTypeFloatRange, FieldTypeDoubleRange,
+		FieldTypeDateRange, FieldTypeIPRange:
+		// Range fields are filtered by a value the range must contain
 		return true
-	case FieldTypeText:
+	case FieldTypeText, FieldTypeMatchOnlyText, FieldTypeSearchAsYouType:
 		// Text fields are filterable if they have a keyword multi-field
 		_, hasKeyword := field.Fields["keyword"]
 		return hasKeyword
@@ -941,15 +1310,31 @@ func (sg *SchemaGenerator) isFilterableField(field *Field) bool {
 
 // getFilterArgumentType returns the GraphQL argument type for filtering
 func (sg *SchemaGenerator) getFilterArgumentType(field *Field) graphql.Input {
+	// Enums are validated when the query field is generated
+	if len(field.EnumValues) > 0 {
+		if enum, err := sg.enumType(field); err == nil {
+			return graphql.NewList(enum)
+		}
+	}
+
 	switch field.Type {
-	case FieldTypeText, FieldTypeKeyword:
+	case FieldTypeText, FieldTypeKeyword, FieldTypeMatchOnlyText, FieldTypeSearchAsYouType,
+		FieldTypeConstantKeyword, FieldTypeWildcard, FieldTypeVersion:
+		return graphql.NewList(graphql.String)
+	case FieldTypeIP:
+		// Values may be addresses or CIDR ranges (e.g., "10.0.0.0/8")
 		return graphql.NewList(graphql.String)
 	case FieldTypeBoolean:
 		return graphql.Boolean
-	case FieldTypeLong, FieldTypeInteger, FieldTypeShort, FieldTypeByte:
+	case FieldTypeLong, FieldTypeUnsignedLong:
+		return sg.longType()
+	case FieldTypeInteger, FieldTypeShort, FieldTypeByte:
 		return graphql.Int
-	case FieldTypeDouble, FieldTypeFloat:
+	case FieldTypeDouble, FieldTypeFloat, FieldTypeHalfFloat, FieldTypeScaledFloat:
 		return graphql.Float
+	case FieldTypeIntegerRange, FieldTypeLongRange, FieldTypeFloatRange, FieldTypeDoubleRange,
+		FieldTypeDateRange, FieldTypeIPRange:
+		return sg.rangeBoundType(field.Type)
 	default:
 		return graphql.String
 	}
@@ -997,8 +1382,43 @@ func capitalize(s string) string {
 }
 
 // getPrecompiledIndexNameForType extracts the primary index name from precompiled query config
+// Falls back to the first configured index (or pattern) when the mapping has no index name
 func (sg *SchemaGenerator) getPrecompiledIndexNameForType(queryConfig *PrecompiledQueryConfig) string {
-	return queryConfig.Mapping.IndexName
+	if queryConfig.Mapping.IndexName != "" {
+		return queryConfig.Mapping.IndexName
+	}
+	if indices := queryConfig.GetIndices(); len(indices) > 0 {
+		return indices[0]
+	}
+	return ""
+}
+
+// indexFieldName is the document field exposing the index a hit was read from
+const indexFieldName = "_index"
+
+// hitIDKey is the document key carrying the _id of a hit (not exposed in the schema, documents
+// may have an id field of their own)
+const hitIDKey = "_id"
+
+// newIndexField creates the field exposing the index a hit was read from
+func newIndexField() *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.String,
+		Description: "The index this document was read from",
+	}
+}
+
+// resultTypeName returns the name of a query's result type, ResultTypeName or "{QueryName}Result"
+func resultTypeName(queryName string, queryConfig *QueryConfig) string {
+	if queryConfig.ResultTypeName != "" {
+		return queryConfig.ResultTypeName
+	}
+	return fmt.Sprintf("%sResult", capitalize(queryName))
+}
+
+// aggregationsTypeName returns the name of a query's aggregations type
+func aggregationsTypeName(baseName string) string {
+	return fmt.Sprintf("%sAggregations", baseName)
 }
 
 // sanitizeTypeName converts index name to valid GraphQL type name
@@ -1006,10 +1426,10 @@ func (sg *SchemaGenerator) getPrecompiledIndexNameForType(queryConfig *Precompil
 //   - "test-leads" → "TestLeads"
 //   - "products" → "Products"
 //   - "cross-domain-search-leads" → "CrossDomainSearchLeads"
+//   - "leads-*" → "Leads"
 func sanitizeTypeName(indexName string) string {
-	// Replace hyphens and underscores with spaces for splitting
-	name := strings.ReplaceAll(indexName, "-", " ")
-	name = strings.ReplaceAll(name, "_", " ")
+	// Replace hyphens, underscores, dots and pattern characters with spaces for splitting
+	name := strings.NewReplacer("-", " ", "_", " ", ".", " ", "*", " ", ",", " ").Replace(indexName)
 
 	// Split into words and capitalize each
 	words := strings.Fields(name)
@@ -13,6 +13,11 @@ func (sg *SchemaGenerator) generatePrecompiledQueryField(queryName string, query
 		return nil, fmt.Errorf("invalid precompiled query config: %w", err)
 	}
 
+	mapping := queryConfig.GetMapping()
+	if err := validateComputedFields(queryConfig.RuntimeFields, queryConfig.ScriptFields, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid precompiled query config: %w", err)
+	}
+
 	// Load a sample query to ensure it works (this validates the file/builder at startup)
 	sampleArgs := queryConfig.SampleParameters
 	if sampleArgs == nil {
@@ -39,10 +44,11 @@ func (sg *SchemaGenerator) generatePrecompiledQueryField(queryName string, query
 	}
 
 	return &graphql.Field{
-		Type:        resultType,
-		Description: queryConfig.Description,
-		Args:        args,
-		Resolve:     sg.resolverBuilder.BuildPrecompiledResolver(queryName, queryConfig),
+		Type:              resultType,
+		Description:       queryConfig.Description,
+		DeprecationReason: mapping.DeprecationReason,
+		Args:              args,
+		Resolve:           sg.resolverBuilder.BuildPrecompiledResolver(queryName, queryConfig),
 	}, nil
 }
 
@@ -55,10 +61,14 @@ func (sg *SchemaGenerator) generateSimplePrecompiledResultType(queryName string,
 		return cachedType
 	}
 
+	mapping := queryConfig.GetMapping()
+
 	// Use custom type name if provided, otherwise use index name for document type
 	var docTypeName string
 	if queryConfig.HitsTypeName != "" {
 		docTypeName = queryConfig.HitsTypeName
+	} else if mapping.GraphQLName != "" {
+		docTypeName = documentTypeName(&mapping)
 	} else {
 		indexName := sg.getPrecompiledIndexNameForType(queryConfig)
 		docTypeName = fmt.Sprintf("%sDocument", sanitizeTypeName(indexName))
@@ -69,10 +79,10 @@ func (sg *SchemaGenerator) generateSimplePrecompiledResultType(queryName string,
 	if cached, ok := sg.typeCache[docTypeName]; ok {
 		docType = cached
 	} else {
-		// Generate full document type from mapping (same as regular queries)
+		// Generate full document type from the (merged) mapping, same as regular queries
 		fields := graphql.Fields{}
 
-		for fieldName, field := range queryConfig.Mapping.Properties {
+		for fieldName, field := range mapping.Properties {
 			// Apply field filter
 			if !sg.shouldIncludeField(fieldName, queryConfig.FieldFilter) {
 				continue
@@ -92,15 +102,23 @@ func (sg *SchemaGenerator) generateSimplePrecompiledResultType(queryName string,
 			// If no override, convert from ES type
 			if gqlField == nil {
 				var err error
-				gqlField, err = sg.convertFieldToGraphQL(field)
+				gqlField, err = sg.convertDocumentField(field, &mapping)
 				if err != nil {
 					return nil
 				}
 			}
 
-			fields[fieldName] = gqlField
+			sg.registerField(fields, docTypeName, fieldName, field, gqlField)
 		}
 
+		if err := sg.addComputedFields(fields, docTypeName, queryConfig.RuntimeFields, queryConfig.ScriptFields); err != nil {
+			return nil
+		}
+
+		// Expose the source index so clients can tell hits from different indices apart
+		sg.names.reserve(docTypeName, indexFieldName)
+		fields[indexFieldName] = newIndexField()
+
 		// Apply type extensions (custom fields)
 		for _, typeExt := range sg.config.TypeExtensions {
 			if typeExt.TypeName == docTypeName {
@@ -119,8 +137,9 @@ func (sg *SchemaGenerator) generateSimplePrecompiledResultType(queryName string,
 		}
 
 		docType = graphql.NewObject(graphql.ObjectConfig{
-			Name:   docTypeName,
-			Fields: fields,
+			Name:        docTypeName,
+			Description: mapping.Description,
+			Fields:      fields,
 		})
 		sg.typeCache[docTypeName] = docType
 
@@ -136,7 +155,8 @@ func (sg *SchemaGenerator) generateSimplePrecompiledResultType(queryName string,
 					QueryName:         queryName,
 					PrecompiledConfig: queryConfig,
 					UseFeatureFlow:    false,
-					Mapping:           &queryConfig.Mapping,
+					Mapping:           &mapping,
+					Indices:           queryConfig.GetIndices(),
 					EntityKeys:        queryConfig.EntityKeyFields,
 				})
 			}
@@ -145,7 +165,7 @@ func (sg *SchemaGenerator) generateSimplePrecompiledResultType(queryName string,
 
 	fields := graphql.Fields{
 		"totalCount": &graphql.Field{
-			Type:        graphql.Int,
+			Type:        sg.longType(),
 			Description: "Total number of hits",
 		},
 		"hits": &graphql.Field{
@@ -162,9 +182,8 @@ func (sg *SchemaGenerator) generateSimplePrecompiledResultType(queryName string,
 		}
 	} else {
 		// Fallback to generic aggregation types
-		initGenericAggregationTypes()
 		fields["aggregations"] = &graphql.Field{
-			Type:        graphql.NewList(GenericAggregationType),
+			Type:        graphql.NewList(sg.genericAggregationType()),
 			Description: "Aggregation results as array with dynamic aggregation names",
 		}
 	}
@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// selectedFields returns the ES names of the fields selected below a field of the query's
+// result type (e.g., "hits" or "aggregations"), following fragments
+// GraphQL names are translated with the names registered for the field's type (or, for
+// interfaces, the types implementing it)
+func (rb *ResolverBuilder) selectedFields(info graphql.ResolveInfo, resultField string) map[string]bool {
+	selected := make(map[string]bool)
+	forSelectedFields(info, resultField, func(scopes []string, selection *ast.Field) {
+		gqlName := selection.Name.Value
+		for _, scope := range scopes {
+			if esName, ok := rb.names.esName(scope, gqlName); ok {
+				selected[esName] = true
+				return
+			}
+		}
+		selected[gqlName] = true
+	})
+	return selected
+}
+
+// forSelectedFields calls fn for each field selected below a field of the query's result type,
+// with the names of the types the selected fields may belong to
+func forSelectedFields(info graphql.ResolveInfo, resultField string, fn func(scopes []string, selection *ast.Field)) {
+	result, ok := graphql.GetNamed(info.ReturnType).(*graphql.Object)
+	if !ok {
+		return
+	}
+	def, ok := result.Fields()[resultField]
+	if !ok {
+		return
+	}
+
+	var scopes []string
+	switch named := graphql.GetNamed(def.Type).(type) {
+	case *graphql.Object:
+		scopes = append(scopes, named.Name())
+	case *graphql.Interface, *graphql.Union:
+		for _, obj := range info.Schema.PossibleTypes(named.(graphql.Abstract)) {
+			scopes = append(scopes, obj.Name())
+		}
+	}
+
+	for _, field := range info.FieldASTs {
+		collectFields(field.SelectionSet, info.Fragments, func(child *ast.Field) {
+			if child.Name.Value != resultField {
+				return
+			}
+			collectFields(child.SelectionSet, info.Fragments, func(selection *ast.Field) {
+				fn(scopes, selection)
+			})
+		})
+	}
+}
+
+// selectsField reports whether a field of the query's result type (e.g., "totalCount") is selected
+func selectsField(info graphql.ResolveInfo, resultField string) bool {
+	selected := false
+	for _, field := range info.FieldASTs {
+		collectFields(field.SelectionSet, info.Fragments, func(child *ast.Field) {
+			if child.Name.Value == resultField {
+				selected = true
+			}
+		})
+	}
+	return selected
+}
+
+// argumentValue returns the value of a field argument in a selection, resolving variables
+// Returns nil when the argument is not set
+func argumentValue(selection *ast.Field, name string, variables map[string]any) any {
+	for _, arg := range selection.Arguments {
+		if arg.Name.Value == name {
+			return astValue(arg.Value, variables)
+		}
+	}
+	return nil
+}
+
+// astValue converts an argument value literal to a Go value
+func astValue(value ast.Value, variables map[string]any) any {
+	switch v := value.(type) {
+	case *ast.Variable:
+		return variables[v.Name.Value]
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			return nil
+		}
+		return n
+	case *ast.ListValue:
+		values := make([]any, len(v.Values))
+		for i, item := range v.Values {
+			values[i] = astValue(item, variables)
+		}
+		return values
+	default:
+		return value.GetValue()
+	}
+}
+
+// collectFields calls fn for each field of a selection set, including the fields of fragments
+func collectFields(set *ast.SelectionSet, fragments map[string]ast.Definition, fn func(*ast.Field)) {
+	if set == nil {
+		return
+	}
+
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			fn(s)
+		case *ast.InlineFragment:
+			collectFields(s.SelectionSet, fragments, fn)
+		case *ast.FragmentSpread:
+			if fragment, ok := fragments[s.Name.Value].(*ast.FragmentDefinition); ok {
+				collectFields(fragment.SelectionSet, fragments, fn)
+			}
+		}
+	}
+}
@@ -42,6 +42,13 @@ func New(backend reveald.Backend, config *Config, opts ...Option) (*GraphQLAPI,
 		opt(api)
 	}
 
+	// Sample the values of sampled field enums
+	if api.esClient != nil {
+		if err := SampleFieldEnums(context.Background(), api.esClient, config); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build the resolver
 	resolverBuilder := NewResolverBuilder(backend, api.esClient)
 
@@ -93,7 +100,7 @@ func (api *GraphQLAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute query with HTTP request in context
-	ctx := context.WithValue(r.Context(), httpRequestKey, r)
+	ctx := context.WithValue(NewRequestContext(r.Context()), httpRequestKey, r)
 	result := graphql.Do(graphql.Params{
 		Schema:         api.schema,
 		RequestString:  req.Query,
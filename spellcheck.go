@@ -0,0 +1,287 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+)
+
+// SpellCheckSuggester selects the ES suggester used for spelling suggestions
+type SpellCheckSuggester string
+
+const (
+	// PhraseSuggester corrects the whole search text (the default)
+	PhraseSuggester SpellCheckSuggester = "phrase"
+	// TermSuggester corrects each term of the search text on its own
+	TermSuggester SpellCheckSuggester = "term"
+)
+
+const (
+	// defaultSpellCheckSize is the number of spelling suggestions returned by default
+	defaultSpellCheckSize = 3
+
+	// spellCheckPrefix prefixes the names of the spelling suggesters, followed by the field
+	// (and ":<term index>" for term suggesters)
+	spellCheckPrefix = "_spellcheck:"
+)
+
+// SpellCheck configures "did you mean" suggestions for the search text of a query
+type SpellCheck struct {
+	// Fields are the text fields corrections are taken from
+	Fields []string
+
+	// Suggester is PhraseSuggester (default) or TermSuggester
+	Suggester SpellCheckSuggester
+
+	// Size is the maximum number of suggestions (default: 3)
+	Size int
+
+	// HighlightPreTag and HighlightPostTag wrap the corrected terms (default: "<em>" and "</em>")
+	HighlightPreTag  string
+	HighlightPostTag string
+}
+
+// SpellingSuggestion is a corrected search text
+var SpellingSuggestion = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SpellingSuggestion",
+	Description: "A corrected search text",
+	Fields: graphql.Fields{
+		"text": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The corrected search text",
+		},
+		"highlighted": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The corrected search text with the corrected terms wrapped in highlight tags",
+		},
+		"score": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "Score of the suggestion",
+		},
+	},
+})
+
+// size returns the maximum number of suggestions
+func (sc *SpellCheck) size() int {
+	if sc.Size > 0 {
+		return sc.Size
+	}
+	return defaultSpellCheckSize
+}
+
+// highlightTags returns the tags wrapping the corrected terms
+func (sc *SpellCheck) highlightTags() (string, string) {
+	if sc.HighlightPreTag == "" && sc.HighlightPostTag == "" {
+		return "<em>", "</em>"
+	}
+	return sc.HighlightPreTag, sc.HighlightPostTag
+}
+
+// validateSpellCheck checks that the spell check fields are text fields
+func validateSpellCheck(spellCheck *SpellCheck, mapping *IndexMapping) error {
+	if spellCheck == nil {
+		return nil
+	}
+	if len(spellCheck.Fields) == 0 {
+		return fmt.Errorf("spell check needs at least one field")
+	}
+	switch spellCheck.Suggester {
+	case "", PhraseSuggester, TermSuggester:
+	default:
+		return fmt.Errorf("unknown spell check suggester %q", spellCheck.Suggester)
+	}
+	for _, path := range spellCheck.Fields {
+		field := mapping.GetField(path)
+		if field == nil {
+			return fmt.Errorf("spell check field %s not found", path)
+		}
+		if !isTextFieldType(field.Type) {
+			return fmt.Errorf("spell check field %s must be a text field, got %s", path, field.Type)
+		}
+	}
+	return nil
+}
+
+// suggester returns the suggesters correcting a search text on each field
+// Term suggesters get one suggester per term of the text: the typed client only keeps the last
+// entry of a suggester's response, while ES returns one entry per term
+func (sc *SpellCheck) suggester(text string) *types.Suggester {
+	suggesters := make(map[string]types.FieldSuggester)
+	for _, field := range sc.Fields {
+		size := sc.size()
+		if sc.Suggester == TermSuggester {
+			for i, term := range strings.Fields(text) {
+				term := term
+				suggesters[fmt.Sprintf("%s%s:%d", spellCheckPrefix, field, i)] = types.FieldSuggester{
+					Text: &term,
+					Term: &types.TermSuggester{Field: field, Size: &size},
+				}
+			}
+			continue
+		}
+		pre, post := sc.highlightTags()
+		suggesters[spellCheckPrefix+field] = types.FieldSuggester{Phrase: &types.PhraseSuggester{
+			Field:     field,
+			Size:      &size,
+			Highlight: &types.PhraseSuggestHighlight{PreTag: pre, PostTag: post},
+		}}
+	}
+	if sc.Suggester == TermSuggester {
+		return &types.Suggester{Suggesters: suggesters}
+	}
+	return &types.Suggester{Text: &text, Suggesters: suggesters}
+}
+
+// spellingSuggestions reads the spelling suggestions of a response, merged across fields and
+// sorted by score. Searches with results get no suggestions.
+func (sc *SpellCheck) spellingSuggestions(resp *search.Response) []map[string]any {
+	if len(resp.Hits.Hits) > 0 || (resp.Hits.Total != nil && resp.Hits.Total.Value > 0) {
+		return []map[string]any{}
+	}
+
+	pre, post := sc.highlightTags()
+	best := make(map[string]map[string]any)
+	add := func(text, highlighted string, score float64) {
+		if existing, ok := best[text]; ok && existing["score"].(float64) >= score {
+			return
+		}
+		best[text] = map[string]any{"text": text, "highlighted": highlighted, "score": score}
+	}
+
+	// Term suggestions by field and term index
+	terms := make(map[string]map[int]*types.TermSuggest)
+	for name, suggests := range resp.Suggest {
+		if !strings.HasPrefix(name, spellCheckPrefix) {
+			continue
+		}
+		for _, suggest := range suggests {
+			switch s := suggest.(type) {
+			case *types.PhraseSuggest:
+				for _, option := range s.Options {
+					highlighted := option.Text
+					if option.Highlighted != nil {
+						highlighted = *option.Highlighted
+					}
+					add(option.Text, highlighted, float64(option.Score))
+				}
+			case *types.TermSuggest:
+				field, index, ok := strings.Cut(strings.TrimPrefix(name, spellCheckPrefix), ":")
+				i, err := strconv.Atoi(index)
+				if !ok || err != nil {
+					continue
+				}
+				if terms[field] == nil {
+					terms[field] = make(map[int]*types.TermSuggest)
+				}
+				terms[field][i] = s
+			}
+		}
+	}
+	for _, field := range sortedKeys(terms) {
+		indices := make([]int, 0, len(terms[field]))
+		for i := range terms[field] {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+
+		var fieldTerms []*types.TermSuggest
+		for _, i := range indices {
+			fieldTerms = append(fieldTerms, terms[field][i])
+		}
+		if text, highlighted, score, ok := correctTerms(fieldTerms, pre, post); ok {
+			add(text, highlighted, score)
+		}
+	}
+
+	suggestions := make([]map[string]any, 0, len(best))
+	for _, text := range sortedKeys(best) {
+		suggestions = append(suggestions, best[text])
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i]["score"].(float64) > suggestions[j]["score"].(float64)
+	})
+	if len(suggestions) > sc.size() {
+		suggestions = suggestions[:sc.size()]
+	}
+	return suggestions
+}
+
+// correctTerms builds a corrected search text from the term suggestions of its terms, replacing
+// each misspelled term by its best correction. The score is the average score of the corrections.
+func correctTerms(terms []*types.TermSuggest, pre, post string) (string, string, float64, bool) {
+	if len(terms) == 0 {
+		return "", "", 0, false
+	}
+
+	// Terms are listed in order of the search text and joined with spaces
+	var text, highlighted strings.Builder
+	var score float64
+	corrected := 0
+	for i, term := range terms {
+		if i > 0 {
+			text.WriteString(" ")
+			highlighted.WriteString(" ")
+		}
+		if len(term.Options) == 0 {
+			text.WriteString(term.Text)
+			highlighted.WriteString(term.Text)
+			continue
+		}
+		option := term.Options[0]
+		text.WriteString(option.Text)
+		highlighted.WriteString(pre + option.Text + post)
+		score += float64(option.Score)
+		corrected++
+	}
+	if corrected == 0 {
+		return "", "", 0, false
+	}
+	return text.String(), highlighted.String(), score / float64(corrected), true
+}
+
+// searchText returns the text of the full-text queries in a query (match, multi_match,
+// query_string, ...), ignoring must_not clauses
+func searchText(query *types.Query) string {
+	var texts []string
+	var walk func(query *types.Query)
+	walk = func(query *types.Query) {
+		if query == nil {
+			return
+		}
+		if query.Bool != nil {
+			for _, clauses := range [][]types.Query{query.Bool.Must, query.Bool.Filter, query.Bool.Should} {
+				for i := range clauses {
+					walk(&clauses[i])
+				}
+			}
+		}
+		if query.Nested != nil {
+			walk(&query.Nested.Query)
+		}
+		for _, field := range sortedKeys(query.Match) {
+			texts = append(texts, query.Match[field].Query)
+		}
+		for _, field := range sortedKeys(query.MatchPhrase) {
+			texts = append(texts, query.MatchPhrase[field].Query)
+		}
+		for _, field := range sortedKeys(query.MatchBoolPrefix) {
+			texts = append(texts, query.MatchBoolPrefix[field].Query)
+		}
+		if query.MultiMatch != nil {
+			texts = append(texts, query.MultiMatch.Query)
+		}
+		if query.QueryString != nil {
+			texts = append(texts, query.QueryString.Query)
+		}
+		if query.SimpleQueryString != nil {
+			texts = append(texts, query.SimpleQueryString.Query)
+		}
+	}
+	walk(query)
+	return strings.TrimSpace(strings.Join(texts, " "))
+}
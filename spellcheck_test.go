@@ -0,0 +1,117 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/reveald/reveald/v2"
+)
+
+// titleSearchFeature searches the title field like a search box feature
+type titleSearchFeature struct{}
+
+func (f *titleSearchFeature) Process(builder *reveald.QueryBuilder, next reveald.FeatureFunc) (*reveald.Result, error) {
+	builder.With(types.Query{Match: map[string]types.MatchQuery{"title": {Query: "iphnoe caes"}}})
+	return next(builder)
+}
+
+func TestSpellCheck(t *testing.T) {
+	queryConfig := &QueryConfig{
+		Mapping: IndexMapping{
+			IndexName: "products",
+			Properties: map[string]*Field{
+				"title": {Name: "title", Type: FieldTypeText},
+				"brand": {Name: "brand", Type: FieldTypeKeyword},
+			},
+		},
+		Features:              []reveald.Feature{&titleSearchFeature{}},
+		SpellCheck:            &SpellCheck{Fields: []string{"title"}, Suggester: PhraseSuggester},
+		EnableElasticQuerying: true,
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		assertContains(t, generateSDL(t, NewConfig(WithQuery("products", queryConfig))), "suggestions: [SpellingSuggestion]", "type SpellingSuggestion", "highlighted: String")
+
+		notText := *queryConfig
+		notText.SpellCheck = &SpellCheck{Fields: []string{"brand"}, Suggester: PhraseSuggester}
+		if _, err := GenerateSchemaSDL(NewConfig(WithQuery("products", &notText))); err == nil {
+			t.Error("Expected an error for a field that is not a text field")
+		}
+	})
+
+	t.Run("typed query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 0, "relation": "eq"}, "hits": []},
+			"suggest": {"phrase#_spellcheck:title": [{"text": "iphnoe", "offset": 0, "length": 6, "options": [
+				{"text": "iphone", "highlighted": "<em>iphone</em>", "score": 0.2},
+				{"text": "iphones", "highlighted": "<em>iphones</em>", "score": 0.4}
+			]}]}`))
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("products", queryConfig))
+
+		data := runQuery(t, api, `{ products(query: {match: {field: "title", query: "iphnoe"}}) { totalCount suggestions { text highlighted score } } }`)
+
+		// Suggestions are requested with the search
+		bodies := es.requests()
+		if len(bodies) != 1 {
+			t.Fatalf("Expected a single request, got %v", bodies)
+		}
+		assertContains(t, bodies[0], `"suggest":{"_spellcheck:title":{"phrase":{"field":"title","highlight":{"post_tag":"\u003c/em\u003e","pre_tag":"\u003cem\u003e"},"size":3}},"text":"iphnoe"}`)
+
+		suggestions := data["products"].(map[string]any)["suggestions"].([]any)
+		if len(suggestions) != 2 || suggestions[0].(map[string]any)["text"] != "iphones" {
+			t.Errorf("Expected suggestions sorted by score, got %v", suggestions)
+		}
+	})
+
+	t.Run("feature query", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 0, "relation": "eq"}, "hits": []},
+			"suggest": {
+				"term#_spellcheck:title:0": [{"text": "iphnoe", "offset": 0, "length": 6, "options": [{"text": "iphone", "score": 0.8, "freq": 12}]}],
+				"term#_spellcheck:title:1": [{"text": "caes", "offset": 0, "length": 4, "options": [{"text": "case", "score": 0.6, "freq": 4}]}]
+			}`))
+		backend := &recordingBackend{}
+		terms := *queryConfig
+		terms.SpellCheck = &SpellCheck{Fields: []string{"title"}, Suggester: TermSuggester}
+		api := es.newAPI(t, backend, WithQuery("products", &terms))
+
+		data := runQuery(t, api, `{ products { totalCount suggestions { text highlighted score } } }`)
+
+		bodies := es.requests()
+		if len(bodies) != 1 {
+			t.Fatalf("Expected a single request, got %v", bodies)
+		}
+		assertContains(t, bodies[0],
+			`"_spellcheck:title:0":{"term":{"field":"title","size":3},"text":"iphnoe"}`,
+			`"_spellcheck:title:1":{"term":{"field":"title","size":3},"text":"caes"}`,
+			`"match":{"title":{"query":"iphnoe caes"}}`,
+		)
+
+		// The suggesters are sent with the features' search instead of through the backend
+		if backend.search != nil {
+			t.Errorf("Expected no backend search, got %v", backend.search)
+		}
+
+		suggestion := data["products"].(map[string]any)["suggestions"].([]any)[0].(map[string]any)
+		if suggestion["text"] != "iphone case" || suggestion["highlighted"] != "<em>iphone</em> <em>case</em>" {
+			t.Errorf("Expected the corrected search text, got %v", suggestion)
+		}
+
+		// Suggestions are only requested when selected
+		runQuery(t, api, `{ products { totalCount } }`)
+		if len(es.requests()) != 1 {
+			t.Errorf("Expected no suggest request, got %v", es.requests())
+		}
+	})
+
+	t.Run("with results", func(t *testing.T) {
+		es := newFakeES(t, staticResponse(`"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [{"_index": "products", "_id": "p1", "_source": {"title": "iphone"}}]},
+			"suggest": {"phrase#_spellcheck:title": [{"text": "iphnoe", "offset": 0, "length": 6, "options": [{"text": "iphone", "score": 0.2}]}]}`))
+		api := es.newAPI(t, &recordingBackend{}, WithQuery("products", queryConfig))
+
+		data := runQuery(t, api, `{ products { totalCount suggestions { text } } }`)
+
+		// Only searches without results get suggestions
+		if suggestions := data["products"].(map[string]any)["suggestions"].([]any); len(suggestions) != 0 {
+			t.Errorf("Expected no suggestions for a search with results, got %v", suggestions)
+		}
+	})
+}
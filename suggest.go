@@ -0,0 +1,550 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"unicode"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/operator"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+	"github.com/graphql-go/graphql"
+)
+
+// SuggestMode selects how a suggest query matches the typed prefix
+type SuggestMode string
+
+const (
+	// SuggestCompletion uses the completion suggester on a completion field
+	SuggestCompletion SuggestMode = "completion"
+	// SuggestSearchAsYouType matches a search_as_you_type field and its shingle subfields
+	SuggestSearchAsYouType SuggestMode = "search_as_you_type"
+	// SuggestPrefix matches a text field indexed with an edge n-gram analyzer
+	SuggestPrefix SuggestMode = "prefix"
+)
+
+const (
+	// defaultSuggestSize is the number of suggestions returned by default
+	defaultSuggestSize = 5
+
+	// completionFilterFactor is how many more completions are requested when they are filtered
+	// by a root query, since the completion suggester cannot apply queries
+	completionFilterFactor = 3
+)
+
+// SuggestQueryConfig defines a suggestion (autocomplete) query on one field of an index
+//
+// Example:
+//
+//	config.AddSuggestQuery("productSuggest", &SuggestQueryConfig{
+//	    Index:   "products",
+//	    Mapping: mapping,
+//	    Field:   "name", // a completion, search_as_you_type or edge n-gram text field
+//	})
+//
+// This generates:
+//
+//	productSuggest(prefix: String!, size: Int = 5): [ProductSuggestSuggestion]
+//
+//	type ProductSuggestSuggestion {
+//	    text: String
+//	    highlighted: String
+//	    score: Float
+//	    document: ProductsDocument
+//	}
+type SuggestQueryConfig struct {
+	// Index is the Elasticsearch index to query
+	Index string
+
+	// Indices are multiple Elasticsearch indices to query
+	Indices []string
+
+	// Mapping is the Elasticsearch index mapping, used for the field and the document type
+	Mapping IndexMapping
+
+	// Field is the field suggestions are matched on and read from
+	Field string
+
+	// Mode selects the matching, by default from the field type: completion fields use the
+	// completion suggester, search_as_you_type fields their shingle subfields and other text
+	// fields SuggestPrefix (the field must be indexed with an edge n-gram analyzer)
+	Mode SuggestMode
+
+	// Size is the default number of suggestions (default: 5)
+	Size int
+
+	// HighlightPreTag and HighlightPostTag wrap the matched text (default: "<em>" and "</em>")
+	HighlightPreTag  string
+	HighlightPostTag string
+
+	// RootQuery is a base Elasticsearch query always applied to the suggested documents
+	RootQuery *types.Query
+
+	// RootQueryBuilder dynamically builds a root query from the HTTP request (e.g., tenant filtering)
+	// Completions are filtered by looking up the suggested documents with the root query
+	RootQueryBuilder RootQueryBuilder
+
+	// IndexResolver picks the indices to search per request
+	// When not set, GetIndices() is searched
+	IndexResolver IndexResolver
+
+	// FieldFilter allows specifying which fields to include/exclude from the document type
+	FieldFilter *FieldFilter
+
+	// HitsTypeName is an optional custom name for the document type
+	// If not provided, defaults to "{IndexName}Document"
+	HitsTypeName string
+
+	// Description is an optional description for the GraphQL schema
+	Description string
+}
+
+// GetIndices returns all indices configured for this query
+func (sc *SuggestQueryConfig) GetIndices() []string {
+	if len(sc.Indices) > 0 {
+		return sc.Indices
+	}
+	if sc.Index != "" {
+		return []string{sc.Index}
+	}
+	return []string{sc.Mapping.IndexName}
+}
+
+// mode returns the configured mode or the mode of the field type
+func (sc *SuggestQueryConfig) mode(field *Field) SuggestMode {
+	if sc.Mode != "" {
+		return sc.Mode
+	}
+	switch field.Type {
+	case FieldTypeCompletion:
+		return SuggestCompletion
+	case FieldTypeSearchAsYouType:
+		return SuggestSearchAsYouType
+	default:
+		return SuggestPrefix
+	}
+}
+
+// size returns the default number of suggestions
+func (sc *SuggestQueryConfig) size() int {
+	if sc.Size > 0 {
+		return sc.Size
+	}
+	return defaultSuggestSize
+}
+
+// highlightTags returns the tags wrapping the matched text
+func (sc *SuggestQueryConfig) highlightTags() (string, string) {
+	pre, post := sc.HighlightPreTag, sc.HighlightPostTag
+	if pre == "" && post == "" {
+		return "<em>", "</em>"
+	}
+	return pre, post
+}
+
+// Validate checks that an index is set and that the field exists and suits the mode
+func (sc *SuggestQueryConfig) Validate() error {
+	// An empty index name would search all indices
+	if slices.Contains(sc.GetIndices(), "") {
+		return fmt.Errorf("suggest query needs an index (Index, Indices or Mapping.IndexName)")
+	}
+
+	field := sc.Mapping.GetField(sc.Field)
+	if field == nil {
+		return fmt.Errorf("suggest field %s not found", sc.Field)
+	}
+
+	switch mode := sc.mode(field); mode {
+	case SuggestCompletion:
+		if field.Type != FieldTypeCompletion {
+			return fmt.Errorf("suggest field %s must be a completion field for mode %s, got %s", sc.Field, mode, field.Type)
+		}
+	case SuggestSearchAsYouType:
+		if field.Type != FieldTypeSearchAsYouType {
+			return fmt.Errorf("suggest field %s must be a search_as_you_type field for mode %s, got %s", sc.Field, mode, field.Type)
+		}
+	case SuggestPrefix:
+		if !isTextFieldType(field.Type) {
+			return fmt.Errorf("suggest field %s must be a text field for mode %s, got %s", sc.Field, mode, field.Type)
+		}
+	default:
+		return fmt.Errorf("unknown suggest mode %q", mode)
+	}
+	return nil
+}
+
+// generateSuggestQueryField generates a GraphQL field for a suggest query
+func (sg *SchemaGenerator) generateSuggestQueryField(queryName string, queryConfig *SuggestQueryConfig) (*graphql.Field, error) {
+	if err := queryConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid suggest query config: %w", err)
+	}
+
+	// Suggested documents share the document type of regular queries on the index
+	docConfig := &QueryConfig{
+		Mapping:      queryConfig.Mapping,
+		FieldFilter:  queryConfig.FieldFilter,
+		HitsTypeName: queryConfig.HitsTypeName,
+	}
+	docType, err := sg.generateDocumentType(queryName, docConfig, &queryConfig.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: capitalize(queryName) + "Suggestion",
+		Fields: graphql.Fields{
+			"text": &graphql.Field{
+				Type:        graphql.String,
+				Description: "The suggested text",
+			},
+			"highlighted": &graphql.Field{
+				Type:        graphql.String,
+				Description: "The suggested text with the matched part wrapped in highlight tags",
+			},
+			"score": &graphql.Field{
+				Type:        graphql.Float,
+				Description: "Relevance score of the suggestion",
+			},
+			"document": &graphql.Field{
+				Type:        docType,
+				Description: "The suggested document (only fetched when selected)",
+			},
+		},
+	})
+
+	return &graphql.Field{
+		Type:        graphql.NewList(suggestionType),
+		Description: queryConfig.Description,
+		Args: graphql.FieldConfigArgument{
+			"prefix": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The text typed so far",
+			},
+			"size": &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: queryConfig.size(),
+				Description:  "Maximum number of suggestions",
+			},
+		},
+		Resolve: sg.resolverBuilder.BuildSuggestResolver(queryName, queryConfig),
+	}, nil
+}
+
+// suggestRequest holds the per-request options of a suggest query
+type suggestRequest struct {
+	prefix       string
+	size         int
+	rootQuery    *types.Query // Static and dynamic root queries
+	withDocument bool
+	indices      []string
+}
+
+// BuildSuggestResolver creates a resolver function for a suggest query
+func (rb *ResolverBuilder) BuildSuggestResolver(queryName string, config *SuggestQueryConfig) graphql.FieldResolveFn {
+	mapping := config.Mapping
+	mode := config.mode(mapping.GetField(config.Field))
+
+	return func(params graphql.ResolveParams) (any, error) {
+		if rb.esClient == nil {
+			return nil, fmt.Errorf("suggest query %s requires an Elasticsearch client (WithESClient)", queryName)
+		}
+
+		httpReq, _ := getHTTPRequest(params)
+		indices, err := config.IndexResolver.resolve(httpReq, params.Args, config.GetIndices())
+		if err != nil {
+			return nil, err
+		}
+
+		// Build dynamic root query if RootQueryBuilder is defined
+		var dynamicRootQuery *types.Query
+		if config.RootQueryBuilder != nil {
+			if httpReq == nil {
+				return nil, fmt.Errorf("HTTP request not available in context")
+			}
+			dynamicRootQuery, err = config.RootQueryBuilder(httpReq)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build root query: %w", err)
+			}
+		}
+
+		req := suggestRequest{
+			prefix:       params.Args["prefix"].(string),
+			size:         config.size(),
+			rootQuery:    mergeQueries(config.RootQuery, dynamicRootQuery),
+			withDocument: selectsField(params.Info, "document"),
+			indices:      indices,
+		}
+		if size, ok := params.Args["size"].(int); ok && size >= 0 {
+			req.size = size
+		}
+		if req.prefix == "" || req.size == 0 {
+			return []map[string]any{}, nil
+		}
+
+		ctx := context.Background()
+		if params.Context != nil {
+			ctx = params.Context
+		}
+
+		var suggestions []map[string]any
+		if mode == SuggestCompletion {
+			suggestions, err = rb.suggestCompletions(ctx, config, req)
+		} else {
+			suggestions, err = rb.suggestMatches(ctx, config, mode, req)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("suggest failed: %w", err)
+		}
+
+		for _, suggestion := range suggestions {
+			if doc, ok := suggestion["document"].(map[string]any); ok {
+				normalizeObjectCardinality(doc, &mapping)
+			}
+		}
+		return suggestions, nil
+	}
+}
+
+// suggestCompletions returns the suggestions of the completion suggester
+// With a root query, more completions are requested and the suggested documents are looked up
+// with the root query, keeping only the matching ones. Duplicates are then skipped after the
+// filtering, ES could otherwise keep the completion of a document that is filtered out
+func (rb *ResolverBuilder) suggestCompletions(ctx context.Context, config *SuggestQueryConfig, req suggestRequest) ([]map[string]any, error) {
+	size := req.size
+	if req.rootQuery != nil {
+		size *= completionFilterFactor
+	}
+
+	zero := 0
+	prefix := req.prefix
+	skipDuplicates := !req.withDocument && req.rootQuery == nil
+	searchReq := &search.Request{
+		Size:    &zero,
+		Source_: req.withDocument,
+		Suggest: &types.Suggester{Suggesters: map[string]types.FieldSuggester{
+			"suggestions": {
+				Prefix: &prefix,
+				Completion: &types.CompletionSuggester{
+					Field:          config.Field,
+					Size:           &size,
+					SkipDuplicates: &skipDuplicates,
+				},
+			},
+		}},
+	}
+
+	resp, err := searchTyped(ctx, rb.esClient, req.indices, searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []types.CompletionSuggestOption
+	for _, suggest := range resp.Suggest["suggestions"] {
+		if completion, ok := suggest.(*types.CompletionSuggest); ok {
+			options = append(options, completion.Options...)
+		}
+	}
+
+	allowed, err := rb.allowedDocuments(ctx, req, options)
+	if err != nil {
+		return nil, err
+	}
+
+	pre, post := config.highlightTags()
+	suggestions := []map[string]any{}
+	seen := make(map[string]bool)
+	for _, option := range options {
+		if len(suggestions) == req.size {
+			break
+		}
+		if allowed != nil && (option.Id_ == nil || !allowed[*option.Id_]) {
+			continue
+		}
+		if allowed != nil && !req.withDocument {
+			if seen[option.Text] {
+				continue
+			}
+			seen[option.Text] = true
+		}
+
+		suggestion := map[string]any{
+			"text":        option.Text,
+			"highlighted": highlightPrefix(option.Text, req.prefix, pre, post),
+		}
+		if option.Score != nil {
+			suggestion["score"] = float64(*option.Score)
+		} else if option.Score_ != nil {
+			suggestion["score"] = float64(*option.Score_)
+		}
+		if req.withDocument {
+			suggestion["document"] = suggestedDocument(option.Source_, option.Id_)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// allowedDocuments returns the ids of the suggested documents matching the root query,
+// or nil when there is no root query
+func (rb *ResolverBuilder) allowedDocuments(ctx context.Context, req suggestRequest, options []types.CompletionSuggestOption) (map[string]bool, error) {
+	if req.rootQuery == nil {
+		return nil, nil
+	}
+
+	allowed := make(map[string]bool)
+	var ids []string
+	for _, option := range options {
+		if option.Id_ != nil {
+			ids = append(ids, *option.Id_)
+		}
+	}
+	if len(ids) == 0 {
+		return allowed, nil
+	}
+
+	size := len(ids)
+	resp, err := searchTyped(ctx, rb.esClient, req.indices, &search.Request{
+		Query:   mergeQueries(req.rootQuery, &types.Query{Ids: &types.IdsQuery{Values: ids}}),
+		Size:    &size,
+		Source_: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range resp.Hits.Hits {
+		if hit.Id_ != nil {
+			allowed[*hit.Id_] = true
+		}
+	}
+	return allowed, nil
+}
+
+// suggestMatches returns the suggestions of a prefix query on a search_as_you_type or
+// edge n-gram field, filtered by the root query
+func (rb *ResolverBuilder) suggestMatches(ctx context.Context, config *SuggestQueryConfig, mode SuggestMode, req suggestRequest) ([]map[string]any, error) {
+	var query *types.Query
+	if mode == SuggestSearchAsYouType {
+		boolPrefix := textquerytype.Boolprefix
+		query = &types.Query{MultiMatch: &types.MultiMatchQuery{
+			Query:  req.prefix,
+			Type:   &boolPrefix,
+			Fields: []string{config.Field, config.Field + "._2gram", config.Field + "._3gram"},
+		}}
+	} else {
+		and := operator.And
+		query = &types.Query{Match: map[string]types.MatchQuery{
+			config.Field: {Query: req.prefix, Operator: &and},
+		}}
+	}
+
+	pre, post := config.highlightTags()
+	size := req.size
+	searchReq := &search.Request{
+		Query: mergeQueries(req.rootQuery, query),
+		Size:  &size,
+		Highlight: &types.Highlight{
+			Fields:   map[string]types.HighlightField{config.Field: {}},
+			PreTags:  []string{pre},
+			PostTags: []string{post},
+		},
+	}
+	if !req.withDocument {
+		searchReq.Source_ = types.SourceFilter{Includes: []string{config.Field}}
+	}
+
+	resp, err := searchTyped(ctx, rb.esClient, req.indices, searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []map[string]any{}
+	for _, hit := range resp.Hits.Hits {
+		doc := suggestedDocument(hit.Source_, hit.Id_)
+		text := suggestionText(selectJSONPath(doc, config.Field))
+		suggestion := map[string]any{
+			"text":        text,
+			"highlighted": text,
+		}
+		if highlights := hit.Highlight[config.Field]; len(highlights) > 0 {
+			suggestion["highlighted"] = highlights[0]
+		}
+		if hit.Score_ != nil {
+			suggestion["score"] = float64(*hit.Score_)
+		}
+		if req.withDocument {
+			suggestion["document"] = doc
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// suggestedDocument decodes the source of a suggested document, setting id from _id
+// unless the source has an id
+func suggestedDocument(source json.RawMessage, id *string) map[string]any {
+	doc := make(map[string]any)
+	if len(source) > 0 {
+		_ = decodeSource(source, &doc)
+	}
+	if _, ok := doc["id"]; !ok && id != nil {
+		doc["id"] = *id
+	}
+	return doc
+}
+
+// suggestionText returns the text of a field value (the first value of an array)
+func suggestionText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) > 0 {
+			return suggestionText(v[0])
+		}
+	}
+	return ""
+}
+
+// highlightPrefix wraps the part of a completion matching the prefix in highlight tags
+// The completion suggester matches from the start of an input, ignoring case and (with a
+// folding analyzer) accents, so the prefix is compared rune by rune after folding both
+func highlightPrefix(text, prefix, pre, post string) string {
+	runes, prefixRunes := []rune(text), []rune(prefix)
+	if len(prefixRunes) == 0 || len(prefixRunes) > len(runes) {
+		return text
+	}
+	for i, r := range prefixRunes {
+		if foldRune(runes[i]) != foldRune(r) {
+			return text
+		}
+	}
+	n := len(prefixRunes)
+	return pre + string(runes[:n]) + post + string(runes[n:])
+}
+
+// foldedLetters maps accented Latin letters to their base letter, like ES asciifolding
+var foldedLetters = func() map[rune]rune {
+	folded := make(map[rune]rune)
+	for _, letters := range []string{
+		"aàáâãäåāăą", "cçćĉċč", "dďđ", "eèéêëēĕėęě", "gĝğġģ", "hĥħ", "iìíîïĩīĭįı", "jĵ", "kķ",
+		"lĺļľŀł", "nñńņň", "oòóôõöøōŏő", "rŕŗř", "sśŝşš", "tţťŧ", "uùúûüũūŭůűų", "wŵ", "yýÿŷ", "zźżž",
+	} {
+		base := []rune(letters)[0]
+		for _, r := range letters {
+			folded[r] = base
+		}
+	}
+	return folded
+}()
+
+// foldRune returns the lower case base letter of a rune
+func foldRune(r rune) rune {
+	r = unicode.ToLower(r)
+	if base, ok := foldedLetters[r]; ok {
+		return base
+	}
+	return r
+}
@@ -0,0 +1,247 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/graphql-go/graphql"
+)
+
+func suggestTestMapping() IndexMapping {
+	return IndexMapping{
+		IndexName: "products",
+		Properties: map[string]*Field{
+			"id":          {Name: "id", Type: FieldTypeKeyword},
+			"name":        {Name: "name", Type: FieldTypeText},
+			"nameSuggest": {Name: "nameSuggest", Type: FieldTypeCompletion},
+			"title":       {Name: "title", Type: FieldTypeSearchAsYouType},
+			"tenant":      {Name: "tenant", Type: FieldTypeKeyword},
+		},
+	}
+}
+
+func tenantRootQuery(r *http.Request) (*types.Query, error) {
+	return &types.Query{Term: map[string]types.TermQuery{"tenant": {Value: r.Header.Get("X-Tenant")}}}, nil
+}
+
+// suggestResponse answers completion suggests, root query lookups and prefix searches
+func suggestResponse(_, body string) string {
+	switch {
+	case strings.Contains(body, `"completion"`):
+		return `"hits": {"total": {"value": 0, "relation": "eq"}, "hits": []},
+			"suggest": {"completion#suggestions": [{"text": "iph", "offset": 0, "length": 3, "options": [
+				{"text": "iPhone 15", "_index": "products", "_id": "p1", "_score": 3.0, "_source": {"name": "iPhone 15"}},
+				{"text": "iPhone 14", "_index": "products", "_id": "p2", "_score": 2.0, "_source": {"name": "iPhone 14"}},
+				{"text": "iPhone SE", "_index": "products", "_id": "p3", "_score": 1.0, "_source": {"name": "iPhone SE"}}
+			]}]}`
+	case strings.Contains(body, `"ids"`):
+		return `"hits": {"total": {"value": 2, "relation": "eq"}, "hits": [
+			{"_index": "products", "_id": "p1", "_score": 1.0},
+			{"_index": "products", "_id": "p3", "_score": 1.0}
+		]}`
+	default:
+		return `"hits": {"total": {"value": 1, "relation": "eq"}, "hits": [
+			{"_index": "products", "_id": "p1", "_score": 2.5, "_source": {"title": "iPhone 15 Pro"},
+			 "highlight": {"title": ["<b>iPhone</b> 15 Pro"]}}
+		]}`
+	}
+}
+
+func tenantContext(tenant string) context.Context {
+	httpReq := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	httpReq.Header.Set("X-Tenant", tenant)
+	return context.WithValue(context.Background(), httpRequestKey, httpReq)
+}
+
+func TestSuggestSchema(t *testing.T) {
+	sdl, err := GenerateSchemaSDL(NewConfig(
+		WithSuggestQuery("productSuggest", &SuggestQueryConfig{Mapping: suggestTestMapping(), Field: "nameSuggest"}),
+		WithSuggestQuery("titleSuggest", &SuggestQueryConfig{Mapping: suggestTestMapping(), Field: "title"}),
+	))
+	if err != nil {
+		t.Fatalf("Failed to generate SDL: %v", err)
+	}
+
+	for _, e := range []string{"productSuggest(", "prefix: String!", "size: Int", "): [ProductSuggestSuggestion]", "highlighted: String", "score: Float", "document: ProductsDocument"} {
+		if !strings.Contains(sdl, e) {
+			t.Errorf("SDL should contain %q, got:\n%s", e, sdl)
+		}
+	}
+
+	tests := map[string]*SuggestQueryConfig{
+		"missing field":              {Mapping: suggestTestMapping(), Field: "missing"},
+		"keyword field":              {Mapping: suggestTestMapping(), Field: "tenant"},
+		"completion on a text field": {Mapping: suggestTestMapping(), Field: "name", Mode: SuggestCompletion},
+		"no index":                   {Mapping: IndexMapping{Properties: suggestTestMapping().Properties}, Field: "name"},
+	}
+	for name, queryConfig := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := GenerateSchemaSDL(NewConfig(WithSuggestQuery("suggest", queryConfig))); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestSuggestCompletion(t *testing.T) {
+	es := newFakeES(t, suggestResponse)
+	api := es.newAPI(t, &recordingBackend{}, WithSuggestQuery("productSuggest", &SuggestQueryConfig{
+		Mapping:          suggestTestMapping(),
+		Field:            "nameSuggest",
+		RootQueryBuilder: tenantRootQuery,
+	}))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		Context:       tenantContext("acme"),
+		RequestString: `{ productSuggest(prefix: "iph", size: 2) { text highlighted score document { id name } } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+
+	// The completions are filtered by looking up the suggested documents with the root query
+	bodies := es.requests()
+	if len(bodies) != 2 {
+		t.Fatalf("Expected a suggest and a lookup, got %v", bodies)
+	}
+	for _, e := range []string{`"prefix":"iph"`, `"field":"nameSuggest"`, `"size":6`} {
+		if !strings.Contains(bodies[0], e) {
+			t.Errorf("Suggest should contain %s, got %s", e, bodies[0])
+		}
+	}
+	if !strings.Contains(bodies[1], `"term":{"tenant":{"value":"acme"}}`) || !strings.Contains(bodies[1], `"ids":{"values":["p1","p2","p3"]}`) {
+		t.Errorf("Lookup should apply the root query to the suggested documents, got %s", bodies[1])
+	}
+
+	suggestions := result.Data.(map[string]any)["productSuggest"].([]any)
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions, got %v", suggestions)
+	}
+	first, second := suggestions[0].(map[string]any), suggestions[1].(map[string]any)
+	if first["highlighted"] != "<em>iPh</em>one 15" || first["score"] != 3.0 {
+		t.Errorf("Expected a highlighted suggestion, got %v", first)
+	}
+	if second["text"] != "iPhone SE" || second["document"].(map[string]any)["id"] != "p3" {
+		t.Errorf("Expected the documents of other tenants to be skipped, got %v", second)
+	}
+}
+
+func TestSuggestCompletionDuplicates(t *testing.T) {
+	es := newFakeES(t, func(_, body string) string {
+		if strings.Contains(body, `"completion"`) {
+			return `"hits": {"total": {"value": 0, "relation": "eq"}, "hits": []},
+				"suggest": {"completion#suggestions": [{"text": "iph", "offset": 0, "length": 3, "options": [
+					{"text": "iPhone 15", "_index": "products", "_id": "p1", "_score": 3.0},
+					{"text": "iPhone 15", "_index": "products", "_id": "p2", "_score": 2.0},
+					{"text": "iPhone 15", "_index": "products", "_id": "p3", "_score": 1.0}
+				]}]}`
+		}
+		return `"hits": {"total": {"value": 2, "relation": "eq"}, "hits": [
+			{"_index": "products", "_id": "p2", "_score": 1.0},
+			{"_index": "products", "_id": "p3", "_score": 1.0}
+		]}`
+	})
+	api := es.newAPI(t, &recordingBackend{}, WithSuggestQuery("productSuggest", &SuggestQueryConfig{
+		Mapping:          suggestTestMapping(),
+		Field:            "nameSuggest",
+		RootQueryBuilder: tenantRootQuery,
+	}))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		Context:       tenantContext("acme"),
+		RequestString: `{ productSuggest(prefix: "iph", size: 2) { text } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+
+	// ES must not skip duplicates before the root query filters the documents of other tenants
+	bodies := es.requests()
+	if len(bodies) != 2 || !strings.Contains(bodies[0], `"skip_duplicates":false`) {
+		t.Fatalf("Expected a suggest without skip_duplicates and a lookup, got %v", bodies)
+	}
+
+	suggestions := result.Data.(map[string]any)["productSuggest"].([]any)
+	if len(suggestions) != 1 || suggestions[0].(map[string]any)["text"] != "iPhone 15" {
+		t.Errorf("Expected the duplicates to be skipped after filtering, got %v", suggestions)
+	}
+}
+
+func TestSuggestSearchAsYouType(t *testing.T) {
+	es := newFakeES(t, suggestResponse)
+	api := es.newAPI(t, &recordingBackend{}, WithSuggestQuery("titleSuggest", &SuggestQueryConfig{
+		Mapping:          suggestTestMapping(),
+		Field:            "title",
+		HighlightPreTag:  "<b>",
+		HighlightPostTag: "</b>",
+		RootQueryBuilder: tenantRootQuery,
+	}))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		Context:       tenantContext("acme"),
+		RequestString: `{ titleSuggest(prefix: "iphone 1") { text highlighted } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+
+	body := es.requests()[0]
+	for _, e := range []string{
+		`"fields":["title","title._2gram","title._3gram"]`,
+		`"type":"bool_prefix"`,
+		`"term":{"tenant":{"value":"acme"}}`,
+		`"pre_tags":["\u003cb\u003e"]`,
+		`"_source":{"includes":["title"]}`,
+	} {
+		if !strings.Contains(body, e) {
+			t.Errorf("Request should contain %s, got %s", e, body)
+		}
+	}
+
+	suggestion := result.Data.(map[string]any)["titleSuggest"].([]any)[0].(map[string]any)
+	if suggestion["text"] != "iPhone 15 Pro" || suggestion["highlighted"] != "<b>iPhone</b> 15 Pro" {
+		t.Errorf("Expected a highlighted suggestion, got %v", suggestion)
+	}
+}
+
+func TestSuggestPrefix(t *testing.T) {
+	es := newFakeES(t, suggestResponse)
+	api := es.newAPI(t, &recordingBackend{}, WithSuggestQuery("nameSuggest", &SuggestQueryConfig{
+		Mapping: suggestTestMapping(),
+		Field:   "name",
+	}))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        api.GetSchema(),
+		RequestString: `{ nameSuggest(prefix: "iph") { text } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+	if !strings.Contains(es.requests()[0], `"match":{"name":{"operator":"and","query":"iph"}}`) {
+		t.Errorf("Expected a match on the edge n-gram field, got %s", es.requests()[0])
+	}
+}
+
+func TestHighlightPrefix(t *testing.T) {
+	tests := []struct{ text, prefix, expected string }{
+		{"iPhone 15", "iph", "<em>iPh</em>one 15"},
+		{"Malmö", "malmo", "<em>Malmö</em>"},
+		{"Ängelholm", "ang", "<em>Äng</em>elholm"},
+		{"Örebro", "öre", "<em>Öre</em>bro"},
+		{"Göteborg", "gu", "Göteborg"},
+		{"Ö", "ör", "Ö"},
+	}
+	for _, tt := range tests {
+		if highlighted := highlightPrefix(tt.text, tt.prefix, "<em>", "</em>"); highlighted != tt.expected {
+			t.Errorf("highlightPrefix(%q, %q) = %q, expected %q", tt.text, tt.prefix, highlighted, tt.expected)
+		}
+	}
+}
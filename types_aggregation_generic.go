@@ -36,13 +36,18 @@ func initGenericAggregationTypes() {
 	}
 	genericTypesInitialized = true
 
+	GenericBucketType, GenericAggregationType, StatsValuesType = newGenericAggregationTypes(Long)
+}
+
+// newGenericAggregationTypes creates the generic aggregation types with the given type for counts
+func newGenericAggregationTypes(countType graphql.Output) (bucketType, aggregationType, statsType *graphql.Object) {
 	// Initialize StatsValuesType first (no dependencies)
-	StatsValuesType = graphql.NewObject(graphql.ObjectConfig{
+	statsType = graphql.NewObject(graphql.ObjectConfig{
 		Name:        "StatsValues",
 		Description: "Statistics aggregation values",
 		Fields: graphql.Fields{
 			"count": &graphql.Field{
-				Type:        graphql.Int,
+				Type:        countType,
 				Description: "Number of values",
 			},
 			"min": &graphql.Field{
@@ -65,7 +70,7 @@ func initGenericAggregationTypes() {
 	})
 
 	// Initialize GenericAggregationType (will reference GenericBucketType in thunk)
-	GenericAggregationType = graphql.NewObject(graphql.ObjectConfig{
+	aggregationType = graphql.NewObject(graphql.ObjectConfig{
 		Name:        "GenericAggregation",
 		Description: "A generic aggregation result that can represent any Elasticsearch aggregation type",
 		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
@@ -76,7 +81,7 @@ func initGenericAggregationTypes() {
 				},
 				// For bucketing aggregations (terms, histogram, date_histogram, etc.)
 				"buckets": &graphql.Field{
-					Type:        graphql.NewList(GenericBucketType),
+					Type:        graphql.NewList(bucketType),
 					Description: "Buckets for terms, histogram, or date_histogram aggregations",
 				},
 				// For metric aggregations
@@ -86,17 +91,17 @@ func initGenericAggregationTypes() {
 				},
 				// For stats aggregation
 				"stats": &graphql.Field{
-					Type:        StatsValuesType,
+					Type:        statsType,
 					Description: "Statistics values (for stats aggregation)",
 				},
 				// For filter/filters aggregation doc count
 				"doc_count": &graphql.Field{
-					Type:        graphql.Int,
+					Type:        countType,
 					Description: "Document count (for filter aggregation)",
 				},
 				// For nested sub-aggregations in filter/filters
 				"sub_aggregations": &graphql.Field{
-					Type:        graphql.NewList(GenericAggregationType),
+					Type:        graphql.NewList(aggregationType),
 					Description: "Sub-aggregations (for filter, filters, nested aggregations)",
 				},
 			}
@@ -104,7 +109,7 @@ func initGenericAggregationTypes() {
 	})
 
 	// Initialize GenericBucketType last (references GenericAggregationType)
-	GenericBucketType = graphql.NewObject(graphql.ObjectConfig{
+	bucketType = graphql.NewObject(graphql.ObjectConfig{
 		Name:        "GenericBucket",
 		Description: "A bucket from any bucketing aggregation (terms, histogram, date_histogram, etc.)",
 		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
@@ -114,14 +119,46 @@ func initGenericAggregationTypes() {
 					Description: "The bucket key",
 				},
 				"doc_count": &graphql.Field{
-					Type:        graphql.Int,
+					Type:        countType,
 					Description: "Number of documents in this bucket",
 				},
 				"sub_aggregations": &graphql.Field{
-					Type:        graphql.NewList(GenericAggregationType),
+					Type:        graphql.NewList(aggregationType),
 					Description: "Nested aggregations within this bucket",
 				},
 			}
 		}),
 	})
+
+	return bucketType, aggregationType, statsType
+}
+
+// genericAggregationType returns the generic aggregation type matching the configured count type
+func (sg *SchemaGenerator) genericAggregationType() *graphql.Object {
+	_, aggregationType, _ := sg.genericAggregationTypes()
+	return aggregationType
+}
+
+// statsValuesType returns the stats values type matching the configured count type
+func (sg *SchemaGenerator) statsValuesType() *graphql.Object {
+	_, _, statsType := sg.genericAggregationTypes()
+	return statsType
+}
+
+// genericAggregationTypes returns the shared generic types, or Int-based copies when UseIntForLong is set
+func (sg *SchemaGenerator) genericAggregationTypes() (bucketType, aggregationType, statsType *graphql.Object) {
+	if !sg.config.UseIntForLong {
+		initGenericAggregationTypes()
+		return GenericBucketType, GenericAggregationType, StatsValuesType
+	}
+
+	if cached, ok := sg.typeCache["GenericAggregation"]; ok {
+		return sg.typeCache["GenericBucket"], cached, sg.typeCache["StatsValues"]
+	}
+
+	bucketType, aggregationType, statsType = newGenericAggregationTypes(graphql.Int)
+	sg.typeCache["GenericBucket"] = bucketType
+	sg.typeCache["GenericAggregation"] = aggregationType
+	sg.typeCache["StatsValues"] = statsType
+	return bucketType, aggregationType, statsType
 }
@@ -2,7 +2,6 @@ package graphql
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/graphql-go/graphql"
@@ -28,11 +27,13 @@ import (
 // - Terms: Generates type with buckets array
 // - DateHistogram: Generates type with buckets array
 // - Histogram: Generates type with buckets array
+// - GeohashGrid, GeotileGrid: Generate type with buckets array keyed by cell
 // - Filters: Generates type with named filter fields (not array)
 // - Filter: Generates type with doc_count + nested aggregations
 // - Nested: Similar to Filter
 // - Metric aggregations (Avg, Sum, Min, Max, Cardinality): Return scalar values
 // - Stats: Returns StatsValues object
+// - GeoCentroid: Returns GeoCentroid object
 
 // generateTypedAggregationsType creates a strongly-typed aggregations object from ES aggregation definitions
 func (sg *SchemaGenerator) generateTypedAggregationsType(queryName string, aggs map[string]types.Aggregations) *graphql.Object {
@@ -49,13 +50,12 @@ func (sg *SchemaGenerator) generateTypedAggregationsType(queryName string, aggs
 
 	fields := graphql.Fields{}
 	for aggName, aggDef := range aggs {
-		fieldName := sanitizeFieldName(aggName)
 		fieldType := sg.generateAggregationType(queryName, aggName, aggDef, "")
 		if fieldType != nil {
-			fields[fieldName] = &graphql.Field{
+			sg.registerField(fields, typeName, aggName, nil, &graphql.Field{
 				Type:        fieldType,
 				Description: fmt.Sprintf("Aggregation: %s", aggName),
-			}
+			})
 		}
 	}
 
@@ -90,6 +90,10 @@ func (sg *SchemaGenerator) generateAggregationType(
 		return sg.generateDateHistogramAggType(queryName, typePath, aggDef)
 	} else if aggDef.Histogram != nil {
 		return sg.generateHistogramAggType(queryName, typePath, aggDef)
+	} else if aggDef.GeohashGrid != nil {
+		return sg.generateGeoGridAggType(queryName, typePath, aggDef, "Geohash grid aggregation result")
+	} else if aggDef.GeotileGrid != nil {
+		return sg.generateGeoGridAggType(queryName, typePath, aggDef, "Geotile grid aggregation result")
 	} else if aggDef.Filters != nil {
 		return sg.generateFiltersAggType(queryName, typePath, aggDef)
 	} else if aggDef.Filter != nil {
@@ -100,16 +104,16 @@ func (sg *SchemaGenerator) generateAggregationType(
 		// Metric aggregations return scalar values
 		return graphql.Float
 	} else if aggDef.Cardinality != nil {
-		return graphql.Int
+		return sg.longType()
 	} else if aggDef.Stats != nil {
 		// Reuse existing StatsValuesType
-		initGenericAggregationTypes()
-		return StatsValuesType
+		return sg.statsValuesType()
+	} else if aggDef.GeoCentroid != nil {
+		return sg.geoCentroidType()
 	}
 
 	// Fallback to generic type for unknown aggregation types
-	initGenericAggregationTypes()
-	return GenericAggregationType
+	return sg.genericAggregationType()
 }
 
 // generateTermsAggType generates a type for Terms aggregation
@@ -205,6 +209,38 @@ func (sg *SchemaGenerator) generateHistogramAggType(
 	return histogramType
 }
 
+// generateGeoGridAggType generates a type for GeohashGrid and GeotileGrid aggregations
+func (sg *SchemaGenerator) generateGeoGridAggType(
+	queryName string,
+	typePath string,
+	aggDef types.Aggregations,
+	description string,
+) *graphql.Object {
+	typeName := fmt.Sprintf("%s%s", capitalize(queryName), typePath)
+
+	// Check cache
+	if cachedType, ok := sg.typeCache[typeName]; ok {
+		return cachedType
+	}
+
+	// Create bucket type for this grid aggregation
+	bucketType := sg.generateBucketType(queryName, typePath, aggDef.Aggregations)
+
+	gridType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        typeName,
+		Description: description,
+		Fields: graphql.Fields{
+			"buckets": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bucketType))),
+				Description: "Buckets grouped by grid cell (geohash or zoom/x/y tile key)",
+			},
+		},
+	})
+
+	sg.typeCache[typeName] = gridType
+	return gridType
+}
+
 // generateBucketType generates a bucket type for bucketing aggregations
 func (sg *SchemaGenerator) generateBucketType(
 	queryName string,
@@ -224,20 +260,19 @@ func (sg *SchemaGenerator) generateBucketType(
 			Description: "The bucket key value",
 		},
 		"doc_count": &graphql.Field{
-			Type:        graphql.NewNonNull(graphql.Int),
+			Type:        graphql.NewNonNull(sg.longType()),
 			Description: "Number of documents in this bucket",
 		},
 	}
 
 	// Add nested aggregation fields
 	for nestedName, nestedDef := range nestedAggs {
-		fieldName := sanitizeFieldName(nestedName)
 		fieldType := sg.generateAggregationType(queryName, nestedName, nestedDef, typePath)
 		if fieldType != nil {
-			fields[fieldName] = &graphql.Field{
+			sg.registerField(fields, typeName, nestedName, nil, &graphql.Field{
 				Type:        fieldType,
 				Description: fmt.Sprintf("Nested aggregation: %s", nestedName),
-			}
+			})
 		}
 	}
 
@@ -280,20 +315,19 @@ func (sg *SchemaGenerator) generateFiltersAggType(
 			} else {
 				bucketFields := graphql.Fields{
 					"doc_count": &graphql.Field{
-						Type:        graphql.NewNonNull(graphql.Int),
+						Type:        graphql.NewNonNull(sg.longType()),
 						Description: "Number of documents matching this filter",
 					},
 				}
 
 				// Add nested aggregation fields
 				for nestedName, nestedDef := range aggDef.Aggregations {
-					fieldName := sanitizeFieldName(nestedName)
 					fieldType := sg.generateAggregationType(queryName, nestedName, nestedDef, typePath)
 					if fieldType != nil {
-						bucketFields[fieldName] = &graphql.Field{
+						sg.registerField(bucketFields, bucketTypeName, nestedName, nil, &graphql.Field{
 							Type:        fieldType,
 							Description: fmt.Sprintf("Nested aggregation: %s", nestedName),
-						}
+						})
 					}
 				}
 
@@ -308,19 +342,17 @@ func (sg *SchemaGenerator) generateFiltersAggType(
 
 			// Add a field for each named filter
 			for filterName := range filterMap {
-				fieldName := sanitizeFieldName(filterName)
-				fields[fieldName] = &graphql.Field{
+				sg.registerField(fields, typeName, filterName, nil, &graphql.Field{
 					Type:        bucketType,
 					Description: fmt.Sprintf("Filter: %s", filterName),
-				}
+				})
 			}
 		}
 	}
 
 	if len(fields) == 0 {
 		// Fallback if no filters defined or if filters are array-based
-		initGenericAggregationTypes()
-		return GenericAggregationType
+		return sg.genericAggregationType()
 	}
 
 	filtersType := graphql.NewObject(graphql.ObjectConfig{
@@ -348,20 +380,19 @@ func (sg *SchemaGenerator) generateFilterAggType(
 
 	fields := graphql.Fields{
 		"doc_count": &graphql.Field{
-			Type:        graphql.NewNonNull(graphql.Int),
+			Type:        graphql.NewNonNull(sg.longType()),
 			Description: "Number of documents matching this filter",
 		},
 	}
 
 	// Add nested aggregation fields
 	for nestedName, nestedDef := range aggDef.Aggregations {
-		fieldName := sanitizeFieldName(nestedName)
 		fieldType := sg.generateAggregationType(queryName, nestedName, nestedDef, typePath)
 		if fieldType != nil {
-			fields[fieldName] = &graphql.Field{
+			sg.registerField(fields, typeName, nestedName, nil, &graphql.Field{
 				Type:        fieldType,
 				Description: fmt.Sprintf("Nested aggregation: %s", nestedName),
-			}
+			})
 		}
 	}
 
@@ -390,20 +421,19 @@ func (sg *SchemaGenerator) generateNestedAggType(
 
 	fields := graphql.Fields{
 		"doc_count": &graphql.Field{
-			Type:        graphql.NewNonNull(graphql.Int),
+			Type:        graphql.NewNonNull(sg.longType()),
 			Description: "Number of nested documents",
 		},
 	}
 
 	// Add nested aggregation fields
 	for nestedName, nestedDef := range aggDef.Aggregations {
-		fieldName := sanitizeFieldName(nestedName)
 		fieldType := sg.generateAggregationType(queryName, nestedName, nestedDef, typePath)
 		if fieldType != nil {
-			fields[fieldName] = &graphql.Field{
+			sg.registerField(fields, typeName, nestedName, nil, &graphql.Field{
 				Type:        fieldType,
 				Description: fmt.Sprintf("Nested aggregation: %s", nestedName),
-			}
+			})
 		}
 	}
 
@@ -417,20 +447,6 @@ func (sg *SchemaGenerator) generateNestedAggType(
 	return nestedType
 }
 
-// sanitizeFieldName converts aggregation names to valid GraphQL field names
-func sanitizeFieldName(name string) string {
-	// Replace dots and hyphens with underscores
-	result := strings.ReplaceAll(name, ".", "_")
-	result = strings.ReplaceAll(result, "-", "_")
-
-	// Ensure it starts with a letter or underscore
-	if len(result) > 0 && !isLetter(rune(result[0])) && result[0] != '_' {
-		result = "_" + result
-	}
-
-	return result
-}
-
 // isLetter checks if a rune is a letter
 func isLetter(r rune) bool {
 	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
@@ -9,12 +9,16 @@ type ESQueryInput struct {
 	MatchPhrase *ESMatchPhraseQueryInput
 	MultiMatch *ESMultiMatchQueryInput
 	Range      *ESRangeQueryInput
+	DateRange  *ESDateRangeQueryInput
 	Bool       *ESBoolQueryInput
 	Exists     *ESExistsQueryInput
 	Nested     *ESNestedQueryInput
 	Prefix     *ESPrefixQueryInput
 	Wildcard   *ESWildcardQueryInput
 	QueryString *ESQueryStringInput
+	GeoDistance *ESGeoDistanceQueryInput
+	GeoBoundingBox *ESGeoBoundingBoxQueryInput
+	GeoPolygon *ESGeoPolygonQueryInput
 }
 
 // ESTermQueryInput represents a term query
@@ -57,6 +61,18 @@ type ESRangeQueryInput struct {
 	Lt    *float64 // less than
 }
 
+// ESDateRangeQueryInput represents a range query on a date field
+// Bounds accept dates, epoch millis and ES date math (e.g., "now-7d/d"), or dates in Format
+type ESDateRangeQueryInput struct {
+	Field    string
+	Gte      *string
+	Gt       *string
+	Lte      *string
+	Lt       *string
+	Format   *string // date format of the bounds
+	TimeZone *string // time zone for bounds without an offset and for date math rounding
+}
+
 // ESBoolQueryInput represents a bool query
 type ESBoolQueryInput struct {
 	Must    []*ESQueryInput
@@ -96,6 +112,32 @@ type ESQueryStringInput struct {
 	DefaultOperator *string // AND or OR
 }
 
+// ESGeoPointInput represents a geo point
+type ESGeoPointInput struct {
+	Lat float64
+	Lon float64
+}
+
+// ESGeoDistanceQueryInput represents a geo_distance query
+type ESGeoDistanceQueryInput struct {
+	Field    string
+	Origin   ESGeoPointInput
+	Distance string // distance with a unit (e.g., "50km")
+}
+
+// ESGeoBoundingBoxQueryInput represents a geo_bounding_box query
+type ESGeoBoundingBoxQueryInput struct {
+	Field       string
+	TopLeft     ESGeoPointInput
+	BottomRight ESGeoPointInput
+}
+
+// ESGeoPolygonQueryInput represents a polygon filter on a geo_point field
+type ESGeoPolygonQueryInput struct {
+	Field  string
+	Points []ESGeoPointInput
+}
+
 // ESAggInput represents an Elasticsearch aggregation input for GraphQL
 type ESAggInput struct {
 	Name           string
@@ -111,6 +153,9 @@ type ESAggInput struct {
 	Cardinality    *ESCardinalityAggInput
 	Nested         *ESNestedAggInput
 	Filter         *ESFilterAggInput
+	GeohashGrid    *ESGeoGridAggInput
+	GeotileGrid    *ESGeoGridAggInput
+	GeoCentroid    *ESGeoCentroidAggInput
 	Aggs           []*ESAggInput // sub-aggregations
 }
 
@@ -134,6 +179,7 @@ type ESDateHistogramAggInput struct {
 	FixedInterval    *string // 30s, 1h, etc.
 	Format           *string
 	MinDocCount      *int
+	TimeZone         *string // time zone for bucketing (e.g., "Europe/Stockholm")
 }
 
 // ESHistogramAggInput represents a histogram aggregation
@@ -194,3 +240,15 @@ type ESNestedAggInput struct {
 type ESFilterAggInput struct {
 	Query *ESQueryInput
 }
+
+// ESGeoGridAggInput represents a geohash_grid or geotile_grid aggregation
+type ESGeoGridAggInput struct {
+	Field     string
+	Precision *int // geohash length (1-12) or tile zoom level (0-29)
+	Size      *int
+}
+
+// ESGeoCentroidAggInput represents a geo_centroid aggregation
+type ESGeoCentroidAggInput struct {
+	Field string
+}
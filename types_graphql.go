@@ -49,6 +49,20 @@ func createESQueryInputType() *graphql.InputObject {
 					},
 				}),
 			},
+			"dateRange": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewInputObject(graphql.InputObjectConfig{
+					Name: "ESDateRangeQueryInput",
+					Fields: graphql.InputObjectConfigFieldMap{
+						"field":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+						"gte":      &graphql.InputObjectFieldConfig{Type: DateRangeBound},
+						"gt":       &graphql.InputObjectFieldConfig{Type: DateRangeBound},
+						"lte":      &graphql.InputObjectFieldConfig{Type: DateRangeBound},
+						"lt":       &graphql.InputObjectFieldConfig{Type: DateRangeBound},
+						"format":   &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Date format of the bounds (e.g., \"dd/MM/yyyy\")"},
+						"timeZone": &graphql.InputObjectFieldConfig{Type: graphql.String},
+					},
+				}),
+			},
 			"bool": &graphql.InputObjectFieldConfig{
 				Type: graphql.NewInputObject(graphql.InputObjectConfig{
 					Name: "ESBoolQueryInput",
@@ -99,6 +113,9 @@ func createESQueryInputType() *graphql.InputObject {
 					},
 				}),
 			},
+			"geoDistance":    &graphql.InputObjectFieldConfig{Type: GeoDistanceQueryInput},
+			"geoBoundingBox": &graphql.InputObjectFieldConfig{Type: GeoBoundingBoxQueryInput},
+			"geoPolygon":     &graphql.InputObjectFieldConfig{Type: GeoPolygonQueryInput},
 		},
 	})
 
@@ -133,6 +150,7 @@ func createESAggInputType() *graphql.InputObject {
 							"fixedInterval":    &graphql.InputObjectFieldConfig{Type: graphql.String},
 							"format":           &graphql.InputObjectFieldConfig{Type: graphql.String},
 							"minDocCount":      &graphql.InputObjectFieldConfig{Type: graphql.Int},
+							"timeZone":         &graphql.InputObjectFieldConfig{Type: graphql.String},
 						},
 					}),
 				},
@@ -193,6 +211,34 @@ func createESAggInputType() *graphql.InputObject {
 						},
 					}),
 				},
+				"geohashGrid": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewInputObject(graphql.InputObjectConfig{
+						Name: "ESGeohashGridAggInput",
+						Fields: graphql.InputObjectConfigFieldMap{
+							"field":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+							"precision": &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Geohash length (1-12)"},
+							"size":      &graphql.InputObjectFieldConfig{Type: graphql.Int},
+						},
+					}),
+				},
+				"geotileGrid": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewInputObject(graphql.InputObjectConfig{
+						Name: "ESGeotileGridAggInput",
+						Fields: graphql.InputObjectConfigFieldMap{
+							"field":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+							"precision": &graphql.InputObjectFieldConfig{Type: graphql.Int, Description: "Tile zoom level (0-29)"},
+							"size":      &graphql.InputObjectFieldConfig{Type: graphql.Int},
+						},
+					}),
+				},
+				"geoCentroid": &graphql.InputObjectFieldConfig{
+					Type: graphql.NewInputObject(graphql.InputObjectConfig{
+						Name: "ESGeoCentroidAggInput",
+						Fields: graphql.InputObjectConfigFieldMap{
+							"field": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+						},
+					}),
+				},
 				"aggs": &graphql.InputObjectFieldConfig{
 					Type: graphql.NewList(esAggInputType),
 				},